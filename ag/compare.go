@@ -0,0 +1,46 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import "github.com/nlpodyssey/spago/mat"
+
+// Greater returns a mask set to 1 where x1 is greater than x2, and to 0
+// elsewhere. The operand x2 can also be a scalar.
+//
+// Masks are constants: they do not propagate gradients. They are
+// meant to be used with Where and MaskedFill.
+func Greater(x1, x2 mat.Tensor) mat.Tensor {
+	return x1.Value().(mat.Matrix).Greater(x2.Value().(mat.Matrix))
+}
+
+// GreaterEqual returns a mask set to 1 where x1 is greater than or equal to
+// x2, and to 0 elsewhere. The operand x2 can also be a scalar.
+func GreaterEqual(x1, x2 mat.Tensor) mat.Tensor {
+	return x1.Value().(mat.Matrix).GreaterEqual(x2.Value().(mat.Matrix))
+}
+
+// Less returns a mask set to 1 where x1 is less than x2, and to 0
+// elsewhere. The operand x2 can also be a scalar.
+func Less(x1, x2 mat.Tensor) mat.Tensor {
+	return x1.Value().(mat.Matrix).Less(x2.Value().(mat.Matrix))
+}
+
+// LessEqual returns a mask set to 1 where x1 is less than or equal to x2,
+// and to 0 elsewhere. The operand x2 can also be a scalar.
+func LessEqual(x1, x2 mat.Tensor) mat.Tensor {
+	return x1.Value().(mat.Matrix).LessEqual(x2.Value().(mat.Matrix))
+}
+
+// Equal returns a mask set to 1 where x1 is equal to x2, and to 0
+// elsewhere. The operand x2 can also be a scalar.
+func Equal(x1, x2 mat.Tensor) mat.Tensor {
+	return x1.Value().(mat.Matrix).Equal(x2.Value().(mat.Matrix))
+}
+
+// NotEqual returns a mask set to 1 where x1 differs from x2, and to 0
+// elsewhere. The operand x2 can also be a scalar.
+func NotEqual(x1, x2 mat.Tensor) mat.Tensor {
+	return x1.Value().(mat.Matrix).NotEqual(x2.Value().(mat.Matrix))
+}
@@ -20,6 +20,10 @@ var (
 	// forceSyncExecution, when set to true, forces operators to run synchronously, overriding any "async" flag in the Run() function.
 	// This can be particularly useful for debugging.
 	forceSyncExecution = false
+
+	// operatorSeq is the counter used to assign a creation sequence number
+	// to each new operator.
+	operatorSeq atomic.Uint64
 )
 
 // SetForceSyncExecution enables or disables the forcing of synchronous execution for all operators.
@@ -113,12 +117,15 @@ type Operator struct {
 	requiresGrad bool
 	// backwardState is the state of the backward pass.
 	backwardState backwardState
+	// seq is the creation sequence number of the operator: operators created
+	// later have higher numbers.
+	seq uint64
 }
 
 // NewOperator creates a new operator with the given AutoGradFunction.
 // Note that the operator's Value() can only be accessed after calling the Run() function.
 func NewOperator(f AutoGradFunction) *Operator {
-	return &Operator{fn: f}
+	return &Operator{fn: f, seq: operatorSeq.Add(1)}
 }
 
 // SetAt sets the value at the given indices.
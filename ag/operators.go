@@ -10,6 +10,7 @@ import (
 
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/gradfn"
+	"github.com/nlpodyssey/spago/mat/rand"
 )
 
 // Abs returns a new operator node as a result of the `Abs` function.
@@ -67,6 +68,21 @@ func Cos(x mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewCos(x)).Run()
 }
 
+// CumProd returns a new operator node as a result of the gradfn.CumProd function.
+func CumProd(x mat.Tensor) mat.Tensor {
+	return NewOperator(gradfn.NewCumProd(x)).Run()
+}
+
+// CumSum returns a new operator node as a result of the gradfn.CumSum function.
+func CumSum(x mat.Tensor) mat.Tensor {
+	return NewOperator(gradfn.NewCumSum(x)).Run()
+}
+
+// Digamma returns a new operator node as a result of the gradfn.Digamma function.
+func Digamma(x mat.Tensor) mat.Tensor {
+	return NewOperator(gradfn.NewDigamma(x)).Run()
+}
+
 // Div returns a new operator node as a result of the gradfn.Div function.
 func Div(x1, x2 mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewDiv(x1, x2)).Run()
@@ -85,16 +101,17 @@ func Dot(x1, x2 mat.Tensor) mat.Tensor {
 // DropoutFunc returns a function to create a Dropout operator working with the given dropout probability.
 func DropoutFunc(p float64) func(x mat.Tensor) mat.Tensor {
 	return func(x mat.Tensor) mat.Tensor {
-		if p == 0.0 {
-			return x
-		}
-		return NewOperator(gradfn.NewDropout(x, p, globalGenerator)).Run()
+		return Dropout(x, p)
 	}
 }
 
 // Dropout returns a new operator node as a result of the gradfn.Dropout function.
 // If the dropout probability is zero, the operator will not be created,
 // so the input itself is returned directly.
+//
+// The mask is drawn from the global generator (see Rand), so it depends on
+// the order of the concurrent draws: use DropoutWithStream for reproducible
+// masks.
 func Dropout(x mat.Tensor, p float64) mat.Tensor {
 	if p == 0.0 {
 		return x
@@ -102,11 +119,37 @@ func Dropout(x mat.Tensor, p float64) mat.Tensor {
 	return NewOperator(gradfn.NewDropout(x, p, globalGenerator)).Run()
 }
 
+// DropoutWithStream is like Dropout, drawing the mask from the given stream,
+// typically derived from Stream with a stable key. The same stream always
+// produces the same mask.
+func DropoutWithStream(x mat.Tensor, p float64, s *rand.Stream) mat.Tensor {
+	if p == 0.0 {
+		return x
+	}
+	return NewOperator(gradfn.NewDropout(x, p, s.Rand())).Run()
+}
+
 // ELU returns a new operator node as a result of the gradfn.ELU function.
 func ELU(x, alpha mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewELU(x, alpha)).Run()
 }
 
+// Einsum returns a new operator node as a result of the gradfn.Einsum function,
+// evaluating the Einstein summation convention on the operands
+// (e.g. "ij,jk->ik"). See mat.Einsum for the supported specifications.
+//
+// Since matrices are two-dimensional, batched specifications over
+// three-dimensional operands, such as "bij,bjk->bik", are rejected. Represent
+// the batch as a slice of matrices instead, and map the two-dimensional form
+// over it with Map2:
+//
+//	ys := Map2(func(a, b mat.Tensor) mat.Tensor {
+//		return Einsum("ij,jk->ik", a, b)
+//	}, as, bs)
+func Einsum(spec string, xs ...mat.Tensor) mat.Tensor {
+	return NewOperator(gradfn.NewEinsum(spec, xs)).Run()
+}
+
 // Exp returns a new operator node as a result of the `Exp` function.
 func Exp(x mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewExp(x)).Run()
@@ -117,6 +160,13 @@ func Flatten(x mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewFlatten(x)).Run()
 }
 
+// Gather returns a new operator node as a result of the gradfn.Gather function.
+// Each value of the output is taken from x along the given axis, at the
+// position given by the index with the same coordinates.
+func Gather(x mat.Tensor, axis int, index [][]int) mat.Tensor {
+	return NewOperator(gradfn.NewGather(x, axis, index)).Run()
+}
+
 // GELU returns a new operator node as a result of the gradfn.GELU function.
 func GELU(x mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewGELU(x)).Run()
@@ -137,6 +187,19 @@ func Copy(x mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewCopy(x)).Run()
 }
 
+// IndexSelect returns a new operator node as a result of the gradfn.IndexSelect function.
+// It selects the rows (axis 0) or the columns (axis 1) of x at the given indices.
+func IndexSelect(x mat.Tensor, axis int, indices ...int) mat.Tensor {
+	return NewOperator(gradfn.NewIndexSelect(x, axis, indices)).Run()
+}
+
+// KMaxPooling returns, for each row of x, its k largest values in their
+// original order. The gradients flow back to the selected positions only.
+func KMaxPooling(x mat.Tensor, k int) mat.Tensor {
+	_, index := x.Value().(mat.Matrix).KMaxPooling(k)
+	return Gather(x, 1, index)
+}
+
 // LeakyReLU returns a new operator node as a result of the gradfn.LeakyReLU function.
 func LeakyReLU(x, alpha mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewLeakyReLU(x, alpha)).Run()
@@ -147,6 +210,23 @@ func Log(x mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewLog(x)).Run()
 }
 
+// LogGamma returns a new operator node as a result of the gradfn.LogGamma function.
+func LogGamma(x mat.Tensor) mat.Tensor {
+	return NewOperator(gradfn.NewLogGamma(x)).Run()
+}
+
+// LogCumSumExp returns a new operator node as a result of the gradfn.LogCumSumExp function.
+func LogCumSumExp(x mat.Tensor) mat.Tensor {
+	return NewOperator(gradfn.NewLogCumSumExp(x)).Run()
+}
+
+// MaskedFill returns a new operator node as a result of the gradfn.MaskedFill function.
+// It replaces the values of x with the given value where the mask is non-zero;
+// the gradients flow back to the other positions only.
+func MaskedFill(x, mask mat.Tensor, value float64) mat.Tensor {
+	return NewOperator(gradfn.NewMaskedFill(x, mask, value)).Run()
+}
+
 // Max returns a new operator node as a result of the gradfn.Max function.
 func Max(x1, x2 mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewMax(x1, x2)).Run()
@@ -252,6 +332,13 @@ func ScalarMax(xs []mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewScalarMax(xs)).Run()
 }
 
+// ScatterAdd returns a new operator node as a result of the gradfn.ScatterAdd function.
+// It adds the values of src to a copy of x along the given axis, at the
+// positions given by the index with the same coordinates.
+func ScatterAdd(x mat.Tensor, axis int, index [][]int, src mat.Tensor) mat.Tensor {
+	return NewOperator(gradfn.NewScatterAdd(x, axis, index, src)).Run()
+}
+
 // SELU returns a new operator node as a result of the gradfn.SELU function.
 func SELU(x, alpha mat.Tensor, scale mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewSELU(x, alpha, scale)).Run()
@@ -357,11 +444,30 @@ func Tanh(x mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewTanh(x)).Run()
 }
 
+// TopK returns the k largest values of the vector x, in descending order,
+// and their indices. The gradients of the values flow back to the selected
+// positions only.
+func TopK(x mat.Tensor, k int) (mat.Tensor, []int) {
+	_, indices := x.Value().(mat.Matrix).TopK(k)
+	axis := 0
+	if x.Shape()[0] == 1 {
+		axis = 1
+	}
+	return IndexSelect(x, axis, indices...), indices
+}
+
 // Threshold returns a new operator node as a result of the gradfn.Threshold function.
 func Threshold(x, threshold, k mat.Tensor) mat.Tensor {
 	return NewOperator(gradfn.NewThreshold(x, threshold, k)).Run()
 }
 
+// Where returns a new operator node as a result of the gradfn.Where function.
+// It takes the values of x1 where the condition is non-zero, and the values of
+// x2 elsewhere; the gradients flow back to the selected operand only.
+func Where(cond, x1, x2 mat.Tensor) mat.Tensor {
+	return NewOperator(gradfn.NewWhere(cond, x1, x2)).Run()
+}
+
 // Map returns a transformed version of xs with all its components modified according to the mapping function.
 // It is useful for applying an operator to a sequence of nodes. Keep in mind that using this function has an overhead
 // because of the callback, however insignificant compared to mathematical computations.
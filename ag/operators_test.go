@@ -10,6 +10,7 @@ import (
 
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -175,3 +176,102 @@ func testColViews[T float.DType](t *testing.T) {
 func newScalar[T float.DType](v T) mat.Tensor {
 	return mat.Scalar(v)
 }
+
+func TestTopK(t *testing.T) {
+	t.Run("float32", testTopK[float32])
+	t.Run("float64", testTopK[float64])
+}
+
+func testTopK[T float.DType](t *testing.T) {
+	x := mat.NewDense[T](mat.WithShape(1, 4), mat.WithBacking([]T{0.1, 0.7, -0.2, 0.4}), mat.WithGrad(true))
+
+	y, indices := TopK(x, 2)
+	assert.Equal(t, []int{1, 3}, indices)
+	assert.Equal(t, []int{1, 2}, y.Shape())
+	assert.InDeltaSlice(t, []T{0.7, 0.4}, y.Value().Data(), 1.0e-6)
+
+	y.AccGrad(mat.NewDense[T](mat.WithShape(1, 2), mat.WithBacking([]T{1, 2})))
+	assert.NoError(t, Backward(y))
+	assert.InDeltaSlice(t, []T{0, 1, 0, 2}, x.Grad().Data(), 1.0e-6)
+}
+
+func TestKMaxPooling(t *testing.T) {
+	t.Run("float32", testKMaxPooling[float32])
+	t.Run("float64", testKMaxPooling[float64])
+}
+
+func testKMaxPooling[T float.DType](t *testing.T) {
+	x := mat.NewDense[T](mat.WithShape(2, 4), mat.WithBacking([]T{
+		0.1, 0.7, -0.2, 0.4,
+		0.5, 0.3, 0.6, 0.2,
+	}), mat.WithGrad(true))
+
+	y := KMaxPooling(x, 2)
+	assert.InDeltaSlice(t, []T{
+		0.7, 0.4,
+		0.5, 0.6,
+	}, y.Value().Data(), 1.0e-6)
+
+	y.AccGrad(mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{
+		1, 2,
+		3, 4,
+	})))
+	assert.NoError(t, Backward(y))
+	assert.InDeltaSlice(t, []T{
+		0, 1, 0, 2,
+		3, 0, 4, 0,
+	}, x.Grad().Data(), 1.0e-6)
+}
+
+func TestEinsum_Batched(t *testing.T) {
+	t.Run("float32", testEinsumBatched[float32])
+	t.Run("float64", testEinsumBatched[float64])
+}
+
+func testEinsumBatched[T float.DType](t *testing.T) {
+	as := []mat.Tensor{
+		mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{1, 2, 3, 4}), mat.WithGrad(true)),
+		mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{0, 1, 1, 0}), mat.WithGrad(true)),
+	}
+	bs := []mat.Tensor{
+		mat.NewDense[T](mat.WithShape(2, 1), mat.WithBacking([]T{1, 1}), mat.WithGrad(true)),
+		mat.NewDense[T](mat.WithShape(2, 1), mat.WithBacking([]T{2, 3}), mat.WithGrad(true)),
+	}
+
+	assert.Panics(t, func() { Einsum("bij,bjk->bik", as[0], bs[0]) })
+
+	// "bij,bjk->bik" over a batch of two items
+	ys := Map2(func(a, b mat.Tensor) mat.Tensor {
+		return Einsum("ij,jk->ik", a, b)
+	}, as, bs)
+	assert.InDeltaSlice(t, []T{3, 7}, ys[0].Value().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{3, 2}, ys[1].Value().Data(), 1.0e-6)
+
+	assert.NoError(t, Backward(Add(ReduceSum(ys[0]), ReduceSum(ys[1]))))
+	assert.InDeltaSlice(t, []T{1, 1, 1, 1}, as[0].Grad().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{2, 3, 2, 3}, as[1].Grad().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{4, 6}, bs[0].Grad().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{1, 1}, bs[1].Grad().Data(), 1.0e-6)
+}
+
+func TestDropout_Reproducible(t *testing.T) {
+	x := mat.NewDense[float64](mat.WithShape(100), mat.WithBacking(make([]float64, 100))).OnesLike()
+
+	ManualSeed(42)
+	s := Stream().Split("layer")
+	y1 := DropoutWithStream(x, 0.5, s.SplitN(0))
+	y2 := DropoutWithStream(x, 0.5, s.SplitN(1))
+
+	// the masks do not depend on the order of the draws
+	ManualSeed(42)
+	z2 := DropoutWithStream(x, 0.5, Stream().Split("layer").SplitN(1))
+	z1 := DropoutWithStream(x, 0.5, Stream().Split("layer").SplitN(0))
+
+	assert.Equal(t, y1.Value().Data(), z1.Value().Data())
+	assert.Equal(t, y2.Value().Data(), z2.Value().Data())
+	assert.NotEqual(t, y1.Value().Data(), y2.Value().Data())
+
+	w1 := DropoutWithStream(x, 0.5, rand.NewStream(1).Split("layer"))
+	w2 := DropoutWithStream(x, 0.5, rand.NewStream(1).Split("layer"))
+	assert.Equal(t, w1.Value().Data(), w2.Value().Data())
+}
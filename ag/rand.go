@@ -5,6 +5,7 @@
 package ag
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/nlpodyssey/spago/mat/rand"
@@ -12,15 +13,23 @@ import (
 
 var globalGenerator = rand.NewLockedRand(12345)
 
+var globalStream atomic.Pointer[rand.Stream]
+
+func init() {
+	globalStream.Store(rand.NewStream(12345))
+}
+
 // Seed sets the seed for generating random numbers to the current time (converted to uint64).
 func Seed() *rand.LockedRand {
-	globalGenerator.Seed(uint64(time.Now().UnixNano()))
-	return globalGenerator
+	return ManualSeed(uint64(time.Now().UnixNano()))
 }
 
 // ManualSeed sets the seed for generating random numbers.
+//
+// It also resets the root stream returned by Stream.
 func ManualSeed(seed uint64) *rand.LockedRand {
 	globalGenerator.Seed(seed)
+	globalStream.Store(rand.NewStream(seed))
 	return globalGenerator
 }
 
@@ -28,3 +37,13 @@ func ManualSeed(seed uint64) *rand.LockedRand {
 func Rand() *rand.LockedRand {
 	return globalGenerator
 }
+
+// Stream returns the root stream, seeded by Seed or ManualSeed.
+//
+// Unlike the global generator returned by Rand, whose numbers are drawn in
+// the order in which goroutines happen to ask for them, the sub-streams
+// derived from a stable key are reproducible regardless of scheduling, e.g.
+// Stream().Split("Layers.0").SplitN(step) for a layer at a training step.
+func Stream() *rand.Stream {
+	return globalStream.Load()
+}
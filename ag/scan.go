@@ -0,0 +1,139 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// ScanFunc computes the next carry of a scan, given the previous carry and
+// the current input. It must have the same behavior at each invocation.
+type ScanFunc func(carry, x mat.Tensor) mat.Tensor
+
+// Scan applies fn along the sequence xs, threading a carry from init:
+//
+//	carry[t] = fn(carry[t-1], xs[t]), with carry[-1] = init
+//
+// It returns a single operator node, whose value is a len(xs)×size matrix,
+// where row t contains the flattened carry[t]. All the carries must have
+// the same size.
+//
+// The whole scan is a single node of the graph: the intermediate operations
+// performed by fn are not retained. During the backward pass, each step is
+// recomputed in isolation, in reverse order, to propagate the gradients to
+// init, to xs, and to the parameters captured by fn. For this reason, fn
+// may only capture leaves of the graph, such as model parameters, or tensors
+// whose gradients are stopped with StopGrad. Any other tensor depending on
+// the rest of the graph must be passed through init or xs: Scan panics if
+// fn captures a non-leaf tensor requiring gradients, since the recomputed
+// steps would propagate partial gradients into the outer graph.
+//
+// Scan can express linear recurrences, such as the ones of state-space
+// layers, and dynamic programs over long sequences, without growing the
+// graph by several operators per step.
+func Scan(fn ScanFunc, init mat.Tensor, xs []mat.Tensor) mat.Tensor {
+	return NewOperator(&scan{fn: fn, init: init, xs: xs}).Run()
+}
+
+// scan is the AutoGradFunction implementing Scan.
+type scan struct {
+	fn   ScanFunc
+	init mat.Tensor
+	xs   []mat.Tensor
+	// carries holds the input carry of each step, set during the forward pass.
+	carries []mat.Matrix
+}
+
+// Operands returns the list of operands.
+func (s *scan) Operands() []mat.Tensor {
+	return append([]mat.Tensor{s.init}, s.xs...)
+}
+
+// Forward computes the output of the function.
+func (s *scan) Forward() (mat.Tensor, error) {
+	carry := s.init.Value().(mat.Matrix)
+	size := carry.Size()
+	s.carries = make([]mat.Matrix, len(s.xs))
+	rows := make([]mat.Matrix, len(s.xs))
+	for t, x := range s.xs {
+		s.carries[t] = carry
+		start := operatorSeq.Load()
+		y := s.fn(carry, x.Value())
+		checkScanCaptures(y, start)
+		carry = y.Value().(mat.Matrix)
+		if carry.Size() != size {
+			return nil, fmt.Errorf("ag: scan carry size changed from %d to %d at step %d", size, carry.Size(), t)
+		}
+		rows[t] = carry.Flatten()
+	}
+	if len(rows) == 0 {
+		return carry.NewMatrix(mat.WithShape(0, size)), nil
+	}
+	return carry.NewStack(rows...), nil
+}
+
+// Backward computes the backward pass.
+func (s *scan) Backward(gy mat.Tensor) error {
+	if gy.Shape()[0] != len(s.xs) {
+		return fmt.Errorf("ag: scan gradients with not compatible size")
+	}
+	g := gy.(mat.Matrix)
+	var gCarry mat.Matrix // gradient flowing back from the following steps
+	for t := len(s.xs) - 1; t >= 0; t-- {
+		carry := s.carries[t].Clone()
+		carry.SetRequiresGrad(true)
+		x := s.xs[t].Value().(mat.Matrix).Clone()
+		x.SetRequiresGrad(s.xs[t].RequiresGrad())
+
+		y := s.fn(carry, x)
+		gyt := g.ExtractRow(t).ReshapeInPlace(y.Shape()...)
+		if gCarry != nil {
+			gyt.AddInPlace(gCarry.ReshapeInPlace(y.Shape()...))
+		}
+		y.AccGrad(gyt)
+		if err := Backward(y); err != nil {
+			return err
+		}
+
+		gCarry = nil
+		if carry.HasGrad() {
+			gCarry = carry.Grad().(mat.Matrix)
+		}
+		if x.HasGrad() {
+			s.xs[t].AccGrad(x.Grad())
+		}
+	}
+	if gCarry != nil && s.init.RequiresGrad() {
+		s.init.AccGrad(gCarry.ReshapeInPlace(s.init.Shape()...))
+	}
+	return nil
+}
+
+// checkScanCaptures panics if y, the output of a scan step, depends on an
+// operator requiring gradients that was created before the step started,
+// that is, on a non-leaf tensor captured by the scan function.
+func checkScanCaptures(y mat.Tensor, start uint64) {
+	visited := make(map[*Operator]struct{})
+	var visit func(t mat.Tensor)
+	visit = func(t mat.Tensor) {
+		op, ok := t.(*Operator)
+		if !ok || !op.RequiresGrad() {
+			return
+		}
+		if _, ok := visited[op]; ok {
+			return
+		}
+		visited[op] = struct{}{}
+		if op.seq <= start {
+			panic("ag: scan function captures a non-leaf tensor requiring gradients: pass it through init or xs, or use StopGrad")
+		}
+		for _, operand := range op.Operands() {
+			visit(operand)
+		}
+	}
+	visit(y)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	t.Run("float32", testScan[float32])
+	t.Run("float64", testScan[float64])
+}
+
+func testScan[T float.DType](t *testing.T) {
+	newInputs := func() (a, h0 *mat.Dense[T], xs []mat.Tensor) {
+		a = mat.NewDense[T](mat.WithShape(2), mat.WithBacking([]T{0.5, -0.8}), mat.WithGrad(true))
+		h0 = mat.NewDense[T](mat.WithShape(2), mat.WithBacking([]T{0.1, 0.2}), mat.WithGrad(true))
+		xs = []mat.Tensor{
+			mat.NewDense[T](mat.WithShape(2), mat.WithBacking([]T{1, 2}), mat.WithGrad(true)),
+			mat.NewDense[T](mat.WithShape(2), mat.WithBacking([]T{-1, 0.5}), mat.WithGrad(true)),
+			mat.NewDense[T](mat.WithShape(2), mat.WithBacking([]T{0.3, -0.4}), mat.WithGrad(true)),
+		}
+		return
+	}
+
+	// A linear recurrence with a captured parameter: h[t] = tanh(a * h[t-1] + x[t]).
+	step := func(a mat.Tensor) ScanFunc {
+		return func(h, x mat.Tensor) mat.Tensor {
+			return Tanh(Add(Prod(a, h), x))
+		}
+	}
+
+	// Reference: the explicitly unrolled graph.
+	refA, refH0, refXs := newInputs()
+	h := mat.Tensor(refH0)
+	var refYs []mat.Tensor
+	for _, x := range refXs {
+		h = step(refA)(h, x)
+		refYs = append(refYs, h)
+	}
+	sumOfSquares := func(x mat.Tensor) mat.Tensor {
+		x = Reshape(x, x.Size(), 1)
+		return ReduceSum(Prod(x, x))
+	}
+	refLoss := sumOfSquares(Stack(refYs...))
+	assert.NoError(t, Backward(refLoss))
+
+	a, h0, xs := newInputs()
+	ys := Scan(step(a), h0, xs)
+	assert.Equal(t, []int{3, 2}, ys.Shape())
+	assert.InDeltaSlice(t, Stack(refYs...).Value().Data().F64(), ys.Value().Data().F64(), 1.0e-6)
+
+	loss := sumOfSquares(ys)
+	assert.NoError(t, Backward(loss))
+
+	assert.InDeltaSlice(t, refA.Grad().Data().F64(), a.Grad().Data().F64(), 1.0e-5)
+	assert.InDeltaSlice(t, refH0.Grad().Data().F64(), h0.Grad().Data().F64(), 1.0e-5)
+	for i := range xs {
+		assert.InDeltaSlice(t, refXs[i].Grad().Data().F64(), xs[i].Grad().Data().F64(), 1.0e-5)
+	}
+}
+
+func TestScan_CapturedNonLeaf(t *testing.T) {
+	a := mat.NewDense[float64](mat.WithShape(2), mat.WithBacking([]float64{0.5, -0.8}), mat.WithGrad(true))
+	h0 := mat.NewDense[float64](mat.WithShape(2), mat.WithBacking([]float64{0.1, 0.2}), mat.WithGrad(true))
+	xs := []mat.Tensor{
+		mat.NewDense[float64](mat.WithShape(2), mat.WithBacking([]float64{1, 2})),
+		mat.NewDense[float64](mat.WithShape(2), mat.WithBacking([]float64{-1, 0.5})),
+	}
+	b := Prod(a, a) // a non-leaf tensor of the outer graph
+
+	t.Run("requiring gradients", func(t *testing.T) {
+		assert.PanicsWithValue(t,
+			"ag: scan function captures a non-leaf tensor requiring gradients: pass it through init or xs, or use StopGrad",
+			func() {
+				Scan(func(h, x mat.Tensor) mat.Tensor {
+					return Add(Prod(b, h), x)
+				}, h0, xs)
+			})
+	})
+
+	t.Run("with stopped gradients", func(t *testing.T) {
+		sb := StopGrad(b)
+		ys := Scan(func(h, x mat.Tensor) mat.Tensor {
+			return Add(Prod(sb, h), x)
+		}, h0, xs)
+		assert.NoError(t, Backward(ReduceSum(Reshape(ys, ys.Size(), 1))))
+		assert.False(t, a.HasGrad())
+		assert.True(t, h0.HasGrad())
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distributions
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+var _ Distribution = &Bernoulli{}
+
+// Bernoulli is a batch of independent Bernoulli distributions,
+// parameterized by the logits of the probabilities of success.
+// Being discrete, it has no reparameterized sampling.
+type Bernoulli struct {
+	Logits mat.Tensor
+}
+
+// NewBernoulli returns a new Bernoulli from the given logits.
+func NewBernoulli(logits mat.Tensor) *Bernoulli {
+	return &Bernoulli{Logits: logits}
+}
+
+// Probs returns the element-wise probabilities of success.
+func (d *Bernoulli) Probs() mat.Tensor {
+	return ag.Sigmoid(d.Logits)
+}
+
+// Sample draws a matrix of zeros and ones from the distribution.
+func (d *Bernoulli) Sample(generator *rand.LockedRand) mat.Tensor {
+	return sampleLike(d.Logits, func(logit float64) float64 {
+		if generator.Float64() < 1/(1+math.Exp(-logit)) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// LogProb returns the element-wise log-probability of the value,
+// computed as value * logits - softplus(logits).
+func (d *Bernoulli) LogProb(value mat.Tensor) mat.Tensor {
+	return ag.Sub(ag.Prod(value, d.Logits), softPlus(d.Logits))
+}
+
+// Entropy returns the element-wise entropy.
+func (d *Bernoulli) Entropy() mat.Tensor {
+	return ag.Sub(softPlus(d.Logits), ag.Prod(d.Probs(), d.Logits))
+}
+
+// KL returns the element-wise KL divergence from another Bernoulli.
+func (d *Bernoulli) KL(other Distribution) mat.Tensor {
+	q, ok := other.(*Bernoulli)
+	if !ok {
+		panic(mismatch(d, other))
+	}
+	p := d.Probs()
+	// log(p) = -softplus(-logits), log(1-p) = -softplus(logits)
+	pos := ag.Sub(softPlus(ag.Neg(q.Logits)), softPlus(ag.Neg(d.Logits)))
+	neg := ag.Sub(softPlus(q.Logits), softPlus(d.Logits))
+	return ag.Add(ag.Prod(p, pos), ag.Prod(ag.ReverseSubOne(p), neg))
+}
+
+func softPlus(x mat.Tensor) mat.Tensor {
+	return ag.SoftPlus(x, scalar(x, 1), scalar(x, 20))
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distributions
+
+import (
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/mat/rand/beta"
+)
+
+var _ Reparameterized = &Beta{}
+
+// Beta is a batch of independent Beta distributions, one for each element
+// of the concentrations Alpha and Beta.
+type Beta struct {
+	Alpha mat.Tensor
+	Beta  mat.Tensor
+}
+
+// NewBeta returns a new Beta with the given positive concentrations.
+// The two tensors must have the same shape.
+func NewBeta(alpha, beta mat.Tensor) *Beta {
+	if !mat.SameDims(alpha.Value(), beta.Value()) {
+		panic("distributions: alpha and beta have incompatible dimensions")
+	}
+	return &Beta{Alpha: alpha, Beta: beta}
+}
+
+// Sample draws a value from the distribution.
+func (d *Beta) Sample(generator *rand.LockedRand) mat.Tensor {
+	return zip(d.Alpha, d.Beta, func(a, b float64) float64 {
+		return beta.New(a, b, generator).Next()
+	})
+}
+
+// RSample draws a value as X / (X + Y), with X ~ Gamma(Alpha, 1) and
+// Y ~ Gamma(Beta, 1), whose gradients are computed with the implicit
+// reparameterization of the Gamma samples.
+func (d *Beta) RSample(generator *rand.LockedRand) mat.Tensor {
+	x := rsampleGamma(d.Alpha, generator)
+	y := rsampleGamma(d.Beta, generator)
+	return ag.Div(x, ag.Add(x, y))
+}
+
+// LogProb returns the element-wise log-density of the value.
+func (d *Beta) LogProb(value mat.Tensor) mat.Tensor {
+	one := scalar(value, 1)
+	x := ag.Prod(ag.SubScalar(d.Alpha, one), ag.Log(value))
+	y := ag.Prod(ag.SubScalar(d.Beta, one), ag.Log(ag.ReverseSubOne(value)))
+	return ag.Sub(ag.Add(x, y), lnBeta(d.Alpha, d.Beta))
+}
+
+// Entropy returns the element-wise entropy.
+func (d *Beta) Entropy() mat.Tensor {
+	one := scalar(d.Alpha, 1)
+	total := ag.Add(d.Alpha, d.Beta)
+	a := ag.Prod(ag.SubScalar(d.Alpha, one), ag.Digamma(d.Alpha))
+	b := ag.Prod(ag.SubScalar(d.Beta, one), ag.Digamma(d.Beta))
+	t := ag.Prod(ag.SubScalar(total, scalar(total, 2)), ag.Digamma(total))
+	return ag.Add(ag.Sub(ag.Sub(lnBeta(d.Alpha, d.Beta), a), b), t)
+}
+
+// KL returns the element-wise KL divergence from another Beta.
+func (d *Beta) KL(other Distribution) mat.Tensor {
+	q, ok := other.(*Beta)
+	if !ok {
+		panic(mismatch(d, other))
+	}
+	dgTotal := ag.Digamma(ag.Add(d.Alpha, d.Beta))
+	a := ag.Prod(ag.Sub(d.Alpha, q.Alpha), ag.Sub(ag.Digamma(d.Alpha), dgTotal))
+	b := ag.Prod(ag.Sub(d.Beta, q.Beta), ag.Sub(ag.Digamma(d.Beta), dgTotal))
+	return ag.Add(ag.Sub(lnBeta(q.Alpha, q.Beta), lnBeta(d.Alpha, d.Beta)), ag.Add(a, b))
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distributions
+
+import (
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/mat/rand/categorical"
+	"github.com/nlpodyssey/spago/mat/rand/gumbel"
+)
+
+var _ Distribution = &Categorical{}
+
+// Categorical is a distribution over the indices of a vector of
+// unnormalized log-probabilities.
+//
+// Being discrete, it has no reparameterized sampling, and it does not
+// implement Reparameterized. RelaxedSample provides a differentiable
+// continuous relaxation of its samples instead.
+type Categorical struct {
+	Logits mat.Tensor
+	// LogProbs are the normalized log-probabilities.
+	LogProbs mat.Tensor
+}
+
+// NewCategorical returns a new Categorical from a column vector of logits.
+func NewCategorical(logits mat.Tensor) *Categorical {
+	if !mat.IsVector(logits.Value()) {
+		panic("distributions: logits must be a vector")
+	}
+	return &Categorical{
+		Logits:   logits,
+		LogProbs: ag.LogSoftmax(logits),
+	}
+}
+
+// Probs returns the probability of each index.
+func (d *Categorical) Probs() mat.Tensor {
+	return ag.Exp(d.LogProbs)
+}
+
+// Sample draws an index from the distribution, returned as a scalar.
+func (d *Categorical) Sample(generator *rand.LockedRand) mat.Tensor {
+	i := categorical.New(d.Logits.Value().Data().F64(), generator).Next()
+	return scalar(d.Logits, float64(i))
+}
+
+// RelaxedSample draws a vector from the Gumbel-softmax (or Concrete)
+// relaxation of the distribution, softmax((Logits + G) / temperature), with
+// G_i ~ Gumbel(0, 1). Its gradients flow back to the logits.
+//
+// The result is a point of the probability simplex approaching the one-hot
+// encoding of a sample as the temperature goes to zero, so it is not a value
+// in the support of the distribution, and LogProb does not apply to it.
+func (d *Categorical) RelaxedSample(temperature float64, generator *rand.LockedRand) mat.Tensor {
+	if temperature <= 0 {
+		panic("distributions: temperature must be positive")
+	}
+	g := gumbel.New(0, 1, generator)
+	noise := sampleLike(d.Logits, func(float64) float64 { return g.Next() })
+	x := ag.Add(d.Logits, noise)
+	return ag.Softmax(ag.ProdScalar(x, scalar(x, 1/temperature)))
+}
+
+// LogProb returns the log-probability of the index held by the scalar value.
+func (d *Categorical) LogProb(value mat.Tensor) mat.Tensor {
+	return ag.At(d.LogProbs, int(value.Value().Item().F64()))
+}
+
+// Entropy returns the entropy, as a scalar.
+func (d *Categorical) Entropy() mat.Tensor {
+	return ag.Neg(ag.ReduceSum(ag.Prod(d.Probs(), d.LogProbs)))
+}
+
+// KL returns the KL divergence from another Categorical, as a scalar.
+func (d *Categorical) KL(other Distribution) mat.Tensor {
+	q, ok := other.(*Categorical)
+	if !ok {
+		panic(mismatch(d, other))
+	}
+	return ag.ReduceSum(ag.Prod(d.Probs(), ag.Sub(d.LogProbs, q.LogProbs)))
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distributions
+
+import (
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/mat/rand/dirichlet"
+)
+
+var _ Reparameterized = &Dirichlet{}
+
+// Dirichlet is a Dirichlet distribution parameterized by a vector of
+// positive concentrations.
+type Dirichlet struct {
+	Alpha mat.Tensor
+}
+
+// NewDirichlet returns a new Dirichlet from a column vector of concentrations.
+func NewDirichlet(alpha mat.Tensor) *Dirichlet {
+	if !mat.IsVector(alpha.Value()) {
+		panic("distributions: alpha must be a vector")
+	}
+	return &Dirichlet{Alpha: alpha}
+}
+
+// Sample draws a vector from the distribution.
+func (d *Dirichlet) Sample(generator *rand.LockedRand) mat.Tensor {
+	v := dirichlet.New(d.Alpha.Value().Data().F64(), generator).Next()
+	m := d.Alpha.Value().(mat.Matrix)
+	return m.NewMatrix(mat.WithShape(m.Shape()...), mat.WithBacking(v))
+}
+
+// RSample draws a vector as X / sum(X), with X_i ~ Gamma(Alpha_i, 1), whose
+// gradients are computed with the implicit reparameterization of the Gamma
+// samples.
+func (d *Dirichlet) RSample(generator *rand.LockedRand) mat.Tensor {
+	x := rsampleGamma(d.Alpha, generator)
+	return ag.DivScalar(x, ag.ReduceSum(x))
+}
+
+// LogProb returns the log-density of the vector, as a scalar.
+func (d *Dirichlet) LogProb(value mat.Tensor) mat.Tensor {
+	x := ag.ReduceSum(ag.Prod(ag.SubScalar(d.Alpha, scalar(value, 1)), ag.Log(value)))
+	return ag.Sub(x, d.lnBeta())
+}
+
+// Entropy returns the entropy, as a scalar.
+func (d *Dirichlet) Entropy() mat.Tensor {
+	total := ag.ReduceSum(d.Alpha)
+	k := float64(d.Alpha.Value().Size())
+	t := ag.Prod(ag.SubScalar(total, scalar(total, k)), ag.Digamma(total))
+	a := ag.ReduceSum(ag.Prod(ag.SubScalar(d.Alpha, scalar(total, 1)), ag.Digamma(d.Alpha)))
+	return ag.Sub(ag.Add(d.lnBeta(), t), a)
+}
+
+// KL returns the KL divergence from another Dirichlet, as a scalar.
+func (d *Dirichlet) KL(other Distribution) mat.Tensor {
+	q, ok := other.(*Dirichlet)
+	if !ok {
+		panic(mismatch(d, other))
+	}
+	dg := ag.SubScalar(ag.Digamma(d.Alpha), ag.Digamma(ag.ReduceSum(d.Alpha)))
+	x := ag.ReduceSum(ag.Prod(ag.Sub(d.Alpha, q.Alpha), dg))
+	return ag.Add(ag.Sub(q.lnBeta(), d.lnBeta()), x)
+}
+
+// lnBeta returns the logarithm of the normalizing constant.
+func (d *Dirichlet) lnBeta() mat.Tensor {
+	return ag.Sub(ag.ReduceSum(ag.LogGamma(d.Alpha)), ag.LogGamma(ag.ReduceSum(d.Alpha)))
+}
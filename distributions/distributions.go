@@ -0,0 +1,92 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package distributions provides probability distributions whose parameters
+// are nodes of the computational graph, so that log-probabilities, entropies
+// and KL divergences can be differentiated with respect to them.
+package distributions
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+// Distribution is a probability distribution parameterized by tensors.
+type Distribution interface {
+	// Sample draws a value from the distribution. The result is detached
+	// from the graph, so no gradient flows back to the parameters.
+	Sample(generator *rand.LockedRand) mat.Tensor
+	// LogProb returns the log-probability (or log-density) of the value.
+	LogProb(value mat.Tensor) mat.Tensor
+	// Entropy returns the entropy of the distribution.
+	Entropy() mat.Tensor
+	// KL returns the Kullback-Leibler divergence KL(d || other).
+	// It panics if other is not of the same type of the receiver.
+	KL(other Distribution) mat.Tensor
+}
+
+// Reparameterized is a Distribution supporting the reparameterization trick.
+type Reparameterized interface {
+	Distribution
+	// RSample draws a value from the distribution as a differentiable
+	// function of the parameters and of an independent noise.
+	RSample(generator *rand.LockedRand) mat.Tensor
+}
+
+// KL returns the Kullback-Leibler divergence KL(p || q).
+func KL(p, q Distribution) mat.Tensor {
+	return p.KL(q)
+}
+
+var halfLog2Pi = 0.5 * math.Log(2*math.Pi)
+
+// scalar returns a new constant scalar of the same type of x.
+func scalar(x mat.Tensor, v float64) mat.Tensor {
+	return x.Value().(mat.Matrix).NewScalar(v)
+}
+
+// sampleLike returns a new constant matrix with the same shape and type of x,
+// whose elements are given by next(v), v being the corresponding element of x.
+func sampleLike(x mat.Tensor, next func(v float64) float64) mat.Matrix {
+	m := x.Value().(mat.Matrix)
+	data := m.Data().F64()
+	out := make([]float64, len(data))
+	for i, v := range data {
+		out[i] = next(v)
+	}
+	return m.NewMatrix(mat.WithShape(m.Shape()...), mat.WithBacking(out))
+}
+
+// zip returns a new constant matrix with the same shape and type of x,
+// whose elements are given by next(a, b), a and b being the corresponding
+// elements of x and y.
+func zip(x, y mat.Tensor, next func(a, b float64) float64) mat.Matrix {
+	if !mat.SameDims(x.Value(), y.Value()) {
+		panic("distributions: parameters have incompatible dimensions")
+	}
+	ys := y.Value().Data().F64()
+	i := -1
+	return sampleLike(x, func(a float64) float64 {
+		i++
+		return next(a, ys[i])
+	})
+}
+
+// lnBeta returns the logarithm of the multivariate Beta function of the
+// concentrations, that is sum(lgamma(a)) - lgamma(sum(a)).
+func lnBeta(concentrations ...mat.Tensor) mat.Tensor {
+	lg := make([]mat.Tensor, len(concentrations))
+	for i, a := range concentrations {
+		lg[i] = ag.LogGamma(a)
+	}
+	return ag.Sub(ag.Sum(lg...), ag.LogGamma(ag.Sum(concentrations...)))
+}
+
+func mismatch(d, other Distribution) string {
+	return fmt.Sprintf("distributions: KL between %T and %T is not supported", d, other)
+}
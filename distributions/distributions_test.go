@@ -0,0 +1,273 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distributions
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/stretchr/testify/assert"
+)
+
+const numSamples = 20_000
+
+func vec(data ...float64) mat.Matrix {
+	return mat.NewDense[float64](mat.WithBacking(data), mat.WithGrad(true))
+}
+
+func TestNormal(t *testing.T) {
+	d := NewNormal(vec(0, 1), vec(1, 2))
+
+	logProb := d.LogProb(vec(0.5, -1))
+	assert.InDeltaSlice(t, []float64{-1.0439385, -2.1120857}, logProb.Value().Data().F64(), 1.0e-6)
+
+	entropy := d.Entropy()
+	assert.InDeltaSlice(t, []float64{1.4189385, 2.1120857}, entropy.Value().Data().F64(), 1.0e-6)
+
+	kl := d.KL(NewNormal(vec(1, 1), vec(2, 1)))
+	assert.InDeltaSlice(t, []float64{0.4431472, 0.8068528}, kl.Value().Data().F64(), 1.0e-6)
+}
+
+func TestNormal_RSample(t *testing.T) {
+	loc, scale := vec(0, 1, 2), vec(1, 2, 3)
+	d := NewNormal(loc, scale)
+
+	y := d.RSample(rand.NewLockedRand(42))
+	assert.Nil(t, ag.Backward(ag.ReduceSum(y)))
+
+	eps := rand.NewLockedRand(42)
+	expected := make([]float64, 3)
+	for i := range expected {
+		expected[i] = eps.NormFloat64()
+	}
+	assert.InDeltaSlice(t, []float64{1, 1, 1}, loc.Grad().Data().F64(), 1.0e-12)
+	assert.InDeltaSlice(t, expected, scale.Grad().Data().F64(), 1.0e-12)
+
+	s := d.Sample(rand.NewLockedRand(42))
+	assert.False(t, s.RequiresGrad())
+	assert.InDeltaSlice(t, y.Value().Data().F64(), s.Value().Data().F64(), 1.0e-12)
+}
+
+func TestMultivariateNormalDiag(t *testing.T) {
+	p := NewMultivariateNormalDiag(vec(0, 1), vec(1, 2))
+	q := NewMultivariateNormalDiag(vec(1, 1), vec(2, 1))
+	assert.InDelta(t, -1.0439385-2.1120857, p.LogProb(vec(0.5, -1)).Value().Item().F64(), 1.0e-6)
+	assert.InDelta(t, 1.4189385+2.1120857, p.Entropy().Value().Item().F64(), 1.0e-6)
+	assert.InDelta(t, 0.4431472+0.8068528, KL(p, q).Value().Item().F64(), 1.0e-6)
+}
+
+func TestCategorical(t *testing.T) {
+	d := NewCategorical(vec(math.Log(0.2), math.Log(0.5), math.Log(0.3)))
+	assert.InDeltaSlice(t, []float64{0.2, 0.5, 0.3}, d.Probs().Value().Data().F64(), 1.0e-12)
+	assert.InDelta(t, math.Log(0.5), d.LogProb(mat.Scalar(1.0)).Value().Item().F64(), 1.0e-12)
+
+	entropy := -(0.2*math.Log(0.2) + 0.5*math.Log(0.5) + 0.3*math.Log(0.3))
+	assert.InDelta(t, entropy, d.Entropy().Value().Item().F64(), 1.0e-12)
+
+	q := NewCategorical(vec(0, 0, 0))
+	assert.InDelta(t, math.Log(3)-entropy, d.KL(q).Value().Item().F64(), 1.0e-12)
+
+	s := d.Sample(rand.NewLockedRand(1)).Value().Item().F64()
+	assert.Contains(t, []float64{0, 1, 2}, s)
+}
+
+func TestBernoulli(t *testing.T) {
+	d := NewBernoulli(vec(0, math.Log(3)))
+	assert.InDeltaSlice(t, []float64{0.5, 0.75}, d.Probs().Value().Data().F64(), 1.0e-12)
+	assert.InDeltaSlice(t, []float64{math.Log(0.5), math.Log(0.25)},
+		d.LogProb(vec(1, 0)).Value().Data().F64(), 1.0e-12)
+	assert.InDeltaSlice(t, []float64{math.Log(2), -0.75*math.Log(0.75) - 0.25*math.Log(0.25)},
+		d.Entropy().Value().Data().F64(), 1.0e-12)
+
+	q := NewBernoulli(vec(math.Log(3), 0))
+	kl := 0.5*math.Log(0.5/0.75) + 0.5*math.Log(0.5/0.25)
+	assert.InDelta(t, kl, d.KL(q).Value().Data().F64()[0], 1.0e-12)
+}
+
+func TestBeta(t *testing.T) {
+	d := NewBeta(vec(2), vec(3))
+	// Beta(2, 3) has density 12 x (1-x)^2.
+	assert.InDelta(t, math.Log(12*0.4*0.36), d.LogProb(vec(0.4)).Value().Item().F64(), 1.0e-12)
+	assert.InDelta(t, -0.2349066, d.Entropy().Value().Item().F64(), 1.0e-6)
+}
+
+func TestDirichlet(t *testing.T) {
+	d := NewDirichlet(vec(1, 1, 1))
+	// The uniform distribution on the 2-simplex has density 2.
+	assert.InDelta(t, math.Log(2), d.LogProb(vec(0.2, 0.3, 0.5)).Value().Item().F64(), 1.0e-12)
+	assert.InDelta(t, -math.Log(2), d.Entropy().Value().Item().F64(), 1.0e-12)
+}
+
+func TestCategorical_RelaxedSample(t *testing.T) {
+	probs := []float64{0.2, 0.5, 0.3}
+	logits := vec(math.Log(0.2), math.Log(0.5), math.Log(0.3))
+	d := NewCategorical(logits)
+
+	// The argmax of a relaxed sample is distributed as the categorical.
+	generator := rand.NewLockedRand(42)
+	counts := make([]float64, 3)
+	for i := 0; i < numSamples; i++ {
+		y := d.RelaxedSample(0.5, generator).Value()
+		assert.InDelta(t, 1, y.(mat.Matrix).Sum().Item().F64(), 1.0e-9)
+		counts[y.(mat.Matrix).ArgMax()] += 1.0 / numSamples
+	}
+	assert.InDeltaSlice(t, probs, counts, 0.02)
+
+	y := d.RelaxedSample(0.5, generator)
+	assert.Nil(t, ag.Backward(ag.At(y, 0)))
+	assert.True(t, logits.HasGrad())
+}
+
+func TestBeta_RSample(t *testing.T) {
+	// E[x] = alpha / (alpha + beta), so the mean gradients of the samples
+	// are beta / (alpha + beta)^2 and -alpha / (alpha + beta)^2.
+	alpha, beta := make([]float64, numSamples), make([]float64, numSamples)
+	for i := range alpha {
+		alpha[i], beta[i] = 2, 3
+	}
+	a, b := vec(alpha...), vec(beta...)
+	x := NewBeta(a, b).RSample(rand.NewLockedRand(42))
+	assert.Nil(t, ag.Backward(ag.ReduceSum(x)))
+
+	assert.InDelta(t, 0.4, mean(x.Value().Data().F64()), 0.01)
+	assert.InDelta(t, 3.0/25, mean(a.Grad().Data().F64()), 0.01)
+	assert.InDelta(t, -2.0/25, mean(b.Grad().Data().F64()), 0.01)
+}
+
+func TestDirichlet_RSample(t *testing.T) {
+	// E[x_0] = alpha_0 / sum(alpha), whose gradient is (9 - 2) / 81 with
+	// respect to alpha_0, and -2 / 81 with respect to the others.
+	alpha := vec(2, 3, 4)
+	d := NewDirichlet(alpha)
+	generator := rand.NewLockedRand(42)
+	const n = 5000
+	var sum float64
+	for i := 0; i < n; i++ {
+		x := d.RSample(generator)
+		assert.InDelta(t, 1, x.Value().(mat.Matrix).Sum().Item().F64(), 1.0e-9)
+		sum += x.Value().Data().F64()[0]
+		assert.Nil(t, ag.Backward(ag.At(x, 0)))
+	}
+	assert.InDelta(t, 2.0/9, sum/n, 0.01)
+	grad := alpha.Grad().Data().F64()
+	assert.InDeltaSlice(t, []float64{7.0 / 81, -2.0 / 81, -2.0 / 81}, []float64{grad[0] / n, grad[1] / n, grad[2] / n}, 0.01)
+}
+
+func TestGammaSampleGrad(t *testing.T) {
+	// Moving the shape from a to a+h, the sample x with the same CDF value
+	// moves to x+h*dx, found here by bisection.
+	for _, a := range []float64{0.3, 1, 2.5, 10} {
+		for _, x := range []float64{0.05, 0.8, 3, 12} {
+			u, _ := regularizedGamma(a, x)
+			const h = 1.0e-5
+			lo, hi := 0.0, 100.0
+			for i := 0; i < 200; i++ {
+				mid := (lo + hi) / 2
+				if p, _ := regularizedGamma(a+h, mid); p < u {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			expected := (lo - x) / h
+			assert.InDelta(t, expected, gammaSampleGrad(x, a), 1.0e-3*math.Max(1, math.Abs(expected)), "a=%g x=%g", a, x)
+		}
+	}
+}
+
+func TestRegularizedGamma(t *testing.T) {
+	for _, x := range []float64{0.01, 0.5, 1, 2, 7, 30} {
+		p, q := regularizedGamma(1, x)
+		assert.InDelta(t, 1-math.Exp(-x), p, 1.0e-13)
+		assert.InDelta(t, math.Exp(-x), q, 1.0e-13)
+		p, q = regularizedGamma(0.5, x)
+		assert.InDelta(t, math.Erf(math.Sqrt(x)), p, 1.0e-13)
+		assert.InDelta(t, math.Erfc(math.Sqrt(x)), q, 1.0e-13)
+	}
+}
+
+func mean(xs []float64) float64 {
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// TestMonteCarlo checks entropies and KL divergences against their Monte
+// Carlo estimates: H(p) ≈ -E[log p(x)] and KL(p||q) ≈ E[log p(x) - log q(x)].
+func TestMonteCarlo(t *testing.T) {
+	testCases := []struct {
+		name string
+		p, q Distribution
+	}{
+		{"normal", NewNormal(vec(0.5), vec(1.5)), NewNormal(vec(-1), vec(2))},
+		{"categorical", NewCategorical(vec(0.1, -1, 2)), NewCategorical(vec(1, 0, 0.5))},
+		{"bernoulli", NewBernoulli(vec(0.7)), NewBernoulli(vec(-0.3))},
+		{"beta", NewBeta(vec(2.5), vec(1.5)), NewBeta(vec(1.2), vec(3))},
+		{"dirichlet", NewDirichlet(vec(2, 3, 4)), NewDirichlet(vec(1, 1.5, 5))},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			generator := rand.NewLockedRand(42)
+			var entropy, kl float64
+			for i := 0; i < numSamples; i++ {
+				x := tc.p.Sample(generator)
+				lp := tc.p.LogProb(x).Value().Data().F64()[0]
+				entropy -= lp / numSamples
+				kl += (lp - tc.q.LogProb(x).Value().Data().F64()[0]) / numSamples
+			}
+			assert.InDelta(t, tc.p.Entropy().Value().Data().F64()[0], entropy, 0.03)
+			assert.InDelta(t, KL(tc.p, tc.q).Value().Data().F64()[0], kl, 0.03)
+			assert.InDelta(t, 0, KL(tc.p, tc.p).Value().Data().F64()[0], 1.0e-9)
+		})
+	}
+}
+
+// TestGradients compares the gradients of LogProb, Entropy and KL with
+// respect to the concentrations against finite differences.
+func TestGradients(t *testing.T) {
+	const h = 1.0e-6
+	fns := map[string]func(alpha mat.Tensor) mat.Tensor{
+		"beta logprob": func(a mat.Tensor) mat.Tensor {
+			return ag.ReduceSum(NewBeta(a, vec(1.5, 0.8)).LogProb(vec(0.3, 0.6)))
+		},
+		"beta entropy": func(a mat.Tensor) mat.Tensor {
+			return ag.ReduceSum(NewBeta(a, vec(1.5, 0.8)).Entropy())
+		},
+		"beta kl": func(a mat.Tensor) mat.Tensor {
+			return ag.ReduceSum(NewBeta(a, vec(1.5, 0.8)).KL(NewBeta(vec(1, 2), vec(2, 1))))
+		},
+		"dirichlet logprob": func(a mat.Tensor) mat.Tensor {
+			return NewDirichlet(a).LogProb(vec(0.3, 0.7))
+		},
+		"dirichlet entropy": func(a mat.Tensor) mat.Tensor {
+			return NewDirichlet(a).Entropy()
+		},
+		"dirichlet kl": func(a mat.Tensor) mat.Tensor {
+			return NewDirichlet(a).KL(NewDirichlet(vec(1, 3)))
+		},
+	}
+	for name, fn := range fns {
+		t.Run(name, func(t *testing.T) {
+			alpha := []float64{2.5, 0.7}
+			a := vec(alpha...)
+			assert.Nil(t, ag.Backward(fn(a)))
+
+			for i := range alpha {
+				plus := append([]float64{}, alpha...)
+				minus := append([]float64{}, alpha...)
+				plus[i] += h
+				minus[i] -= h
+				fp := fn(vec(plus...)).Value().Item().F64()
+				fm := fn(vec(minus...)).Value().Item().F64()
+				assert.InDelta(t, (fp-fm)/(2*h), a.Grad().Data().F64()[i], 1.0e-5)
+			}
+		})
+	}
+}
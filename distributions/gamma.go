@@ -0,0 +1,133 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distributions
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/mat/rand/gamma"
+)
+
+// rsampleGamma draws a value from Gamma(alpha, 1) for each element of alpha,
+// as a node of the graph whose gradients flow back to alpha.
+//
+// The samples of a Gamma are not a differentiable function of the shape and
+// of an independent noise, so the gradients are computed with the implicit
+// reparameterization of Figurnov et al. (2018): for a sample x, whose CDF
+// value u = F(x; alpha) is held constant, dx/dalpha = -(dF/dalpha) / f(x),
+// f being the density.
+func rsampleGamma(alpha mat.Tensor, generator *rand.LockedRand) mat.Tensor {
+	sample := sampleLike(alpha, func(a float64) float64 {
+		return gamma.Sample(a, generator)
+	})
+	return ag.NewOperator(&standardGamma{alpha: alpha, sample: sample}).Run()
+}
+
+// standardGamma is the AutoGradFunction returning the samples drawn by
+// rsampleGamma.
+type standardGamma struct {
+	alpha  mat.Tensor
+	sample mat.Matrix
+}
+
+// Operands returns the list of operands.
+func (r *standardGamma) Operands() []mat.Tensor {
+	return []mat.Tensor{r.alpha}
+}
+
+// Forward computes the output of the function.
+func (r *standardGamma) Forward() (mat.Tensor, error) {
+	return r.sample, nil
+}
+
+// Backward computes the backward pass.
+func (r *standardGamma) Backward(gy mat.Tensor) error {
+	if !mat.SameDims(r.sample, gy) {
+		return fmt.Errorf("distributions: matrices have incompatible dimensions")
+	}
+	if r.alpha.RequiresGrad() {
+		gx := zip(r.sample, r.alpha, gammaSampleGrad).Prod(gy.(mat.Matrix))
+		r.alpha.AccGrad(gx)
+	}
+	return nil
+}
+
+// gammaSampleGrad returns the derivative, with respect to the shape a, of
+// the sample x drawn from Gamma(a, 1), given the CDF value of x held
+// constant. The derivative of the CDF is computed by central differences.
+func gammaSampleGrad(x, a float64) float64 {
+	lg, _ := math.Lgamma(a)
+	density := math.Exp((a-1)*math.Log(x) - x - lg)
+	if density == 0 || math.IsInf(density, 0) {
+		return 0
+	}
+	h := 1.0e-4 * a
+	pp, qp := regularizedGamma(a+h, x)
+	pm, qm := regularizedGamma(a-h, x)
+	// The smaller of P and Q = 1 - P is the more accurate one.
+	dF := (pp - pm) / (2 * h)
+	if pp > 0.5 {
+		dF = (qm - qp) / (2 * h)
+	}
+	return -dF / density
+}
+
+// regularizedGamma returns the regularized lower and upper incomplete gamma
+// functions P(a, x) and Q(a, x) = 1 - P(a, x), computed with their series
+// expansion for x < a+1, and with their continued fraction otherwise.
+func regularizedGamma(a, x float64) (p, q float64) {
+	const (
+		eps     = 1.0e-15
+		tiny    = 1.0e-300
+		maxIter = 1000
+	)
+	if x <= 0 {
+		return 0, 1
+	}
+	lg, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(x) - x - lg)
+
+	if x < a+1 {
+		ap, del := a, 1/a
+		sum := del
+		for i := 0; i < maxIter && math.Abs(del) > math.Abs(sum)*eps; i++ {
+			ap++
+			del *= x / ap
+			sum += del
+		}
+		p = sum * prefix
+		return p, 1 - p
+	}
+
+	// Modified Lentz's method.
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	f := d
+	for i := 1; i <= maxIter; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		f *= del
+		if math.Abs(del-1) < eps {
+			break
+		}
+	}
+	q = prefix * f
+	return 1 - q, q
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distributions
+
+import (
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+var _ Reparameterized = &MultivariateNormalDiag{}
+
+// MultivariateNormalDiag is a multivariate normal distribution with a
+// diagonal covariance matrix, parameterized by the mean vector and by the
+// vector of the standard deviations.
+type MultivariateNormalDiag struct {
+	normal *Normal
+}
+
+// NewMultivariateNormalDiag returns a new MultivariateNormalDiag.
+// loc and scale must be column vectors of the same size.
+func NewMultivariateNormalDiag(loc, scale mat.Tensor) *MultivariateNormalDiag {
+	if !mat.IsVector(loc.Value()) {
+		panic("distributions: loc must be a vector")
+	}
+	return &MultivariateNormalDiag{normal: NewNormal(loc, scale)}
+}
+
+// Loc returns the mean vector.
+func (d *MultivariateNormalDiag) Loc() mat.Tensor {
+	return d.normal.Loc
+}
+
+// Scale returns the vector of the standard deviations.
+func (d *MultivariateNormalDiag) Scale() mat.Tensor {
+	return d.normal.Scale
+}
+
+// Sample draws a vector from the distribution.
+func (d *MultivariateNormalDiag) Sample(generator *rand.LockedRand) mat.Tensor {
+	return d.normal.Sample(generator)
+}
+
+// RSample draws a vector as Loc + Scale * ε, with ε ~ N(0, I).
+func (d *MultivariateNormalDiag) RSample(generator *rand.LockedRand) mat.Tensor {
+	return d.normal.RSample(generator)
+}
+
+// LogProb returns the log-density of the vector, as a scalar.
+func (d *MultivariateNormalDiag) LogProb(value mat.Tensor) mat.Tensor {
+	return ag.ReduceSum(d.normal.LogProb(value))
+}
+
+// Entropy returns the entropy, as a scalar.
+func (d *MultivariateNormalDiag) Entropy() mat.Tensor {
+	return ag.ReduceSum(d.normal.Entropy())
+}
+
+// KL returns the KL divergence from another MultivariateNormalDiag, as a scalar.
+func (d *MultivariateNormalDiag) KL(other Distribution) mat.Tensor {
+	q, ok := other.(*MultivariateNormalDiag)
+	if !ok {
+		panic(mismatch(d, other))
+	}
+	return ag.ReduceSum(d.normal.KL(q.normal))
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distributions
+
+import (
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+var _ Reparameterized = &Normal{}
+
+// Normal is a batch of independent univariate normal distributions,
+// one for each element of Loc and Scale.
+type Normal struct {
+	Loc   mat.Tensor
+	Scale mat.Tensor
+}
+
+// NewNormal returns a new Normal with the given mean and standard deviation.
+// The two tensors must have the same shape.
+func NewNormal(loc, scale mat.Tensor) *Normal {
+	if !mat.SameDims(loc.Value(), scale.Value()) {
+		panic("distributions: loc and scale have incompatible dimensions")
+	}
+	return &Normal{Loc: loc, Scale: scale}
+}
+
+// Sample draws a value from the distribution.
+func (d *Normal) Sample(generator *rand.LockedRand) mat.Tensor {
+	return zip(d.Loc, d.Scale, func(loc, scale float64) float64 {
+		return loc + scale*generator.NormFloat64()
+	})
+}
+
+// RSample draws a value as Loc + Scale * ε, with ε ~ N(0, 1).
+func (d *Normal) RSample(generator *rand.LockedRand) mat.Tensor {
+	eps := sampleLike(d.Loc, func(float64) float64 { return generator.NormFloat64() })
+	return ag.Add(d.Loc, ag.Prod(d.Scale, eps))
+}
+
+// LogProb returns the element-wise log-density of the value.
+func (d *Normal) LogProb(value mat.Tensor) mat.Tensor {
+	z := ag.Div(ag.Sub(value, d.Loc), d.Scale)
+	sq := ag.ProdScalar(ag.Square(z), scalar(z, -0.5))
+	return ag.SubScalar(ag.Sub(sq, ag.Log(d.Scale)), scalar(z, halfLog2Pi))
+}
+
+// Entropy returns the element-wise entropy.
+func (d *Normal) Entropy() mat.Tensor {
+	return ag.AddScalar(ag.Log(d.Scale), scalar(d.Scale, 0.5+halfLog2Pi))
+}
+
+// KL returns the element-wise KL divergence from another Normal.
+func (d *Normal) KL(other Distribution) mat.Tensor {
+	q, ok := other.(*Normal)
+	if !ok {
+		panic(mismatch(d, other))
+	}
+	ratio := ag.Square(ag.Div(d.Scale, q.Scale))
+	diff := ag.Square(ag.Div(ag.Sub(d.Loc, q.Loc), q.Scale))
+	half := scalar(ratio, 0.5)
+	return ag.ProdScalar(ag.Sub(ag.Add(ratio, diff), ag.AddScalar(ag.Log(ratio), scalar(ratio, 1))), half)
+}
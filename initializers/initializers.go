@@ -5,12 +5,14 @@
 package initializers
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/mat/rand"
 	"github.com/nlpodyssey/spago/mat/rand/normal"
+	"github.com/nlpodyssey/spago/mat/rand/truncnormal"
 	"github.com/nlpodyssey/spago/mat/rand/uniform"
 	"github.com/nlpodyssey/spago/nn/activation"
 )
@@ -156,3 +158,119 @@ func Achlioptas(m mat.Matrix, generator *rand.LockedRand) mat.Matrix {
 
 	return m
 }
+
+// FanMode selects the dimension of a weight matrix used to scale the
+// Kaiming (He) initializations. The matrix is expected to be shaped as
+// (out × in), so that it is multiplied by column vectors of size in.
+type FanMode int
+
+const (
+	// FanIn uses the number of columns, preserving the magnitude of the
+	// variance of the weights in the forward pass.
+	FanIn FanMode = iota
+	// FanOut uses the number of rows, preserving the magnitudes in the
+	// backward pass.
+	FanOut
+)
+
+func fan(m mat.Matrix, mode FanMode) float64 {
+	if mode == FanOut {
+		return float64(m.Shape()[0])
+	}
+	return float64(m.Shape()[1])
+}
+
+// KaimingUniform fills the input matrix with values according to the method
+// described in "Delving deep into rectifiers: Surpassing human-level
+// performance on ImageNet classification" - He, K. et al. (2015), using a
+// uniform distribution in [-bound, bound], with bound = gain * sqrt(3 / fan).
+// Use Gain to find the gain value for the activation that follows the layer.
+//
+// The matrix is returned for convenience.
+func KaimingUniform(m mat.Matrix, mode FanMode, gain float64, generator *rand.LockedRand) mat.Matrix {
+	bound := gain * math.Sqrt(3.0/fan(m, mode))
+	return Uniform(m, -bound, bound, generator)
+}
+
+// KaimingNormal fills the input matrix with values according to the method
+// described in "Delving deep into rectifiers: Surpassing human-level
+// performance on ImageNet classification" - He, K. et al. (2015), using a
+// normal distribution with std = gain / sqrt(fan).
+// Use Gain to find the gain value for the activation that follows the layer.
+//
+// The matrix is returned for convenience.
+func KaimingNormal(m mat.Matrix, mode FanMode, gain float64, generator *rand.LockedRand) mat.Matrix {
+	std := gain / math.Sqrt(fan(m, mode))
+	return Normal(m, 0, std, generator)
+}
+
+// TruncatedNormal fills the input matrix with random samples from a normal
+// (Gaussian) distribution, truncated to the interval [min, max].
+//
+// The matrix is returned for convenience.
+func TruncatedNormal(m mat.Matrix, mean, std, min, max float64, generator *rand.LockedRand) mat.Matrix {
+	dist := truncnormal.New(std, mean, min, max, generator)
+	data := make([]float64, m.Size())
+	for i := range data {
+		data[i] = dist.Next()
+	}
+	m.SetData(float.Make(data...))
+	return m
+}
+
+// Sparse fills the input matrix as a sparse matrix, where the given fraction
+// of the elements of each column is set to zero, and the remaining ones are
+// drawn from a normal distribution with zero mean and the given standard
+// deviation, as described in "Deep learning via Hessian-free optimization" -
+// Martens, J. (2010).
+//
+// The matrix is returned for convenience.
+// It panics if the sparsity is not in the range [0, 1].
+func Sparse(m mat.Matrix, sparsity, std float64, generator *rand.LockedRand) mat.Matrix {
+	if !(sparsity >= 0 && sparsity <= 1) {
+		panic(fmt.Sprintf("initializers: the sparsity must be in the range [0, 1], got %g", sparsity))
+	}
+	rows, cols := m.Shape()[0], m.Shape()[1]
+	numZeros := int(math.Ceil(sparsity * float64(rows)))
+	Normal(m, 0, std, generator)
+	zero := float.Interface(0.0)
+	for j := 0; j < cols; j++ {
+		for _, i := range generator.Perm(rows)[:numZeros] {
+			m.SetScalar(zero, i, j)
+		}
+	}
+	return m
+}
+
+// Orthogonal fills the input matrix with a (semi-)orthogonal matrix, scaled
+// by the gain, as described in "Exact solutions to the nonlinear dynamics of
+// learning in deep linear neural networks" - Saxe, A. et al. (2013).
+// If the matrix has more rows than columns, its columns are orthonormal,
+// otherwise its rows are.
+//
+// The matrix is returned for convenience.
+func Orthogonal(m mat.Matrix, gain float64, generator *rand.LockedRand) mat.Matrix {
+	rows, cols := m.Shape()[0], m.Shape()[1]
+	tall, short := rows, cols
+	if rows < cols {
+		tall, short = cols, rows
+	}
+	a := Normal(mat.NewDense[float64](mat.WithShape(tall, short)), 0, 1, generator)
+	q, r := mat.QR(a)
+
+	// Make the decomposition unique, so that q is uniformly distributed.
+	qData := q.Data().F64()
+	for j := 0; j < short; j++ {
+		if r.At(j, j).Item().F64() < 0 {
+			for i := 0; i < tall; i++ {
+				qData[i*short+j] = -qData[i*short+j]
+			}
+		}
+	}
+	q = q.NewMatrix(mat.WithShape(tall, short), mat.WithBacking(qData))
+	if rows < cols {
+		q = q.T()
+	}
+	m.SetData(q.ProdScalar(gain).Data())
+	return m
+}
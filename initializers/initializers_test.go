@@ -0,0 +1,160 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package initializers
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/activation"
+	"github.com/nlpodyssey/spago/nn/linear"
+	"github.com/stretchr/testify/assert"
+)
+
+func stats(m mat.Matrix) (mean, std float64) {
+	data := m.Data().F64()
+	v := variance(data)
+	for _, x := range data {
+		mean += x
+	}
+	return mean / float64(len(data)), math.Sqrt(v)
+}
+
+func TestKaiming(t *testing.T) {
+	gain := Gain(activation.ReLU)
+
+	m := KaimingNormal(mat.NewDense[float64](mat.WithShape(400, 100)), FanIn, gain, rand.NewLockedRand(1))
+	mean, std := stats(m)
+	assert.InDelta(t, 0, mean, 0.005)
+	assert.InDelta(t, gain/math.Sqrt(100), std, 0.005)
+
+	m = KaimingUniform(mat.NewDense[float32](mat.WithShape(400, 100)), FanOut, gain, rand.NewLockedRand(1))
+	bound := gain * math.Sqrt(3.0/400)
+	_, std = stats(m)
+	assert.InDelta(t, gain/math.Sqrt(400), std, 0.002)
+	for _, v := range m.Data().F64() {
+		assert.True(t, math.Abs(v) <= bound)
+	}
+}
+
+func TestTruncatedNormal(t *testing.T) {
+	m := TruncatedNormal(mat.NewDense[float32](mat.WithShape(50, 50)), 0, 1, -2, 2, rand.NewLockedRand(1))
+	for _, v := range m.Data().F64() {
+		assert.True(t, v >= -2 && v <= 2)
+	}
+}
+
+func TestSparse(t *testing.T) {
+	m := Sparse(mat.NewDense[float64](mat.WithShape(10, 20)), 0.3, 0.01, rand.NewLockedRand(1))
+	for j := 0; j < 20; j++ {
+		zeros := 0
+		for _, v := range m.ExtractColumn(j).Data().F64() {
+			if v == 0 {
+				zeros++
+			}
+		}
+		assert.Equal(t, 3, zeros)
+	}
+
+	for _, sparsity := range []float64{-0.1, 1.5, math.NaN()} {
+		assert.PanicsWithValue(t, fmt.Sprintf("initializers: the sparsity must be in the range [0, 1], got %g", sparsity), func() {
+			Sparse(mat.NewDense[float64](mat.WithShape(10, 20)), sparsity, 0.01, rand.NewLockedRand(1))
+		})
+	}
+	m = Sparse(mat.NewDense[float64](mat.WithShape(4, 2)), 1, 0.01, rand.NewLockedRand(1))
+	assert.Equal(t, []float64{0, 0, 0, 0, 0, 0, 0, 0}, m.Data().F64())
+}
+
+func TestOrthogonal(t *testing.T) {
+	for _, shape := range [][]int{{6, 3}, {3, 6}, {4, 4}} {
+		m := Orthogonal(mat.NewDense[float64](mat.WithShape(shape...)), 2, rand.NewLockedRand(1))
+		small := shape[0]
+		gram := m.Mul(m.T())
+		if shape[0] > shape[1] {
+			small = shape[1]
+			gram = m.T().Mul(m)
+		}
+		expected := mat.CreateIdentityMatrix[float64](small)
+		for i := range expected {
+			expected[i] *= 4
+		}
+		assert.InDeltaSlice(t, expected, gram.Data().F64(), 1.0e-9)
+	}
+}
+
+func TestLSUV(t *testing.T) {
+	r := rand.NewLockedRand(1)
+	x := Normal(mat.NewDense[float64](mat.WithShape(16, 256)), 3, 5, r)
+	w1 := mat.NewDense[float64](mat.WithShape(32, 16))
+	w2 := mat.NewDense[float64](mat.WithShape(8, 32))
+
+	h1 := func() mat.Tensor { return ag.Mul(w1, x) }
+	h2 := func() mat.Tensor { return ag.Mul(w2, ag.ReLU(h1())) }
+	outputs := []func() mat.Tensor{h1, h2}
+
+	err := LSUV([]mat.Matrix{w1, w2}, func(i int) mat.Tensor { return outputs[i]() }, 0.01, 10, r)
+	assert.Nil(t, err)
+	for _, out := range outputs {
+		assert.InDelta(t, 1, variance(out().Value().Data().F64()), 0.01)
+	}
+
+	err = LSUV([]mat.Matrix{w1}, func(int) mat.Tensor { return mat.NewDense[float64](mat.WithShape(2, 2)) }, 0.01, 10, r)
+	assert.Error(t, err)
+}
+
+type testModel struct {
+	nn.Module
+	Layers []*linear.Model
+	Out    *linear.Model
+}
+
+func TestInitModel(t *testing.T) {
+	m := &testModel{
+		Layers: []*linear.Model{linear.New[float32](4, 8), linear.New[float32](8, 8)},
+		Out:    linear.New[float32](8, 2),
+	}
+	r := rand.NewLockedRand(1)
+	InitModel(m,
+		ForType(func(l *testModel) {
+			Constant(l.Out.W, 0.5)
+		}),
+		ForType(func(l *linear.Model) {
+			KaimingUniform(l.W, FanIn, Gain(activation.ReLU), r)
+			Constant(l.B, 0.1)
+		}),
+	)
+
+	for _, l := range m.Layers {
+		assert.NotZero(t, l.W.Data().F64()[0])
+		assert.Equal(t, []float32{0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1}, l.B.Data().F32())
+	}
+	// Out matches the second rule as well, overriding the first one,
+	// because nn.Apply visits the sub-models after their parent.
+	assert.NotEqual(t, 0.5, m.Out.W.Data().F64()[0])
+	assert.InDeltaSlice(t, []float64{0.1, 0.1}, m.Out.B.Data().F64(), 1.0e-7)
+}
+
+func TestInitParams(t *testing.T) {
+	newModel := func(layers int) *testModel {
+		m := &testModel{Out: linear.New[float32](8, 2)}
+		for i := 0; i < layers; i++ {
+			m.Layers = append(m.Layers, linear.New[float32](8, 8))
+		}
+		return InitParams(m, rand.NewStream(1), func(name string, p *nn.Param, generator *rand.LockedRand) {
+			Normal(p, 0, 1, generator)
+		})
+	}
+
+	// adding a layer does not change the values of the other parameters
+	m1, m2 := newModel(1), newModel(2)
+	assert.Equal(t, m1.Layers[0].W.Data(), m2.Layers[0].W.Data())
+	assert.Equal(t, m1.Out.W.Data(), m2.Out.W.Data())
+	assert.NotEqual(t, m2.Layers[0].W.Data(), m2.Layers[1].W.Data())
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package initializers
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+// LSUV performs the data-dependent Layer-Sequential Unit-Variance
+// initialization described in "All you need is a good init" - Mishkin, D. &
+// Matas, J. (2016).
+//
+// The weights are the matrices of the layers, in the order in which they are
+// applied in the forward pass. Each of them is first initialized with
+// Orthogonal, then repeatedly rescaled until the variance of the output of
+// its layer is within tolerance from one, or maxIterations is reached.
+//
+// The function output(i) must run the forward pass on a representative batch
+// of data, returning the output of the i-th layer before its activation.
+func LSUV(weights []mat.Matrix, output func(i int) mat.Tensor, tolerance float64, maxIterations int, generator *rand.LockedRand) error {
+	for i, w := range weights {
+		Orthogonal(w, 1, generator)
+		for iter := 0; iter < maxIterations; iter++ {
+			v := variance(output(i).Value().Data().F64())
+			if v == 0 || math.IsNaN(v) {
+				return fmt.Errorf("initializers: LSUV: the output of layer %d has variance %g", i, v)
+			}
+			if math.Abs(v-1) < tolerance {
+				break
+			}
+			w.ProdScalarInPlace(1 / math.Sqrt(v))
+		}
+	}
+	return nil
+}
+
+func variance(xs []float64) float64 {
+	var sum, sumSq float64
+	for _, x := range xs {
+		sum += x
+		sumSq += x * x
+	}
+	n := float64(len(xs))
+	mean := sum / n
+	return sumSq/n - mean*mean
+}
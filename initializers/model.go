@@ -0,0 +1,57 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package initializers
+
+import (
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+// Rule initializes the parameters of a model, reporting whether the model
+// was handled.
+type Rule func(m nn.Model) bool
+
+// ForType returns a Rule which applies init to the models of type M,
+// and ignores any other model.
+func ForType[M nn.Model](init func(m M)) Rule {
+	return func(m nn.Model) bool {
+		v, ok := m.(M)
+		if ok {
+			init(v)
+		}
+		return ok
+	}
+}
+
+// InitModel walks the model and all its sub-models with nn.Apply,
+// initializing each of them with the first matching rule.
+// Models matching no rule are left untouched. Since a model is visited
+// before its sub-models, the rules of the latter take precedence.
+//
+// The model is returned for convenience.
+func InitModel[M nn.Model](m M, rules ...Rule) M {
+	nn.Apply(m, func(model nn.Model) {
+		for _, rule := range rules {
+			if rule(model) {
+				return
+			}
+		}
+	})
+	return m
+}
+
+// InitParams calls init for each parameter of the model, with its path (see
+// nn.NamedParameters) and a generator drawing from the sub-stream of s
+// identified by the path. The values of each parameter therefore only
+// depend on s and on its path, regardless of the order in which the
+// parameters are initialized, or of the other parameters of the model.
+//
+// The model is returned for convenience.
+func InitParams[M nn.Model](m M, s *rand.Stream, init func(name string, p *nn.Param, generator *rand.LockedRand)) M {
+	for _, p := range nn.NamedParameters(m) {
+		init(p.Name, p.Param, s.Split(p.Name).Rand())
+	}
+	return m
+}
@@ -0,0 +1,171 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// numArenaBuckets is the number of size classes of an Arena: a buffer of
+// size n belongs to the class of the smallest power of two >= n.
+const numArenaBuckets = 64
+
+// Arena is a size-bucketed pool of buffers for the data of dense matrices.
+//
+// An arena is scoped to the computations it is explicitly attached to: the
+// input matrices of a training step are created with WithArena, and the
+// matrices resulting from the operations of Dense on them (including the
+// gradients they accumulate with AccGrad) take their buffers from the arena,
+// instead of allocating new ones, and keep it for their own results.
+// Release returns all those buffers to the pool at once, ready to be reused.
+//
+// The computations not involving any matrix with an arena never draw from
+// one, such as the ones of other models trained concurrently. Parameters,
+// their gradients (AccGrad allocates from the arena of the receiver) and the
+// optimizer states are not part of an arena either.
+//
+// A typical training loop calls Release at the end of each step, after the
+// optimizer has updated the parameters and cleared their gradients. At that
+// point, no matrix created during the step must be in use anymore: copy out
+// anything that should survive (e.g. with NewDense and WithBacking).
+type Arena struct {
+	mu     sync.Mutex
+	free32 [numArenaBuckets][][]float32
+	free64 [numArenaBuckets][][]float64
+	used32 [][]float32
+	used64 [][]float64
+	stats  ArenaStats
+}
+
+// ArenaStats reports the usage of an Arena.
+type ArenaStats struct {
+	// Allocs is the number of buffers requested to the arena.
+	Allocs int
+	// Reuses is the number of requests served with a pooled buffer.
+	Reuses int
+	// Releases is the number of calls to Release.
+	Releases int
+	// InUse is the number of buffers handed out since the last Release.
+	InUse int
+	// Pooled is the number of buffers available for reuse.
+	Pooled int
+	// PooledBytes is the total capacity, in bytes, of the pooled buffers.
+	PooledBytes int
+}
+
+// ReuseRatio returns the fraction of requests served with a pooled buffer.
+func (s ArenaStats) ReuseRatio() float64 {
+	if s.Allocs == 0 {
+		return 0
+	}
+	return float64(s.Reuses) / float64(s.Allocs)
+}
+
+// String returns a short summary of the statistics.
+func (s ArenaStats) String() string {
+	return fmt.Sprintf("allocs: %d, reuses: %d (%.1f%%), releases: %d, in use: %d, pooled: %d (%d bytes)",
+		s.Allocs, s.Reuses, s.ReuseRatio()*100, s.Releases, s.InUse, s.Pooled, s.PooledBytes)
+}
+
+// NewArena returns a new empty Arena.
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+// Release makes all the buffers handed out since the previous Release
+// available for reuse. The matrices backed by those buffers must not be
+// used anymore.
+func (a *Arena) Release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, buf := range a.used32 {
+		b := bucketOf(cap(buf))
+		a.free32[b] = append(a.free32[b], buf)
+		a.stats.PooledBytes += cap(buf) * 4
+	}
+	for _, buf := range a.used64 {
+		b := bucketOf(cap(buf))
+		a.free64[b] = append(a.free64[b], buf)
+		a.stats.PooledBytes += cap(buf) * 8
+	}
+	a.stats.Pooled += a.stats.InUse
+	a.stats.InUse = 0
+	a.stats.Releases++
+	clear(a.used32)
+	clear(a.used64)
+	a.used32 = a.used32[:0]
+	a.used64 = a.used64[:0]
+}
+
+// Reset drops all the pooled buffers, letting the garbage collector reclaim
+// them. The buffers in use are left untouched, and will be pooled on the
+// next Release.
+func (a *Arena) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.free32 = [numArenaBuckets][][]float32{}
+	a.free64 = [numArenaBuckets][][]float64{}
+	a.stats.Pooled = 0
+	a.stats.PooledBytes = 0
+}
+
+// Stats returns the usage statistics of the arena.
+func (a *Arena) Stats() ArenaStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stats
+}
+
+// arenaAlloc returns a zeroed buffer of the given size from the arena.
+func arenaAlloc[T float.DType](a *Arena, size int) []T {
+	if size == 0 {
+		return []T{}
+	}
+	switch any(T(0)).(type) {
+	case float32:
+		return any(allocFrom(a, &a.free32, &a.used32, size, 4)).([]T)
+	case float64:
+		return any(allocFrom(a, &a.free64, &a.used64, size, 8)).([]T)
+	default:
+		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
+	}
+}
+
+func allocFrom[T float.DType](a *Arena, free *[numArenaBuckets][][]T, used *[][]T, size, sizeOf int) []T {
+	b := bucketOf(size)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stats.Allocs++
+	a.stats.InUse++
+
+	var buf []T
+	if n := len(free[b]); n > 0 {
+		buf = free[b][n-1]
+		free[b][n-1] = nil
+		free[b] = free[b][:n-1]
+		clear(buf[:size])
+		a.stats.Reuses++
+		a.stats.Pooled--
+		a.stats.PooledBytes -= cap(buf) * sizeOf
+	} else {
+		buf = make([]T, 1<<b)
+	}
+	*used = append(*used, buf)
+	// The capacity is limited to the size, so that appending to the
+	// returned slice never writes to the rest of the pooled buffer.
+	return buf[:size:size]
+}
+
+// bucketOf returns the size class of a buffer of the given size.
+func bucketOf(size int) int {
+	if size <= 1 {
+		return 0
+	}
+	return bits.Len(uint(size - 1))
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+func TestArena(t *testing.T) {
+	t.Run("float32", testArena[float32])
+	t.Run("float64", testArena[float64])
+}
+
+func testArena[T float.DType](t *testing.T) {
+	a := NewArena()
+
+	x := NewDense[T](WithShape(3), WithBacking([]T{1, 2, 3}), WithArena(a))
+	y := x.AddScalar(1)
+	assert.Equal(t, []T{2, 3, 4}, Data[T](y))
+	assert.Equal(t, ArenaStats{Allocs: 1, InUse: 1}, a.Stats())
+
+	a.Release()
+	assert.Equal(t, ArenaStats{Allocs: 1, Releases: 1, Pooled: 1, PooledBytes: 4 * int(unsafe.Sizeof(T(0)))}, a.Stats())
+
+	// same size class (4), zeroed on reuse
+	z := NewDense[T](WithShape(4), WithBacking([]T{1, 2, 3, 4}), WithArena(a)).Clone()
+	assert.Equal(t, []T{1, 2, 3, 4}, Data[T](z))
+	w := x.Prod(x)
+	assert.Equal(t, []T{1, 4, 9}, Data[T](w))
+	stats := a.Stats()
+	assert.Equal(t, 3, stats.Allocs)
+	assert.Equal(t, 1, stats.Reuses)
+	assert.Equal(t, 2, stats.InUse)
+	assert.Equal(t, 0, stats.Pooled)
+	assert.InDelta(t, 1.0/3.0, stats.ReuseRatio(), 1e-9)
+
+	// the results keep the arena
+	w.Sqrt()
+	assert.Equal(t, 4, a.Stats().Allocs)
+
+	a.Release()
+	a.Reset()
+	stats = a.Stats()
+	assert.Equal(t, 0, stats.Pooled)
+	assert.Equal(t, 0, stats.PooledBytes)
+}
+
+func TestArena_Scope(t *testing.T) {
+	a := NewArena()
+	x := NewDense[float32](WithShape(3), WithBacking([]float32{1, 2, 3}), WithArena(a))
+	p := NewDense[float32](WithShape(3), WithBacking([]float32{4, 5, 6}), WithGrad(true))
+
+	// the matrices without an arena do not draw from it
+	q := p.AddScalar(1).Prod(p)
+	assert.Equal(t, 0, a.Stats().Allocs)
+
+	// operations between matrices with and without an arena draw from it
+	y := p.Prod(x)
+	assert.Equal(t, 1, a.Stats().Allocs)
+
+	// the gradients are drawn from the arena of the receiver
+	p.AccGrad(y)
+	x.AccGrad(y)
+	assert.Equal(t, 2, a.Stats().Allocs)
+
+	a.Release()
+	x.AddScalar(100) // reuses a released buffer
+	assert.Equal(t, []float32{4, 10, 18}, Data[float32](p.Grad()))
+	assert.Equal(t, []float32{20, 30, 42}, Data[float32](q))
+}
+
+func TestArena_ZeroedBuffers(t *testing.T) {
+	a := NewArena()
+	buf := arenaAlloc[float32](a, 5)
+	for i := range buf {
+		buf[i] = 42
+	}
+	a.Release()
+	assert.Equal(t, []float32{0, 0, 0, 0, 0, 0}, arenaAlloc[float32](a, 6))
+}
+
+func TestArena_CappedBuffers(t *testing.T) {
+	a := NewArena()
+	buf := arenaAlloc[float64](a, 5)
+	assert.Equal(t, 5, cap(buf))
+	other := append(buf, 1)
+	other[0] = 42
+	assert.Equal(t, 0.0, buf[0])
+}
+
+func TestArena_Concurrency(t *testing.T) {
+	a := NewArena()
+	x := NewDense[float32](WithShape(100), WithBacking(make([]float32, 100)), WithArena(a))
+	for step := 0; step < 3; step++ {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					x.AddScalar(1)
+				}
+			}()
+		}
+		wg.Wait()
+		a.Release()
+	}
+	stats := a.Stats()
+	assert.Equal(t, 3000, stats.Allocs)
+	assert.Equal(t, 2000, stats.Reuses)
+	assert.Equal(t, 1000, stats.Pooled)
+}
+
+func BenchmarkArena(b *testing.B) {
+	step := func(x *Dense[float32]) {
+		y := x.AddScalar(1)
+		for i := 0; i < 10; i++ {
+			y = y.Prod(x).Sigmoid()
+		}
+	}
+	b.Run("no-arena", func(b *testing.B) {
+		x := NewDense[float32](WithShape(256, 256), WithBacking(make([]float32, 256*256)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			step(x)
+		}
+	})
+	b.Run("arena", func(b *testing.B) {
+		a := NewArena()
+		x := NewDense[float32](WithShape(256, 256), WithBacking(make([]float32, 256*256)), WithArena(a))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			step(x)
+			a.Release()
+		}
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package backend defines the low-level compute routines behind the
+// operations of mat.Dense, and a registry to switch implementation at runtime.
+//
+// Three backends are registered by default:
+//   - "reference": plain Go loops, slow but easy to verify;
+//   - "simd": assembly and vectorized kernels where available (the default);
+//   - "parallel": the "simd" backend, splitting large inputs across goroutines.
+package backend
+
+import "github.com/nlpodyssey/spago/mat/float"
+
+// Kernels is the set of routines a backend implements for the data type T.
+// All matrices are raw slices in row-major order.
+//
+// Unless stated otherwise, the output y has the same length as the inputs
+// and may be the same slice as any of them.
+type Kernels[T float.DType] interface {
+	// Gemm computes c = a * b, where a is m×k, b is k×n and c is m×n.
+	// The content of c is overwritten; c must not overlap a or b.
+	Gemm(m, k, n int, a, b, c []T)
+	// Gemv computes y = a * x, where a is m×n, x has length n and y has
+	// length m. y must not overlap a or x.
+	Gemv(m, n int, a, x, y []T)
+	// GemvT computes y = aᵀ * x, where a is m×n, x has length m and y has
+	// length n. y must not overlap a or x.
+	GemvT(m, n int, a, x, y []T)
+
+	// Add computes y = x1 + x2, element-wise.
+	Add(x1, x2, y []T)
+	// Sub computes y = x1 - x2, element-wise.
+	Sub(x1, x2, y []T)
+	// Prod computes y = x1 * x2, element-wise.
+	Prod(x1, x2, y []T)
+	// Div computes y = x1 / x2, element-wise.
+	Div(x1, x2, y []T)
+	// AddConst computes y = x + c, element-wise.
+	AddConst(c T, x, y []T)
+	// MulConst computes y = x * c, element-wise.
+	MulConst(c T, x, y []T)
+
+	// Dot returns the dot product of x1 and x2.
+	Dot(x1, x2 []T) T
+	// Sum returns the sum of the elements of x.
+	Sum(x []T) T
+	// Max returns the maximum element of x, which must not be empty.
+	// As with the built-in max, the result is NaN if any element is NaN.
+	Max(x []T) T
+
+	// Exp computes the base-e exponential of each element of x.
+	Exp(x, y []T)
+	// Log computes the natural logarithm of each element of x.
+	Log(x, y []T)
+	// Sigmoid computes the logistic sigmoid of each element of x.
+	Sigmoid(x, y []T)
+	// Tanh computes the hyperbolic tangent of each element of x.
+	Tanh(x, y []T)
+	// SiLU computes x * sigmoid(x) for each element of x.
+	SiLU(x, y []T)
+	// GELU computes the tanh approximation of the GELU of each element of x.
+	GELU(x, y []T)
+	// ReLU computes max(0, x) for each element of x.
+	ReLU(x, y []T)
+}
+
+// Backend provides the kernels for each supported data type.
+type Backend interface {
+	// Name returns the name the backend is registered with.
+	Name() string
+	// Float32 returns the kernels operating on float32 values.
+	Float32() Kernels[float32]
+	// Float64 returns the kernels operating on float64 values.
+	Float64() Kernels[float64]
+}
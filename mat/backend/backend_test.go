@@ -0,0 +1,71 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package backend_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nlpodyssey/spago/mat/backend"
+	"github.com/nlpodyssey/spago/mat/backend/backendtest"
+)
+
+func TestConformance(t *testing.T) {
+	for _, name := range backend.Names() {
+		b, _ := backend.Get(name)
+		t.Run(name, func(t *testing.T) {
+			backendtest.Run(t, b)
+		})
+	}
+	t.Run("parallel-reference", func(t *testing.T) {
+		backendtest.Run(t, backend.NewParallel("parallel-reference", backend.Reference(), 4))
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	assert.Equal(t, []string{"parallel", "reference", "simd"}, backend.Names())
+	assert.Equal(t, backend.SIMDName, backend.Current().Name())
+
+	err := backend.Register(backend.Reference())
+	assert.Error(t, err)
+
+	err = backend.Use("foo")
+	assert.Error(t, err)
+	assert.Equal(t, backend.SIMDName, backend.Current().Name())
+
+	require.NoError(t, backend.Use(backend.ReferenceName))
+	defer func() { require.NoError(t, backend.Use(backend.SIMDName)) }()
+	assert.Equal(t, backend.ReferenceName, backend.Current().Name())
+
+	y := make([]float32, 2)
+	backend.For[float32]().Add([]float32{1, 2}, []float32{3, 4}, y)
+	assert.Equal(t, []float32{4, 6}, y)
+}
+
+func BenchmarkGemm(b *testing.B) {
+	for _, name := range backend.Names() {
+		k := mustGet(b, name).Float32()
+		b.Run(name, func(b *testing.B) {
+			a, x, c := make([]float32, 256*256), make([]float32, 256*256), make([]float32, 256*256)
+			for i := range a {
+				a[i], x[i] = float32(i%7), float32(i%5)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				k.Gemm(256, 256, 256, a, x, c)
+			}
+		})
+	}
+}
+
+func mustGet(b *testing.B, name string) backend.Backend {
+	bk, ok := backend.Get(name)
+	if !ok {
+		b.Fatalf("backend %q not found", name)
+	}
+	return bk
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package backendtest implements a conformance suite, checking the kernels
+// of a backend against the ones of the reference backend.
+package backendtest
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/backend"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Run checks every kernel of the backend b, for each data type, against the
+// reference backend.
+func Run(t *testing.T, b backend.Backend) {
+	ref := backend.Reference()
+	t.Run("float32", func(t *testing.T) {
+		run(t, b.Float32(), ref.Float32(), 1e-5)
+	})
+	t.Run("float64", func(t *testing.T) {
+		run(t, b.Float64(), ref.Float64(), 1e-12)
+	})
+}
+
+// sizes of the vectors used for testing, including one large enough to be
+// split by a parallel backend.
+var sizes = []int{0, 1, 3, 4, 7, 8, 9, 16, 33, 100, 1000, 1<<16 + 5}
+
+// shapes (m, k, n) of the matrix multiplications used for testing.
+var shapes = [][3]int{
+	{1, 1, 1}, {1, 5, 1}, {3, 4, 5}, {7, 9, 1}, {1, 9, 7},
+	{16, 32, 8}, {33, 17, 65}, {65, 70, 80}, {300, 250, 1},
+}
+
+func run[T float.DType](t *testing.T, k, ref backend.Kernels[T], eps float64) {
+	r := rand.New(rand.NewSource(42))
+	t.Run("Gemm", func(t *testing.T) { testGemm(t, r, k, ref, eps) })
+	t.Run("Gemv", func(t *testing.T) { testGemv(t, r, k, ref, eps, false) })
+	t.Run("GemvT", func(t *testing.T) { testGemv(t, r, k, ref, eps, true) })
+
+	binary := map[string][2]func(x1, x2, y []T){
+		"Add":  {k.Add, ref.Add},
+		"Sub":  {k.Sub, ref.Sub},
+		"Prod": {k.Prod, ref.Prod},
+		"Div":  {k.Div, ref.Div},
+	}
+	for name, fns := range binary {
+		t.Run(name, func(t *testing.T) { testBinary(t, r, fns[0], fns[1], eps) })
+	}
+
+	c := T(r.NormFloat64())
+	unary := map[string][2]func(x, y []T){
+		"AddConst": {func(x, y []T) { k.AddConst(c, x, y) }, func(x, y []T) { ref.AddConst(c, x, y) }},
+		"MulConst": {func(x, y []T) { k.MulConst(c, x, y) }, func(x, y []T) { ref.MulConst(c, x, y) }},
+		"Exp":      {k.Exp, ref.Exp},
+		"Sigmoid":  {k.Sigmoid, ref.Sigmoid},
+		"Tanh":     {k.Tanh, ref.Tanh},
+		"SiLU":     {k.SiLU, ref.SiLU},
+		"GELU":     {k.GELU, ref.GELU},
+		"ReLU":     {k.ReLU, ref.ReLU},
+	}
+	for name, fns := range unary {
+		t.Run(name, func(t *testing.T) { testUnary(t, r, fns[0], fns[1], eps, false) })
+	}
+	t.Run("Log", func(t *testing.T) { testUnary(t, r, k.Log, ref.Log, eps, true) })
+
+	t.Run("Dot", func(t *testing.T) { testDot(t, r, k, ref, eps) })
+	t.Run("Sum", func(t *testing.T) { testSum(t, r, k, ref, eps) })
+	t.Run("Max", func(t *testing.T) { testMax(t, r, k, ref) })
+}
+
+func testGemm[T float.DType](t *testing.T, r *rand.Rand, k, ref backend.Kernels[T], eps float64) {
+	for _, s := range shapes {
+		m, l, n := s[0], s[1], s[2]
+		a, b := randVec[T](r, m*l), randVec[T](r, l*n)
+		expected, bound := make([]T, m*n), make([]T, m*n)
+		ref.Gemm(m, l, n, a, b, expected)
+		ref.Gemm(m, l, n, abs(a), abs(b), bound)
+
+		actual := randVec[T](r, m*n) // garbage, to be overwritten
+		k.Gemm(m, l, n, a, b, actual)
+		requireClose(t, expected, actual, bound, eps, "Gemm %v", s)
+	}
+}
+
+func testGemv[T float.DType](t *testing.T, r *rand.Rand, k, ref backend.Kernels[T], eps float64, transposed bool) {
+	for _, s := range shapes {
+		m, n := s[0], s[1]
+		a := randVec[T](r, m*n)
+		gemv, refGemv, in, out := k.Gemv, ref.Gemv, n, m
+		if transposed {
+			gemv, refGemv, in, out = k.GemvT, ref.GemvT, m, n
+		}
+		x := randVec[T](r, in)
+		expected, bound := make([]T, out), make([]T, out)
+		refGemv(m, n, a, x, expected)
+		refGemv(m, n, abs(a), abs(x), bound)
+
+		actual := randVec[T](r, out)
+		gemv(m, n, a, x, actual)
+		requireClose(t, expected, actual, bound, eps, "%dx%d", m, n)
+	}
+}
+
+func testBinary[T float.DType](t *testing.T, r *rand.Rand, fn, refFn func(x1, x2, y []T), eps float64) {
+	for _, size := range sizes {
+		x1, x2 := randVec[T](r, size), randVec[T](r, size)
+		expected := make([]T, size)
+		refFn(x1, x2, expected)
+
+		actual := make([]T, size)
+		fn(x1, x2, actual)
+		requireClose(t, expected, actual, abs(expected), eps, "size %d", size)
+
+		fn(x1, x2, x1) // in place
+		requireClose(t, expected, x1, abs(expected), eps, "size %d, in place", size)
+	}
+}
+
+func testUnary[T float.DType](t *testing.T, r *rand.Rand, fn, refFn func(x, y []T), eps float64, positive bool) {
+	for _, size := range sizes {
+		x := randVec[T](r, size)
+		if positive {
+			x = abs(x)
+		} else if size > 8 {
+			copy(x, []T{-1000, -100, -20, -1e-4, 0, 1e-4, 20, 100, 1000})
+		}
+		expected := make([]T, size)
+		refFn(x, expected)
+
+		actual := make([]T, size)
+		fn(x, actual)
+		requireClose(t, expected, actual, abs(expected), eps, "size %d", size)
+
+		fn(x, x) // in place
+		requireClose(t, expected, x, abs(expected), eps, "size %d, in place", size)
+	}
+}
+
+func testDot[T float.DType](t *testing.T, r *rand.Rand, k, ref backend.Kernels[T], eps float64) {
+	for _, size := range sizes {
+		x1, x2 := randVec[T](r, size), randVec[T](r, size)
+		expected := []T{ref.Dot(x1, x2)}
+		bound := []T{ref.Dot(abs(x1), abs(x2))}
+		requireClose(t, expected, []T{k.Dot(x1, x2)}, bound, eps, "size %d", size)
+	}
+}
+
+func testSum[T float.DType](t *testing.T, r *rand.Rand, k, ref backend.Kernels[T], eps float64) {
+	for _, size := range sizes {
+		x := randVec[T](r, size)
+		expected := []T{ref.Sum(x)}
+		bound := []T{ref.Sum(abs(x))}
+		requireClose(t, expected, []T{k.Sum(x)}, bound, eps, "size %d", size)
+	}
+}
+
+func testMax[T float.DType](t *testing.T, r *rand.Rand, k, ref backend.Kernels[T]) {
+	for _, size := range sizes[1:] {
+		x := randVec[T](r, size)
+		if expected, actual := ref.Max(x), k.Max(x); expected != actual {
+			t.Fatalf("size %d: expected %v, actual %v", size, expected, actual)
+		}
+		for _, i := range []int{0, size / 2, size - 1} {
+			y := append([]T(nil), x...)
+			y[i] = T(math.NaN())
+			if actual := k.Max(y); !math.IsNaN(float64(actual)) {
+				t.Fatalf("size %d, NaN at %d: expected NaN, actual %v", size, i, actual)
+			}
+		}
+	}
+}
+
+// requireClose fails the test if any actual value differs from the expected
+// one by more than eps times the corresponding bound (at least 1).
+func requireClose[T float.DType](t *testing.T, expected, actual, bound []T, eps float64, format string, args ...any) {
+	t.Helper()
+	if len(expected) != len(actual) {
+		t.Fatalf(format+": expected length %d, actual %d", append(args, len(expected), len(actual))...)
+	}
+	for i, e := range expected {
+		a := actual[i]
+		if math.IsInf(float64(e), 0) && e == a {
+			continue
+		}
+		tol := eps * math.Max(1, float64(bound[i]))
+		if !(math.Abs(float64(e-a)) <= tol) {
+			t.Fatalf(format+": at %d expected %v, actual %v", append(args, i, e, a)...)
+		}
+	}
+}
+
+func randVec[T float.DType](r *rand.Rand, size int) []T {
+	v := make([]T, size)
+	for i := range v {
+		v[i] = T(r.NormFloat64())
+	}
+	return v
+}
+
+func abs[T float.DType](x []T) []T {
+	y := make([]T, len(x))
+	for i, v := range x {
+		y[i] = T(math.Abs(float64(v)))
+	}
+	return y
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package backend
+
+import (
+	"sync"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// minParallelCost is the minimum amount of work, in number of elements or
+// multiply-adds, assigned to each goroutine by the parallel backend.
+const minParallelCost = 1 << 15
+
+// NewParallel returns a backend which splits large inputs across up to
+// the given number of goroutines, delegating each part to the base backend.
+// Small inputs are processed by the base backend directly.
+func NewParallel(name string, base Backend, workers int) Backend {
+	return &parallelBackend{
+		name: name,
+		k32:  parallel[float32]{base: base.Float32(), workers: workers},
+		k64:  parallel[float64]{base: base.Float64(), workers: workers},
+	}
+}
+
+type parallelBackend struct {
+	name string
+	k32  parallel[float32]
+	k64  parallel[float64]
+}
+
+func (b *parallelBackend) Name() string              { return b.name }
+func (b *parallelBackend) Float32() Kernels[float32] { return b.k32 }
+func (b *parallelBackend) Float64() Kernels[float64] { return b.k64 }
+
+type parallel[T float.DType] struct {
+	base    Kernels[T]
+	workers int
+}
+
+// ranges splits [0, n) into contiguous ranges, one per goroutine, given the
+// total cost of the work. It returns a single range if the work is too
+// small to be worth splitting.
+func (p parallel[T]) ranges(n, cost int) [][2]int {
+	w := min(p.workers, n, cost/minParallelCost)
+	if w < 2 {
+		return [][2]int{{0, n}}
+	}
+	size := (n + w - 1) / w
+	rs := make([][2]int, 0, w)
+	for from := 0; from < n; from += size {
+		rs = append(rs, [2]int{from, min(from+size, n)})
+	}
+	return rs
+}
+
+// run calls fn for each range, concurrently if there is more than one.
+func run(rs [][2]int, fn func(i, from, to int)) {
+	if len(rs) == 1 {
+		fn(0, rs[0][0], rs[0][1])
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(rs))
+	for i, r := range rs {
+		go func(i, from, to int) {
+			defer wg.Done()
+			fn(i, from, to)
+		}(i, r[0], r[1])
+	}
+	wg.Wait()
+}
+
+func (p parallel[T]) Gemm(m, k, n int, a, b, c []T) {
+	run(p.ranges(m, m*k*n), func(_, from, to int) {
+		p.base.Gemm(to-from, k, n, a[from*k:to*k], b, c[from*n:to*n])
+	})
+}
+
+func (p parallel[T]) Gemv(m, n int, a, x, y []T) {
+	run(p.ranges(m, m*n), func(_, from, to int) {
+		p.base.Gemv(to-from, n, a[from*n:to*n], x[:n], y[from:to])
+	})
+}
+
+func (p parallel[T]) GemvT(m, n int, a, x, y []T) {
+	rs := p.ranges(m, m*n)
+	if len(rs) == 1 {
+		p.base.GemvT(m, n, a, x, y)
+		return
+	}
+	partials := make([][]T, len(rs))
+	run(rs, func(i, from, to int) {
+		partials[i] = make([]T, n)
+		p.base.GemvT(to-from, n, a[from*n:to*n], x[from:to], partials[i])
+	})
+	copy(y[:n], partials[0])
+	for _, partial := range partials[1:] {
+		p.base.Add(y[:n], partial, y[:n])
+	}
+}
+
+// elementwise applies fn to matching ranges of x1, x2 and y.
+func (p parallel[T]) elementwise(x1, x2, y []T, fn func(x1, x2, y []T)) {
+	run(p.ranges(len(x1), len(x1)), func(_, from, to int) {
+		fn(x1[from:to], x2[from:to], y[from:to])
+	})
+}
+
+// unary applies fn to matching ranges of x and y.
+func (p parallel[T]) unary(x, y []T, fn func(x, y []T)) {
+	run(p.ranges(len(x), len(x)), func(_, from, to int) {
+		fn(x[from:to], y[from:to])
+	})
+}
+
+// reduce applies fn to the ranges of x and combines the partial results.
+func (p parallel[T]) reduce(x []T, fn func(from, to int) T, combine func(a, b T) T) T {
+	rs := p.ranges(len(x), len(x))
+	partials := make([]T, len(rs))
+	run(rs, func(i, from, to int) {
+		partials[i] = fn(from, to)
+	})
+	r := partials[0]
+	for _, v := range partials[1:] {
+		r = combine(r, v)
+	}
+	return r
+}
+
+func (p parallel[T]) Add(x1, x2, y []T)  { p.elementwise(x1, x2, y, p.base.Add) }
+func (p parallel[T]) Sub(x1, x2, y []T)  { p.elementwise(x1, x2, y, p.base.Sub) }
+func (p parallel[T]) Prod(x1, x2, y []T) { p.elementwise(x1, x2, y, p.base.Prod) }
+func (p parallel[T]) Div(x1, x2, y []T)  { p.elementwise(x1, x2, y, p.base.Div) }
+
+func (p parallel[T]) AddConst(c T, x, y []T) {
+	p.unary(x, y, func(x, y []T) { p.base.AddConst(c, x, y) })
+}
+
+func (p parallel[T]) MulConst(c T, x, y []T) {
+	p.unary(x, y, func(x, y []T) { p.base.MulConst(c, x, y) })
+}
+
+func (p parallel[T]) Dot(x1, x2 []T) T {
+	return p.reduce(x1, func(from, to int) T { return p.base.Dot(x1[from:to], x2[from:to]) }, add[T])
+}
+
+func (p parallel[T]) Sum(x []T) T {
+	return p.reduce(x, func(from, to int) T { return p.base.Sum(x[from:to]) }, add[T])
+}
+
+func (p parallel[T]) Max(x []T) T {
+	return p.reduce(x, func(from, to int) T { return p.base.Max(x[from:to]) }, maximum[T])
+}
+
+func (p parallel[T]) Exp(x, y []T)     { p.unary(x, y, p.base.Exp) }
+func (p parallel[T]) Log(x, y []T)     { p.unary(x, y, p.base.Log) }
+func (p parallel[T]) Sigmoid(x, y []T) { p.unary(x, y, p.base.Sigmoid) }
+func (p parallel[T]) Tanh(x, y []T)    { p.unary(x, y, p.base.Tanh) }
+func (p parallel[T]) SiLU(x, y []T)    { p.unary(x, y, p.base.SiLU) }
+func (p parallel[T]) GELU(x, y []T)    { p.unary(x, y, p.base.GELU) }
+func (p parallel[T]) ReLU(x, y []T)    { p.unary(x, y, p.base.ReLU) }
+
+func add[T float.DType](a, b T) T {
+	return a + b
+}
+
+func maximum[T float.DType](a, b T) T {
+	return max(a, b)
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package backend
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Reference returns the backend implemented with plain Go loops.
+// It is the baseline the other backends are checked against.
+func Reference() Backend {
+	return referenceBackend{}
+}
+
+type referenceBackend struct{}
+
+func (referenceBackend) Name() string              { return ReferenceName }
+func (referenceBackend) Float32() Kernels[float32] { return reference[float32]{} }
+func (referenceBackend) Float64() Kernels[float64] { return reference[float64]{} }
+
+type reference[T float.DType] struct{}
+
+func (reference[T]) Gemm(m, k, n int, a, b, c []T) {
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			var sum T
+			for l := 0; l < k; l++ {
+				sum += a[i*k+l] * b[l*n+j]
+			}
+			c[i*n+j] = sum
+		}
+	}
+}
+
+func (reference[T]) Gemv(m, n int, a, x, y []T) {
+	for i := 0; i < m; i++ {
+		var sum T
+		for j := 0; j < n; j++ {
+			sum += a[i*n+j] * x[j]
+		}
+		y[i] = sum
+	}
+}
+
+func (reference[T]) GemvT(m, n int, a, x, y []T) {
+	for j := 0; j < n; j++ {
+		var sum T
+		for i := 0; i < m; i++ {
+			sum += a[i*n+j] * x[i]
+		}
+		y[j] = sum
+	}
+}
+
+func (reference[T]) Add(x1, x2, y []T) {
+	for i := range x1 {
+		y[i] = x1[i] + x2[i]
+	}
+}
+
+func (reference[T]) Sub(x1, x2, y []T) {
+	for i := range x1 {
+		y[i] = x1[i] - x2[i]
+	}
+}
+
+func (reference[T]) Prod(x1, x2, y []T) {
+	for i := range x1 {
+		y[i] = x1[i] * x2[i]
+	}
+}
+
+func (reference[T]) Div(x1, x2, y []T) {
+	for i := range x1 {
+		y[i] = x1[i] / x2[i]
+	}
+}
+
+func (reference[T]) AddConst(c T, x, y []T) {
+	for i, v := range x {
+		y[i] = v + c
+	}
+}
+
+func (reference[T]) MulConst(c T, x, y []T) {
+	for i, v := range x {
+		y[i] = v * c
+	}
+}
+
+func (reference[T]) Dot(x1, x2 []T) T {
+	var sum T
+	for i := range x1 {
+		sum += x1[i] * x2[i]
+	}
+	return sum
+}
+
+func (reference[T]) Sum(x []T) T {
+	var sum T
+	for _, v := range x {
+		sum += v
+	}
+	return sum
+}
+
+func (reference[T]) Max(x []T) T {
+	m := x[0]
+	for _, v := range x[1:] {
+		m = max(m, v)
+	}
+	return m
+}
+
+func (reference[T]) Exp(x, y []T) {
+	for i, v := range x {
+		y[i] = T(math.Exp(float64(v)))
+	}
+}
+
+func (reference[T]) Log(x, y []T) {
+	for i, v := range x {
+		y[i] = T(math.Log(float64(v)))
+	}
+}
+
+func (reference[T]) Sigmoid(x, y []T) {
+	for i, v := range x {
+		y[i] = T(1 / (1 + math.Exp(-float64(v))))
+	}
+}
+
+func (reference[T]) Tanh(x, y []T) {
+	for i, v := range x {
+		y[i] = T(math.Tanh(float64(v)))
+	}
+}
+
+func (reference[T]) SiLU(x, y []T) {
+	for i, v := range x {
+		y[i] = T(float64(v) / (1 + math.Exp(-float64(v))))
+	}
+}
+
+func (reference[T]) GELU(x, y []T) {
+	for i, v := range x {
+		f := float64(v)
+		y[i] = T(0.5 * f * (1 + math.Tanh(math.Sqrt(2/math.Pi)*(f+0.044715*f*f*f))))
+	}
+}
+
+func (reference[T]) ReLU(x, y []T) {
+	for i, v := range x {
+		y[i] = max(0, v)
+	}
+}
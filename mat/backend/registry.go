@@ -0,0 +1,110 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package backend
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Names of the backends registered by default.
+const (
+	ReferenceName = "reference"
+	SIMDName      = "simd"
+	ParallelName  = "parallel"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Backend)
+	current    atomic.Pointer[active]
+)
+
+// active caches the kernels of the current backend, to avoid repeated
+// interface conversions on each operation.
+type active struct {
+	backend Backend
+	k32     Kernels[float32]
+	k64     Kernels[float64]
+}
+
+func init() {
+	simd := SIMD()
+	for _, b := range []Backend{Reference(), simd, NewParallel(ParallelName, simd, runtime.GOMAXPROCS(0))} {
+		if err := Register(b); err != nil {
+			panic(err)
+		}
+	}
+	if err := Use(SIMDName); err != nil {
+		panic(err)
+	}
+}
+
+// Register makes a backend available by its name.
+// It returns an error if a backend with the same name is already registered.
+func Register(b Backend) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[b.Name()]; ok {
+		return fmt.Errorf("backend: %q is already registered", b.Name())
+	}
+	registry[b.Name()] = b
+	return nil
+}
+
+// Get returns the backend registered with the given name.
+func Get(name string) (Backend, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	b, ok := registry[name]
+	return b, ok
+}
+
+// Names returns the sorted names of the registered backends.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Use sets the registered backend with the given name as the current one.
+// It is safe to call Use concurrently with the operations, but each
+// operation runs entirely on the backend that was current when it started.
+func Use(name string) error {
+	b, ok := Get(name)
+	if !ok {
+		return fmt.Errorf("backend: %q is not registered", name)
+	}
+	current.Store(&active{backend: b, k32: b.Float32(), k64: b.Float64()})
+	return nil
+}
+
+// Current returns the current backend.
+func Current() Backend {
+	return current.Load().backend
+}
+
+// For returns the kernels of the current backend for the data type T.
+func For[T float.DType]() Kernels[T] {
+	a := current.Load()
+	switch any(T(0)).(type) {
+	case float32:
+		return any(a.k32).(Kernels[T])
+	case float64:
+		return any(a.k64).(Kernels[T])
+	default:
+		panic(fmt.Sprintf("backend: unexpected type %T", T(0)))
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package backend
+
+import (
+	"github.com/nlpodyssey/spago/mat/internal/f32"
+	"github.com/nlpodyssey/spago/mat/internal/f32/asm32"
+	"github.com/nlpodyssey/spago/mat/internal/f64"
+	"github.com/nlpodyssey/spago/mat/internal/f64/asm64"
+	"github.com/nlpodyssey/spago/mat/internal/matfuncs"
+)
+
+// SIMD returns the backend built on the assembly and vectorized kernels of
+// the internal packages, falling back to pure Go on unsupported platforms.
+func SIMD() Backend {
+	return simdBackend{}
+}
+
+type simdBackend struct{}
+
+func (simdBackend) Name() string              { return SIMDName }
+func (simdBackend) Float32() Kernels[float32] { return simd32{} }
+func (simdBackend) Float64() Kernels[float64] { return simd64{} }
+
+type simd32 struct{}
+
+func (s simd32) Gemm(m, k, n int, a, b, c []float32) {
+	if n == 1 {
+		s.Gemv(m, k, a, b, c)
+		return
+	}
+	clear(c)
+	f32.MatrixMul(m, k, n, a, b, c)
+}
+
+func (simd32) Gemv(m, n int, a, x, y []float32) {
+	from := 0
+	for i := range y[:m] {
+		to := from + n
+		y[i] = matfuncs.DotProd32(a[from:to], x)
+		from = to
+	}
+}
+
+func (simd32) GemvT(m, n int, a, x, y []float32) {
+	clear(y)
+	from := 0
+	for _, xv := range x[:m] {
+		to := from + n
+		asm32.AxpyUnitaryTo(y, xv, a[from:to], y)
+		from = to
+	}
+}
+
+func (simd32) Add(x1, x2, y []float32)            { matfuncs.Add32(x1, x2, y) }
+func (simd32) Sub(x1, x2, y []float32)            { matfuncs.Sub32(x1, x2, y) }
+func (simd32) Prod(x1, x2, y []float32)           { prod(x1, x2, y) }
+func (simd32) Div(x1, x2, y []float32)            { matfuncs.Div32(x1, x2, y) }
+func (simd32) AddConst(c float32, x, y []float32) { matfuncs.AddConst32(c, x, y) }
+func (simd32) MulConst(c float32, x, y []float32) { matfuncs.MulConst32(c, x, y) }
+func (simd32) Dot(x1, x2 []float32) float32       { return matfuncs.DotProd32(x1, x2) }
+func (simd32) Sum(x []float32) float32            { return matfuncs.Sum32(x) }
+func (simd32) Max(x []float32) float32            { return maxOf(x) }
+func (simd32) Exp(x, y []float32)                 { matfuncs.Exp32(x, y) }
+func (simd32) Log(x, y []float32)                 { matfuncs.Log32(x, y) }
+func (simd32) Sigmoid(x, y []float32)             { matfuncs.Sigmoid32(x, y) }
+func (simd32) Tanh(x, y []float32)                { matfuncs.Tanh32(x, y) }
+func (simd32) SiLU(x, y []float32)                { matfuncs.SiLU32(x, y) }
+func (simd32) GELU(x, y []float32)                { matfuncs.GELU32(x, y) }
+func (simd32) ReLU(x, y []float32)                { relu(x, y) }
+
+type simd64 struct{}
+
+func (s simd64) Gemm(m, k, n int, a, b, c []float64) {
+	if n == 1 {
+		s.Gemv(m, k, a, b, c)
+		return
+	}
+	clear(c)
+	f64.MatrixMul(m, k, n, a, b, c)
+}
+
+func (simd64) Gemv(m, n int, a, x, y []float64) {
+	asm64.GemvN(uintptr(m), uintptr(n), 1, a, uintptr(n), x, 1, 0, y, 1)
+}
+
+func (simd64) GemvT(m, n int, a, x, y []float64) {
+	clear(y)
+	from := 0
+	for _, xv := range x[:m] {
+		to := from + n
+		asm64.AxpyUnitaryTo(y, xv, a[from:to], y)
+		from = to
+	}
+}
+
+func (simd64) Add(x1, x2, y []float64)            { matfuncs.Add64(x1, x2, y) }
+func (simd64) Sub(x1, x2, y []float64)            { matfuncs.Sub64(x1, x2, y) }
+func (simd64) Prod(x1, x2, y []float64)           { prod(x1, x2, y) }
+func (simd64) Div(x1, x2, y []float64)            { matfuncs.Div64(x1, x2, y) }
+func (simd64) AddConst(c float64, x, y []float64) { matfuncs.AddConst64(c, x, y) }
+func (simd64) MulConst(c float64, x, y []float64) { matfuncs.MulConst64(c, x, y) }
+func (simd64) Dot(x1, x2 []float64) float64       { return matfuncs.DotProd64(x1, x2) }
+func (simd64) Sum(x []float64) float64            { return matfuncs.Sum64(x) }
+func (simd64) Max(x []float64) float64            { return maxOf(x) }
+func (simd64) Exp(x, y []float64)                 { matfuncs.Exp64(x, y) }
+func (simd64) Log(x, y []float64)                 { matfuncs.Log64(x, y) }
+func (simd64) Sigmoid(x, y []float64)             { matfuncs.Sigmoid64(x, y) }
+func (simd64) Tanh(x, y []float64)                { matfuncs.Tanh64(x, y) }
+func (simd64) SiLU(x, y []float64)                { matfuncs.SiLU64(x, y) }
+func (simd64) GELU(x, y []float64)                { matfuncs.GELU64(x, y) }
+func (simd64) ReLU(x, y []float64)                { relu(x, y) }
+
+func prod[T float32 | float64](x1, x2, y []T) {
+	if len(x1) == 0 {
+		return
+	}
+	_ = y[len(x1)-1]
+	_ = x2[len(x1)-1]
+	for i, v := range x1 {
+		y[i] = v * x2[i]
+	}
+}
+
+func relu[T float32 | float64](x, y []T) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	for i, v := range x {
+		y[i] = max(0, v)
+	}
+}
+
+func maxOf[T float32 | float64](x []T) T {
+	m := x[0]
+	for _, v := range x[1:] {
+		m = max(m, v)
+	}
+	return m
+}
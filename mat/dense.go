@@ -8,14 +8,13 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"sort"
 	"sync"
 
+	"github.com/nlpodyssey/spago/mat/backend"
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/mat/internal/f32"
-	"github.com/nlpodyssey/spago/mat/internal/f32/asm32"
-	"github.com/nlpodyssey/spago/mat/internal/f64"
 	"github.com/nlpodyssey/spago/mat/internal/f64/asm64"
-	"github.com/nlpodyssey/spago/mat/internal/matfuncs"
 )
 
 // A Dense matrix implementation.
@@ -25,6 +24,9 @@ type Dense[T float.DType] struct {
 	grad         *Dense[T]
 	shape        []int
 	requiresGrad bool // default: false
+	// arena is the arena the results of the operations on the matrix are
+	// drawn from, if any (see WithArena).
+	arena *Arena
 }
 
 // makeDense returns a Dense matrix.
@@ -42,6 +44,43 @@ func malloc[T float.DType](size int) []T {
 	return make([]T, size)
 }
 
+// newDenseIn returns a new zeroed Dense matrix of the given shape, whose
+// buffer is drawn from the arena a, if not nil. The new matrix keeps the
+// arena for the results of its own operations.
+func newDenseIn[T float.DType](a *Arena, shape ...int) *Dense[T] {
+	size := calculateSize(shape)
+	if a == nil {
+		return makeDense[T](malloc[T](size), shape...)
+	}
+	out := makeDense[T](arenaAlloc[T](a, size), shape...)
+	out.arena = a
+	return out
+}
+
+// arenaWith returns the arena of the receiver or, if it has none, the one of
+// the first operand having it.
+func (d *Dense[T]) arenaWith(operands ...Matrix) *Arena {
+	if d.arena != nil {
+		return d.arena
+	}
+	return arenaOf(operands...)
+}
+
+// arenaOf returns the arena of the first matrix having it, if any.
+func arenaOf(ms ...Matrix) *Arena {
+	for _, m := range ms {
+		if d, ok := m.(interface{ dataArena() *Arena }); ok && d.dataArena() != nil {
+			return d.dataArena()
+		}
+	}
+	return nil
+}
+
+// dataArena returns the arena of the matrix, if any.
+func (d *Dense[T]) dataArena() *Arena {
+	return d.arena
+}
+
 // Shape returns the size in each dimension.
 func (d *Dense[_]) Shape() []int {
 	return d.shape
@@ -76,14 +115,14 @@ func (d *Dense[T]) SetData(data float.Slice) {
 // ZerosLike returns a new matrix with the same dimensions of the
 // receiver, initialized with zeroes.
 func (d *Dense[T]) ZerosLike() Matrix {
-	return NewDense[T](WithShape(d.shape...))
+	return newDenseIn[T](d.arena, d.shape...)
 }
 
 // OnesLike returns a new matrix with the same dimensions of the
 // receiver, initialized with ones.
 func (d *Dense[T]) OnesLike() Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
+	out := newDenseIn[T](d.arena, d.shape...)
 	data := out.data // avoid bounds check in loop
 	for i := range data {
 		data[i] = 1.0
@@ -189,7 +228,7 @@ func (d *Dense[T]) ExtractRow(i int) Matrix {
 		panic("mat: index out of range")
 	}
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.shape[1]), 1, d.shape[1])
+	out := newDenseIn[T](d.arena, 1, d.shape[1])
 	start := i * d.shape[1]
 	copy(out.data, d.data[start:start+d.shape[1]])
 	return out
@@ -202,7 +241,7 @@ func (d *Dense[T]) ExtractColumn(i int) Matrix {
 		panic("mat: index out of range")
 	}
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.shape[0]), d.shape[0], 1)
+	out := newDenseIn[T](d.arena, d.shape[0], 1)
 	dData := d.data
 	outData := out.data
 	for k := range outData {
@@ -223,7 +262,7 @@ func (d *Dense[T]) Slice(fromRow, fromCol, toRow, toCol int) Matrix {
 	}
 
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	y := makeDense[T](malloc[T]((toRow-fromRow)*(toCol-fromCol)), toRow-fromRow, toCol-fromCol)
+	y := newDenseIn[T](d.arena, toRow-fromRow, toCol-fromCol)
 
 	if fromCol == 0 && toCol == dCols {
 		copy(y.data, d.data[fromRow*dCols:toRow*dCols])
@@ -255,7 +294,9 @@ func (d *Dense[T]) Reshape(shape ...int) Matrix {
 		panic(fmt.Sprintf("mat: wrong matrix dimensions. Size (rows*cols) must be: %d", len(d.data)))
 	}
 
-	return NewDense[T](WithShape(rows, cols), WithBacking(copySlice(d.data)))
+	out := newDenseIn[T](d.arena, rows, cols)
+	copy(out.data, d.data)
+	return out
 }
 
 func copySlice[T float.DType](src []T) []T {
@@ -287,7 +328,7 @@ func (d *Dense[T]) ReshapeInPlace(shape ...int) Matrix {
 // "flattened" row-major ordered representation of the initial matrix.
 func (d *Dense[T]) Flatten() Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](len(d.data)), 1, len(d.data))
+	out := newDenseIn[T](d.arena, 1, len(d.data))
 	copy(out.data, d.data)
 	return out
 }
@@ -330,7 +371,7 @@ func (d *Dense[T]) T() Matrix {
 	dCols := d.shape[1]
 
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	m := makeDense[T](malloc[T](dCols*dRows), dCols, dRows)
+	m := newDenseIn[T](d.arena, dCols, dRows)
 	if IsVector(d) {
 		copy(m.data, d.data)
 		return m
@@ -414,17 +455,8 @@ func (d *Dense[T]) Add(other Matrix) Matrix {
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	out := NewDense[T](WithShape(d.shape...))
-	switch any(T(0)).(type) {
-	case float32:
-		otherData := float32Data(other)
-		matfuncs.Add32(any(d.data).([]float32), otherData, any(out.data).([]float32))
-	case float64:
-		otherData := float64Data(other)
-		matfuncs.Add64(any(d.data).([]float64), otherData, any(out.data).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	out := newDenseIn[T](d.arenaWith(other), d.shape...)
+	backend.For[T]().Add(d.data, Data[T](other), out.data)
 	return out
 }
 
@@ -433,48 +465,21 @@ func (d *Dense[T]) AddInPlace(other Matrix) Matrix {
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	switch any(T(0)).(type) {
-	case float32:
-		dData := any(d.data).([]float32)
-		otherData := float32Data(other)
-		matfuncs.Add32(dData, otherData, dData)
-	case float64:
-		dData := any(d.data).([]float64)
-		otherData := float64Data(other)
-		matfuncs.Add64(dData, otherData, dData)
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	backend.For[T]().Add(d.data, Data[T](other), d.data)
 	return d
 }
 
 // AddScalar performs the addition between the matrix and the given value.
 func (d *Dense[T]) AddScalar(n float64) Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
-	switch any(T(0)).(type) {
-	case float32:
-		matfuncs.AddConst32(float32(n), any(d.data).([]float32), any(out.data).([]float32))
-	case float64:
-		matfuncs.AddConst64(n, any(d.data).([]float64), any(out.data).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	out := newDenseIn[T](d.arena, d.shape...)
+	backend.For[T]().AddConst(T(n), d.data, out.data)
 	return out
 }
 
 // AddScalarInPlace adds the scalar to all values of the matrix.
 func (d *Dense[T]) AddScalarInPlace(n float64) Matrix {
-	switch any(T(0)).(type) {
-	case float32:
-		dData := any(d.data).([]float32)
-		matfuncs.AddConst32(float32(n), dData, dData)
-	case float64:
-		dData := any(d.data).([]float64)
-		matfuncs.AddConst64(n, dData, dData)
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	backend.For[T]().AddConst(T(n), d.data, d.data)
 	return d
 }
 
@@ -483,17 +488,8 @@ func (d *Dense[T]) Sub(other Matrix) Matrix {
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	out := NewDense[T](WithShape(d.shape...))
-	switch any(T(0)).(type) {
-	case float32:
-		otherData := float32Data(other)
-		matfuncs.Sub32(any(d.data).([]float32), otherData, any(out.data).([]float32))
-	case float64:
-		otherData := float64Data(other)
-		matfuncs.Sub64(any(d.data).([]float64), otherData, any(out.data).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	out := newDenseIn[T](d.arenaWith(other), d.shape...)
+	backend.For[T]().Sub(d.data, Data[T](other), out.data)
 	return out
 }
 
@@ -502,46 +498,21 @@ func (d *Dense[T]) SubInPlace(other Matrix) Matrix {
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	switch any(T(0)).(type) {
-	case float32:
-		otherData := float32Data(other)
-		matfuncs.Sub32(any(d.data).([]float32), otherData, any(d.data).([]float32))
-	case float64:
-		otherData := float64Data(other)
-		matfuncs.Sub64(any(d.data).([]float64), otherData, any(d.data).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	backend.For[T]().Sub(d.data, Data[T](other), d.data)
 	return d
 }
 
 // SubScalar performs a subtraction between the matrix and the given value.
 func (d *Dense[T]) SubScalar(n float64) Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
-	switch any(T(0)).(type) {
-	case float32:
-		matfuncs.AddConst32(float32(-n), any(d.data).([]float32), any(out.data).([]float32))
-	case float64:
-		matfuncs.AddConst64(-n, any(d.data).([]float64), any(out.data).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	out := newDenseIn[T](d.arena, d.shape...)
+	backend.For[T]().AddConst(T(-n), d.data, out.data)
 	return out
 }
 
 // SubScalarInPlace subtracts the scalar from the receiver's values.
 func (d *Dense[T]) SubScalarInPlace(n float64) Matrix {
-	switch any(T(0)).(type) {
-	case float32:
-		dData := any(d.data).([]float32)
-		matfuncs.AddConst32(float32(-n), dData, dData)
-	case float64:
-		dData := any(d.data).([]float64)
-		matfuncs.AddConst64(-n, dData, dData)
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	backend.For[T]().AddConst(T(-n), d.data, d.data)
 	return d
 }
 
@@ -550,23 +521,9 @@ func (d *Dense[T]) Prod(other Matrix) Matrix {
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
-
-	// Avoid bounds checks in loop
-	dData := d.data
-	oData := Data[T](other)
-	outData := out.data
-	lastIndex := len(oData) - 1
-	if lastIndex < 0 {
-		return out
-	}
-	_ = outData[lastIndex]
-	_ = dData[lastIndex]
-	for i := lastIndex; i >= 0; i-- {
-		outData[i] = dData[i] * oData[i]
-	}
+	out := newDenseIn[T](d.arenaWith(other), d.shape...)
+	backend.For[T]().Prod(d.data, Data[T](other), out.data)
 	return out
 }
 
@@ -575,45 +532,21 @@ func (d *Dense[T]) ProdInPlace(other Matrix) Matrix {
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	dData := d.data
-	if len(dData) == 0 {
-		return d
-	}
-	oData := Data[T](other)
-	_ = dData[len(oData)-1]
-	for i, val := range oData {
-		dData[i] *= val
-	}
+	backend.For[T]().Prod(d.data, Data[T](other), d.data)
 	return d
 }
 
 // ProdScalar returns the multiplication between the matrix and the given value.
 func (d *Dense[T]) ProdScalar(n float64) Matrix {
-	out := NewDense[T](WithShape(d.shape...))
-	switch any(T(0)).(type) {
-	case float32:
-		matfuncs.MulConst32(float32(n), any(d.data).([]float32), any(out.data).([]float32))
-	case float64:
-		matfuncs.MulConst64(n, any(d.data).([]float64), any(out.data).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	out := newDenseIn[T](d.arena, d.shape...)
+	backend.For[T]().MulConst(T(n), d.data, out.data)
 	return out
 }
 
 // ProdScalarInPlace performs the in-place multiplication between the
 // matrix and the given value.
 func (d *Dense[T]) ProdScalarInPlace(n float64) Matrix {
-	switch any(T(0)).(type) {
-	case float32:
-		dData := any(d.data).([]float32)
-		matfuncs.MulConst32(float32(n), dData, dData)
-	case float64:
-		dData := any(d.data).([]float64)
-		matfuncs.MulConst64(n, dData, dData)
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	backend.For[T]().MulConst(T(n), d.data, d.data)
 	return d
 }
 
@@ -623,16 +556,7 @@ func (d *Dense[T]) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
 	if !SameDims(d, m) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	switch any(T(0)).(type) {
-	case float32:
-		mData := float32Data(m)
-		matfuncs.MulConst32(float32(n), mData, any(d.data).([]float32))
-	case float64:
-		mData := float64Data(m)
-		matfuncs.MulConst64(n, mData, any(d.data).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	backend.For[T]().MulConst(T(n), Data[T](m), d.data)
 	return d
 }
 
@@ -641,17 +565,8 @@ func (d *Dense[T]) Div(other Matrix) Matrix {
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	out := NewDense[T](WithShape(d.shape...))
-	switch any(T(0)).(type) {
-	case float32:
-		otherData := float32Data(other)
-		matfuncs.Div32(any(d.data).([]float32), otherData, any(out.data).([]float32))
-	case float64:
-		otherData := float64Data(other)
-		matfuncs.Div64(any(d.data).([]float64), otherData, any(out.data).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	out := newDenseIn[T](d.arenaWith(other), d.shape...)
+	backend.For[T]().Div(d.data, Data[T](other), out.data)
 	return out
 }
 
@@ -660,18 +575,7 @@ func (d *Dense[T]) DivInPlace(other Matrix) Matrix {
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	switch any(T(0)).(type) {
-	case float32:
-		dData := any(d.data).([]float32)
-		otherData := float32Data(other)
-		matfuncs.Div32(dData, otherData, dData)
-	case float64:
-		dData := any(d.data).([]float64)
-		otherData := float64Data(other)
-		matfuncs.Div64(dData, otherData, dData)
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	backend.For[T]().Div(d.data, Data[T](other), d.data)
 	return d
 }
 
@@ -685,69 +589,14 @@ func (d *Dense[T]) Mul(other Matrix) Matrix {
 	if d.shape[1] != otherRows {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	outRows := d.shape[0]
-	outCols := otherCols
-
-	switch any(T(0)).(type) {
-	case float32:
-		otherData := float32Data(other)
-		if outCols != 1 {
-			out := makeDense[float32](malloc[float32](outRows*outCols), outRows, outCols)
-			f32.MatrixMul(
-				d.shape[0],                // aRows
-				d.shape[1],                // aCols
-				otherCols,                 // bCols
-				any(d.data).([]float32),   // a
-				otherData,                 // b
-				any(out.data).([]float32), // c
-			)
-			return out
-		}
-
-		// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-		out := makeDense[float32](malloc[float32](outRows*outCols), outRows, outCols)
-		dData := any(d.data).([]float32)
-		outData := any(out.data).([]float32)
-
-		dCols := d.shape[1]
-		from := 0
-		for i := range outData {
-			to := from + dCols
-			outData[i] = matfuncs.DotProd32(dData[from:to], otherData)
-			from = to
-		}
-		return out
-	case float64:
-		out := makeDense[float64](malloc[float64](outRows*outCols), outRows, outCols)
-		otherData := float64Data(other)
-		if outCols != 1 {
-			f64.MatrixMul(
-				d.shape[0],                // aRows
-				d.shape[1],                // aCols
-				otherCols,                 // bCols
-				any(d.data).([]float64),   // a
-				otherData,                 // b
-				any(out.data).([]float64), // c
-			)
-			return out
-		}
-
-		asm64.GemvN(
-			uintptr(d.shape[0]),       // m
-			uintptr(d.shape[1]),       // n
-			1,                         // alpha
-			any(d.data).([]float64),   // a
-			uintptr(d.shape[1]),       // lda
-			otherData,                 // x
-			1,                         // incX
-			0,                         // beta
-			any(out.data).([]float64), // y
-			1,                         // incY
-		)
+	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
+	out := newDenseIn[T](d.arenaWith(other), d.shape[0], otherCols)
+	if otherCols == 1 {
+		backend.For[T]().Gemv(d.shape[0], d.shape[1], d.data, Data[T](other), out.data)
 		return out
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
 	}
+	backend.For[T]().Gemm(d.shape[0], d.shape[1], otherCols, d.data, Data[T](other), out.data)
+	return out
 }
 
 // MulT performs the matrix multiplication row by column.
@@ -764,41 +613,9 @@ func (d *Dense[T]) MulT(other Matrix) Matrix {
 	if otherCols != 1 {
 		panic("mat: the other matrix must have exactly 1 column")
 	}
-
-	switch any(T(0)).(type) {
-	case float32:
-		out := makeDense[float32](malloc[float32](d.shape[1]*otherCols), d.shape[1], otherCols)
-		otherData := float32Data(other)
-
-		dCols := d.shape[1]
-		dData := any(d.data).([]float32)
-		outData := any(out.data).([]float32)
-
-		from := 0
-		for _, otherVal := range otherData {
-			to := from + dCols
-			asm32.AxpyUnitaryTo(outData, otherVal, dData[from:to], outData)
-			from = to
-		}
-		return out
-	case float64:
-		out := makeDense[float64](malloc[float64](d.shape[1]*otherCols), d.shape[1], otherCols)
-		otherData := float64Data(other)
-
-		dCols := d.shape[1]
-		dData := any(d.data).([]float64)
-		outData := any(out.data).([]float64)
-
-		from := 0
-		for _, otherVal := range otherData {
-			to := from + dCols
-			asm64.AxpyUnitaryTo(outData, otherVal, dData[from:to], outData)
-			from = to
-		}
-		return out
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	out := newDenseIn[T](d.arenaWith(other), d.shape[1], 1)
+	backend.For[T]().GemvT(d.shape[0], d.shape[1], d.data, Data[T](other), out.data)
+	return out
 }
 
 // DotUnitary returns the dot product of two vectors as a scalar Matrix.
@@ -806,16 +623,7 @@ func (d *Dense[T]) DotUnitary(other Matrix) Matrix {
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
-	switch any(T(0)).(type) {
-	case float32:
-		otherData := float32Data(other)
-		return Scalar(matfuncs.DotProd32(any(d.data).([]float32), otherData))
-	case float64:
-		otherData := float64Data(other)
-		return Scalar(matfuncs.DotProd64(any(d.data).([]float64), otherData))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	return Scalar(backend.For[T]().Dot(d.data, Data[T](other)))
 }
 
 // ClipInPlace clips in place each value of the matrix.
@@ -847,7 +655,7 @@ func (d *Dense[T]) Maximum(other Matrix) Matrix {
 		panic("mat: matrices have incompatible dimensions")
 	}
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
+	out := newDenseIn[T](d.arenaWith(other), d.shape...)
 	dData := d.data
 	if len(dData) == 0 {
 		return out
@@ -874,7 +682,7 @@ func (d *Dense[T]) Minimum(other Matrix) Matrix {
 		panic("mat: matrices have incompatible dimensions")
 	}
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
+	out := newDenseIn[T](d.arenaWith(other), d.shape...)
 	dData := d.data
 	if len(dData) == 0 {
 		return out
@@ -898,7 +706,7 @@ func (d *Dense[T]) Minimum(other Matrix) Matrix {
 // Abs returns a new matrix applying the absolute value function to all elements.
 func (d *Dense[T]) Abs() Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
+	out := newDenseIn[T](d.arena, d.shape...)
 	dData := d.data
 	if len(dData) == 0 {
 		return out
@@ -915,7 +723,7 @@ func (d *Dense[T]) Abs() Matrix {
 // to all elements of the matrix.
 func (d *Dense[T]) Pow(power float64) Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
+	out := newDenseIn[T](d.arena, d.shape...)
 	dData := d.data
 	if len(dData) == 0 {
 		return out
@@ -931,7 +739,7 @@ func (d *Dense[T]) Pow(power float64) Matrix {
 // Sqrt returns a new matrix applying the square root function to all elements.
 func (d *Dense[T]) Sqrt() Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
+	out := newDenseIn[T](d.arena, d.shape...)
 	inData := d.data
 	lastIndex := len(inData) - 1
 	if lastIndex < 0 {
@@ -948,64 +756,24 @@ func (d *Dense[T]) Sqrt() Matrix {
 // Log returns a new matrix applying the natural logarithm function to each element.
 func (d *Dense[T]) Log() Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
-	outData := out.data
-	if len(outData) == 0 {
-		return out
-	}
-	inData := d.data
-	switch any(T(0)).(type) {
-	case float32:
-		matfuncs.Log32(any(inData).([]float32), any(outData).([]float32))
-	case float64:
-		matfuncs.Log64(any(inData).([]float64), any(outData).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	out := newDenseIn[T](d.arena, d.shape...)
+	backend.For[T]().Log(d.data, out.data)
 	return out
 }
 
 // Exp returns a new matrix applying the base-e exponential function to each element.
 func (d *Dense[T]) Exp() Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
-	outData := out.data
-	if len(outData) == 0 {
-		return out
-	}
-	inData := d.data
-	switch any(T(0)).(type) {
-	case float32:
-		matfuncs.Exp32(any(inData).([]float32), any(outData).([]float32))
-	case float64:
-		matfuncs.Exp64(any(inData).([]float64), any(outData).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	out := newDenseIn[T](d.arena, d.shape...)
+	backend.For[T]().Exp(d.data, out.data)
 	return out
 }
 
 // Sigmoid returns a new matrix applying the sigmoid function to each element.
 func (d *Dense[T]) Sigmoid() Matrix {
-	if d.Size() == 0 {
-		return d.Clone()
-	}
-
-	out := d.ProdScalar(-1).(*Dense[T])
-
-	switch any(T(0)).(type) {
-	case float32:
-		matfuncs.Exp32(any(out.data).([]float32), any(out.data).([]float32))
-	case float64:
-		matfuncs.Exp64(any(out.data).([]float64), any(out.data).([]float64))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
-
-	outData := out.data
-	for i, val := range outData {
-		outData[i] = 1 / (1 + val)
-	}
+	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
+	out := newDenseIn[T](d.arena, d.shape...)
+	backend.For[T]().Sigmoid(d.data, out.data)
 	return out
 }
 
@@ -1015,14 +783,7 @@ func (d *Dense[T]) Sum() Matrix {
 }
 
 func (d *Dense[T]) sum() T {
-	switch any(T(0)).(type) {
-	case float32:
-		return T(matfuncs.Sum32(any(d.data).([]float32)))
-	case float64:
-		return T(matfuncs.Sum64(any(d.data).([]float64)))
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	return backend.For[T]().Sum(d.data)
 }
 
 // Max returns the maximum value of the matrix as a scalar Matrix.
@@ -1034,13 +795,7 @@ func (d *Dense[T]) max() T {
 	if len(d.data) == 0 {
 		panic("mat: cannot find the maximum value from an empty matrix")
 	}
-	max := d.data[0]
-	for _, v := range d.data[1:] {
-		if v > max {
-			max = v
-		}
-	}
-	return max
+	return backend.For[T]().Max(d.data)
 }
 
 // Min returns the minimum value of the matrix as a scalar Matrix.
@@ -1077,6 +832,85 @@ func (d *Dense[T]) ArgMax() int {
 	return maxIndex
 }
 
+// ArgSort returns the indices that would sort the vector, in ascending
+// order, or descending if specified. The sorting is stable.
+func (d *Dense[T]) ArgSort(descending bool) []int {
+	if !IsVector(d) {
+		panic("mat: expected vector")
+	}
+	return argSort(d.data, descending)
+}
+
+// Sort returns a copy of the vector, with the values sorted in ascending
+// order, or descending if specified.
+func (d *Dense[T]) Sort(descending bool) Matrix {
+	if !IsVector(d) {
+		panic("mat: expected vector")
+	}
+	out := d.Clone().(*Dense[T])
+	if descending {
+		sort.Slice(out.data, func(i, j int) bool { return out.data[i] > out.data[j] })
+	} else {
+		sort.Slice(out.data, func(i, j int) bool { return out.data[i] < out.data[j] })
+	}
+	return out
+}
+
+// TopK returns the k largest values of the vector, in descending order,
+// and their indices. The values are returned as a vector with the same
+// orientation of the receiver. On equal values, the lowest index comes first.
+func (d *Dense[T]) TopK(k int) (Matrix, []int) {
+	if !IsVector(d) {
+		panic("mat: expected vector")
+	}
+	if k < 0 || k > len(d.data) {
+		panic(fmt.Sprintf("mat: invalid k %d for vector of size %d", k, len(d.data)))
+	}
+	indices := argSort(d.data, true)[:k]
+	out := newDenseIn[T](d.arena, k, 1)
+	if d.shape[0] == 1 {
+		out.shape[0], out.shape[1] = 1, k
+	}
+	for i, j := range indices {
+		out.data[i] = d.data[j]
+	}
+	return out, indices
+}
+
+// KMaxPooling returns, for each row of the matrix, its k largest values in
+// their original order, as a new rows×k matrix. It also returns the column
+// index of each selected value.
+func (d *Dense[T]) KMaxPooling(k int) (Matrix, [][]int) {
+	rows, cols := d.shape[0], d.shape[1]
+	if k < 0 || k > cols {
+		panic(fmt.Sprintf("mat: invalid k %d for matrix with %d columns", k, cols))
+	}
+	out := newDenseIn[T](d.arena, rows, k)
+	index := make([][]int, rows)
+	for r := range index {
+		row := d.data[r*cols : (r+1)*cols]
+		index[r] = argSort(row, true)[:k]
+		sort.Ints(index[r])
+		for i, c := range index[r] {
+			out.data[r*k+i] = row[c]
+		}
+	}
+	return out, index
+}
+
+func argSort[T float.DType](data []T, descending bool) []int {
+	indices := make([]int, len(data))
+	for i := range indices {
+		indices[i] = i
+	}
+	if descending {
+		sort.SliceStable(indices, func(i, j int) bool { return data[indices[i]] > data[indices[j]] })
+	} else {
+		sort.SliceStable(indices, func(i, j int) bool { return data[indices[i]] < data[indices[j]] })
+	}
+	return indices
+}
+
 // Softmax applies the softmax function to the vector, returning the
 // result as a new column vector.
 func (d *Dense[T]) Softmax() Matrix {
@@ -1095,16 +929,7 @@ func (d *Dense[T]) Softmax() Matrix {
 		out.TransposeInPlace()
 	}
 
-	switch any(T(0)).(type) {
-	case float32:
-		outData := any(out.data).([]float32)
-		matfuncs.Exp32(outData, outData)
-	case float64:
-		outData := any(out.data).([]float64)
-		matfuncs.Exp64(outData, outData)
-	default:
-		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
-	}
+	backend.For[T]().Exp(out.data, out.data)
 
 	sum := out.sum()
 	out.ProdScalarInPlace(float64(1 / sum))
@@ -1120,7 +945,7 @@ func (d *Dense[T]) CumSum() Matrix {
 	}
 
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](len(d.data)), len(d.data), 1)
+	out := newDenseIn[T](d.arena, len(d.data), 1)
 	if len(d.data) == 0 {
 		return out
 	}
@@ -1137,6 +962,47 @@ func (d *Dense[T]) CumSum() Matrix {
 	return out
 }
 
+// CumProd computes the cumulative product of the vector's elements,
+// returning the result as a new column vector.
+func (d *Dense[T]) CumProd() Matrix {
+	if !IsVector(d) {
+		panic("mat: expected vector")
+	}
+	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
+	out := newDenseIn[T](d.arena, len(d.data), 1)
+	p := T(1)
+	for i, v := range d.data {
+		p *= v
+		out.data[i] = p
+	}
+	return out
+}
+
+// LogCumSumExp computes the logarithm of the cumulative sum of the
+// exponentials of the vector's elements, returning the result as a new
+// column vector. The computation is numerically stable.
+func (d *Dense[T]) LogCumSumExp() Matrix {
+	if !IsVector(d) {
+		panic("mat: expected vector")
+	}
+	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
+	out := newDenseIn[T](d.arena, len(d.data), 1)
+	acc := Inf[T](-1)
+	for i, v := range d.data {
+		// log(exp(acc) + exp(v)), factoring out the maximum
+		switch {
+		case IsInf(acc, -1):
+			acc = v
+		case acc > v:
+			acc += T(math.Log1p(math.Exp(float64(v - acc))))
+		default:
+			acc = v + T(math.Log1p(math.Exp(float64(acc-v))))
+		}
+		out.data[i] = acc
+	}
+	return out
+}
+
 // Range creates a new vector initialized with data extracted from the
 // matrix raw data, from start (inclusive) to end (exclusive).
 func (d *Dense[T]) Range(start, end int) Matrix {
@@ -1218,7 +1084,7 @@ func (d *Dense[T]) PadRows(n int) Matrix {
 	cols := d.shape[1]
 	dRows := d.shape[0]
 	yRows := dRows + n
-	y := NewDense[T](WithShape(yRows, cols))
+	y := newDenseIn[T](d.arena, yRows, cols)
 
 	if cols == 0 || dRows == 0 {
 		return y
@@ -1240,7 +1106,7 @@ func (d *Dense[T]) PadColumns(n int) Matrix {
 	rows := d.shape[0]
 	dCols := d.shape[1]
 	yCols := dCols + n
-	y := NewDense[T](WithShape(rows, yCols))
+	y := newDenseIn[T](d.arena, rows, yCols)
 
 	if rows == 0 || dCols == 0 {
 		return y
@@ -1263,7 +1129,7 @@ func (d *Dense[T]) PadColumns(n int) Matrix {
 func (d *Dense[T]) AppendRows(vs ...Matrix) Matrix {
 	cols := d.shape[1]
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T]((d.shape[0]+len(vs))*cols), d.shape[0]+len(vs), cols)
+	out := newDenseIn[T](d.arenaWith(vs...), d.shape[0]+len(vs), cols)
 	dData := d.data
 	outData := out.data
 	copy(outData[:len(dData)], dData)
@@ -1311,7 +1177,7 @@ func (d *Dense[T]) Normalize2() Matrix {
 // Apply creates a new matrix executing the unary function fn.
 func (d *Dense[T]) Apply(fn func(r, c int, v float64) float64) Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
+	out := newDenseIn[T](d.arena, d.shape...)
 	if len(d.data) == 0 {
 		return out
 	}
@@ -1364,7 +1230,7 @@ func (d *Dense[T]) ApplyInPlace(fn func(r, c int, v float64) float64, a Matrix)
 // taking additional alpha.
 func (d *Dense[T]) ApplyWithAlpha(fn func(r, c int, v float64, alpha ...float64) float64, alpha ...float64) Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
+	out := newDenseIn[T](d.arena, d.shape...)
 	if len(d.data) == 0 {
 		return out
 	}
@@ -1434,7 +1300,7 @@ func (d *Dense[T]) DoVecNonZero(fn func(i int, v float64)) {
 // Clone returns a new matrix, copying all its values from the receiver.
 func (d *Dense[T]) Clone() Matrix {
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](d.Size()), d.shape...)
+	out := newDenseIn[T](d.arena, d.shape...)
 	copy(out.data, d.data)
 	return out
 }
@@ -1459,11 +1325,20 @@ func (d *Dense[T]) String() string {
 // Rows and columns MUST not be negative, and the length of data MUST be
 // equal to rows*cols, otherwise the method panics.
 func (d *Dense[T]) NewMatrix(opts ...OptionsFunc) Matrix {
-	return NewDense[T](opts...)
+	return NewDense[T](d.withArena(opts)...)
 }
 
 func (d *Dense[T]) NewScalar(v float64, opts ...OptionsFunc) Matrix {
-	return Scalar[T](T(v), opts...)
+	return Scalar[T](T(v), d.withArena(opts)...)
+}
+
+// withArena prepends to opts the option setting the arena of the receiver,
+// if any, so that the new matrices created from it keep the same arena.
+func (d *Dense[T]) withArena(opts []OptionsFunc) []OptionsFunc {
+	if d.arena == nil {
+		return opts
+	}
+	return append([]OptionsFunc{WithArena(d.arena)}, opts...)
 }
 
 // NewConcatV creates a new column vector, of the same type of the receiver,
@@ -0,0 +1,43 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nlpodyssey/spago/mat/backend"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+func TestDense_Backends(t *testing.T) {
+	t.Run("float32", testDenseBackends[float32])
+	t.Run("float64", testDenseBackends[float64])
+}
+
+func testDenseBackends[T float.DType](t *testing.T) {
+	a := NewDense[T](WithShape(2, 3), WithBacking([]T{1, -2, 3, -4, 5, -6}))
+	b := NewDense[T](WithShape(3, 2), WithBacking([]T{1, 2, 3, 4, 5, 6}))
+	v := NewDense[T](WithShape(3), WithBacking([]T{1, 0, -1}))
+	w := NewDense[T](WithShape(2), WithBacking([]T{1, 2}))
+
+	defer func() { require.NoError(t, backend.Use(backend.SIMDName)) }()
+	for _, name := range backend.Names() {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, backend.Use(name))
+			assert.Equal(t, []T{10, 12, -19, -24}, Data[T](a.Mul(b)))
+			assert.Equal(t, []T{-2, 2}, Data[T](a.Mul(v)))
+			assert.Equal(t, []T{-7, 8, -9}, Data[T](a.MulT(w)))
+			assert.Equal(t, []T{2, -4, 6, -8, 10, -12}, Data[T](a.Add(a)))
+			assert.Equal(t, []T{1, 4, 9, 16, 25, 36}, Data[T](a.Prod(a)))
+			assert.Equal(t, []T{2, -1, 4, -3, 6, -5}, Data[T](a.AddScalar(1)))
+			assert.Equal(t, -3.0, a.Sum().Item().F64())
+			assert.Equal(t, 5.0, a.Max().Item().F64())
+			assert.InDeltaSlice(t, []T{0.731058, 0.5, 0.268941}, Data[T](v.Sigmoid()), 1.0e-6)
+		})
+	}
+}
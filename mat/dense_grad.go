@@ -18,11 +18,17 @@ func (d *Dense[T]) Grad() Tensor {
 
 // AccGrad accumulates the gradients.
 // It accumulates the gradients even if the requiresGrad flag is false.
+//
+// The gradients are stored in a matrix drawn from the arena of the receiver,
+// if any, regardless of the arena of grad: the gradients of the matrices
+// created without an arena, such as parameters, are never part of one.
 func (d *Dense[T]) AccGrad(grad Tensor) {
 	d.gradMu.Lock()
 	defer d.gradMu.Unlock()
 	if d.grad == nil {
-		d.grad = grad.(Matrix).Clone().(*Dense[T])
+		shape := grad.Shape()
+		d.grad = newDenseIn[T](d.arena, shape[0], shape[1])
+		copy(d.grad.data, Data[T](grad))
 		return
 	}
 	d.grad.AddInPlace(grad.(Matrix))
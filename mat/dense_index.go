@@ -0,0 +1,149 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "fmt"
+
+// IndexSelect returns a new matrix made of the rows (axis 0) or the
+// columns (axis 1) of the receiver at the given indices, in order.
+// Indices may be repeated.
+func (d *Dense[T]) IndexSelect(axis int, indices ...int) Matrix {
+	rows, cols := d.shape[0], d.shape[1]
+	switch axis {
+	case 0:
+		out := newDenseIn[T](d.arena, len(indices), cols)
+		for k, i := range indices {
+			checkIndex(i, rows)
+			copy(out.data[k*cols:(k+1)*cols], d.data[i*cols:(i+1)*cols])
+		}
+		return out
+	case 1:
+		out := newDenseIn[T](d.arena, rows, len(indices))
+		for r := 0; r < rows; r++ {
+			row := d.data[r*cols : (r+1)*cols]
+			outRow := out.data[r*len(indices) : (r+1)*len(indices)]
+			for k, j := range indices {
+				checkIndex(j, cols)
+				outRow[k] = row[j]
+			}
+		}
+		return out
+	default:
+		panic(fmt.Sprintf("mat: invalid axis %d", axis))
+	}
+}
+
+// IndexAddInPlace adds the rows (axis 0) or the columns (axis 1) of src
+// to the rows or columns of the receiver at the given indices.
+// Values are accumulated on repeated indices.
+// It is the inverse operation of IndexSelect.
+func (d *Dense[T]) IndexAddInPlace(axis int, indices []int, src Matrix) Matrix {
+	rows, cols := d.shape[0], d.shape[1]
+	s := Data[T](src)
+	switch axis {
+	case 0:
+		if src.Shape()[0] != len(indices) || src.Shape()[1] != cols {
+			panic("mat: matrices have incompatible dimensions")
+		}
+		for k, i := range indices {
+			checkIndex(i, rows)
+			row := d.data[i*cols : (i+1)*cols]
+			for j, v := range s[k*cols : (k+1)*cols] {
+				row[j] += v
+			}
+		}
+	case 1:
+		if src.Shape()[0] != rows || src.Shape()[1] != len(indices) {
+			panic("mat: matrices have incompatible dimensions")
+		}
+		for r := 0; r < rows; r++ {
+			row := d.data[r*cols : (r+1)*cols]
+			srcRow := s[r*len(indices) : (r+1)*len(indices)]
+			for k, j := range indices {
+				checkIndex(j, cols)
+				row[j] += srcRow[k]
+			}
+		}
+	default:
+		panic(fmt.Sprintf("mat: invalid axis %d", axis))
+	}
+	return d
+}
+
+// Gather returns a new matrix with the same shape of index, collecting the
+// values of the receiver along the given axis:
+//
+//	out[i][j] = d[index[i][j]][j]  // axis 0
+//	out[i][j] = d[i][index[i][j]]  // axis 1
+func (d *Dense[T]) Gather(axis int, index [][]int) Matrix {
+	rows, cols := indexShape(index)
+	out := newDenseIn[T](d.arena, rows, cols)
+	for i, idx := range index {
+		for j, k := range idx {
+			r, c := d.gatherPosition(axis, i, j, k)
+			out.data[i*cols+j] = d.data[r*d.shape[1]+c]
+		}
+	}
+	return out
+}
+
+// ScatterAddInPlace adds the values of src to the receiver, at the positions
+// given by index along the given axis:
+//
+//	d[index[i][j]][j] += src[i][j]  // axis 0
+//	d[i][index[i][j]] += src[i][j]  // axis 1
+//
+// Values are accumulated on repeated indices.
+// It is the inverse operation of Gather.
+func (d *Dense[T]) ScatterAddInPlace(axis int, index [][]int, src Matrix) Matrix {
+	rows, cols := indexShape(index)
+	if src.Shape()[0] != rows || src.Shape()[1] != cols {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	s := Data[T](src)
+	for i, idx := range index {
+		for j, k := range idx {
+			r, c := d.gatherPosition(axis, i, j, k)
+			d.data[r*d.shape[1]+c] += s[i*cols+j]
+		}
+	}
+	return d
+}
+
+// gatherPosition returns the position in the receiver of the element
+// addressed by the index k, found at position (i, j) of a gather index.
+func (d *Dense[T]) gatherPosition(axis, i, j, k int) (r, c int) {
+	switch axis {
+	case 0:
+		r, c = k, j
+	case 1:
+		r, c = i, k
+	default:
+		panic(fmt.Sprintf("mat: invalid axis %d", axis))
+	}
+	checkIndex(r, d.shape[0])
+	checkIndex(c, d.shape[1])
+	return r, c
+}
+
+// indexShape returns the dimensions of a rectangular index.
+func indexShape(index [][]int) (rows, cols int) {
+	if len(index) == 0 {
+		return 0, 0
+	}
+	cols = len(index[0])
+	for _, idx := range index[1:] {
+		if len(idx) != cols {
+			panic("mat: index rows must have the same length")
+		}
+	}
+	return len(index), cols
+}
+
+func checkIndex(i, size int) {
+	if i < 0 || i >= size {
+		panic("mat: index out of range")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDense_IndexSelect(t *testing.T) {
+	t.Run("float32", testDenseIndexSelect[float32])
+	t.Run("float64", testDenseIndexSelect[float64])
+}
+
+func testDenseIndexSelect[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(2, 3), WithBacking([]T{
+		1, 2, 3,
+		4, 5, 6,
+	}))
+
+	assert.Equal(t, []T{4, 5, 6, 4, 5, 6, 1, 2, 3}, Data[T](d.IndexSelect(0, 1, 1, 0)))
+	assert.Equal(t, []T{3, 1, 6, 4}, Data[T](d.IndexSelect(1, 2, 0)))
+	assert.Equal(t, []int{0, 3}, d.IndexSelect(0).Shape())
+
+	require.Panics(t, func() { d.IndexSelect(0, 2) })
+	require.Panics(t, func() { d.IndexSelect(1, -1) })
+	require.Panics(t, func() { d.IndexSelect(2, 0) })
+}
+
+func TestDense_IndexAddInPlace(t *testing.T) {
+	t.Run("float32", testDenseIndexAddInPlace[float32])
+	t.Run("float64", testDenseIndexAddInPlace[float64])
+}
+
+func testDenseIndexAddInPlace[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(2, 2))
+	d.IndexAddInPlace(0, []int{1, 1}, NewDense[T](WithShape(2, 2), WithBacking([]T{1, 2, 3, 4})))
+	assert.Equal(t, []T{0, 0, 4, 6}, d.data)
+
+	d.IndexAddInPlace(1, []int{0}, NewDense[T](WithShape(2, 1), WithBacking([]T{1, 2})))
+	assert.Equal(t, []T{1, 0, 6, 6}, d.data)
+
+	require.Panics(t, func() {
+		d.IndexAddInPlace(0, []int{0}, NewDense[T](WithShape(2, 2)))
+	})
+}
+
+func TestDense_Gather(t *testing.T) {
+	t.Run("float32", testDenseGather[float32])
+	t.Run("float64", testDenseGather[float64])
+}
+
+func testDenseGather[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(2, 3), WithBacking([]T{
+		1, 2, 3,
+		4, 5, 6,
+	}))
+
+	y := d.Gather(0, [][]int{{1, 0, 1}})
+	assert.Equal(t, []int{1, 3}, y.Shape())
+	assert.Equal(t, []T{4, 2, 6}, Data[T](y))
+
+	y = d.Gather(1, [][]int{{2, 2}, {0, 1}})
+	assert.Equal(t, []int{2, 2}, y.Shape())
+	assert.Equal(t, []T{3, 3, 4, 5}, Data[T](y))
+
+	require.Panics(t, func() { d.Gather(0, [][]int{{2}}) })
+	require.Panics(t, func() { d.Gather(1, [][]int{{0}, {0}, {0}}) })
+	require.Panics(t, func() { d.Gather(1, [][]int{{0}, {0, 1}}) })
+}
+
+func TestDense_ScatterAddInPlace(t *testing.T) {
+	t.Run("float32", testDenseScatterAddInPlace[float32])
+	t.Run("float64", testDenseScatterAddInPlace[float64])
+}
+
+func testDenseScatterAddInPlace[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(2, 3))
+	d.ScatterAddInPlace(0, [][]int{{1, 0, 1}, {1, 1, 1}}, NewDense[T](WithShape(2, 3), WithBacking([]T{
+		1, 2, 3,
+		4, 5, 6,
+	})))
+	assert.Equal(t, []T{
+		0, 2, 0,
+		5, 5, 9,
+	}, d.data)
+
+	require.Panics(t, func() {
+		d.ScatterAddInPlace(0, [][]int{{0}}, NewDense[T](WithShape(2, 1)))
+	})
+}
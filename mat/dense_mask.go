@@ -0,0 +1,101 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+// Greater returns a new mask matrix, set to 1 where the receiver's values
+// are greater than the other's, and to 0 elsewhere.
+// The other matrix must have the same dimensions of the receiver, or be a
+// scalar compared with each value.
+func (d *Dense[T]) Greater(other Matrix) Matrix {
+	return d.compare(other, func(a, b T) bool { return a > b })
+}
+
+// GreaterEqual returns a new mask matrix, set to 1 where the receiver's
+// values are greater than or equal to the other's, and to 0 elsewhere.
+func (d *Dense[T]) GreaterEqual(other Matrix) Matrix {
+	return d.compare(other, func(a, b T) bool { return a >= b })
+}
+
+// Less returns a new mask matrix, set to 1 where the receiver's values
+// are less than the other's, and to 0 elsewhere.
+func (d *Dense[T]) Less(other Matrix) Matrix {
+	return d.compare(other, func(a, b T) bool { return a < b })
+}
+
+// LessEqual returns a new mask matrix, set to 1 where the receiver's
+// values are less than or equal to the other's, and to 0 elsewhere.
+func (d *Dense[T]) LessEqual(other Matrix) Matrix {
+	return d.compare(other, func(a, b T) bool { return a <= b })
+}
+
+// Equal returns a new mask matrix, set to 1 where the receiver's values
+// are equal to the other's, and to 0 elsewhere.
+func (d *Dense[T]) Equal(other Matrix) Matrix {
+	return d.compare(other, func(a, b T) bool { return a == b })
+}
+
+// NotEqual returns a new mask matrix, set to 1 where the receiver's values
+// differ from the other's, and to 0 elsewhere.
+func (d *Dense[T]) NotEqual(other Matrix) Matrix {
+	return d.compare(other, func(a, b T) bool { return a != b })
+}
+
+// compare returns a new mask matrix, set to 1 where cmp is true, comparing
+// each value of the receiver with the corresponding value of other, or with
+// the only value of other, if it is a scalar.
+func (d *Dense[T]) compare(other Matrix, cmp func(a, b T) bool) Matrix {
+	otherData := Data[T](other)
+	stride := 1
+	if IsScalar(other) && !IsScalar(d) {
+		stride = 0
+	} else if !SameDims(d, other) {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	out := newDenseIn[T](d.arenaWith(other), d.shape...)
+	for i, v := range d.data {
+		if cmp(v, otherData[i*stride]) {
+			out.data[i] = 1
+		}
+	}
+	return out
+}
+
+// Where returns a new matrix taking the values of a where the receiver is
+// non-zero, and the values of b elsewhere.
+// All the matrices must have the same dimensions.
+func (d *Dense[T]) Where(a, b Matrix) Matrix {
+	if !SameDims(d, a) || !SameDims(d, b) {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	aData, bData := Data[T](a), Data[T](b)
+	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
+	out := newDenseIn[T](d.arenaWith(a, b), d.shape...)
+	for i, c := range d.data {
+		if c != 0 {
+			out.data[i] = aData[i]
+		} else {
+			out.data[i] = bData[i]
+		}
+	}
+	return out
+}
+
+// MaskedFill returns a copy of the matrix, with the given value in place of
+// the elements where the mask is non-zero.
+// The mask must have the same dimensions of the receiver.
+func (d *Dense[T]) MaskedFill(mask Matrix, value float64) Matrix {
+	if !SameDims(d, mask) {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	maskData := Data[T](mask)
+	out := d.Clone().(*Dense[T])
+	v := T(value)
+	for i, m := range maskData {
+		if m != 0 {
+			out.data[i] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDense_Comparisons(t *testing.T) {
+	t.Run("float32", testDenseComparisons[float32])
+	t.Run("float64", testDenseComparisons[float64])
+}
+
+func testDenseComparisons[T float.DType](t *testing.T) {
+	a := NewDense[T](WithShape(2, 2), WithBacking([]T{1, 2, 3, 4}))
+	b := NewDense[T](WithShape(2, 2), WithBacking([]T{4, 2, 1, 5}))
+	s := Scalar[T](2)
+
+	testCases := []struct {
+		name     string
+		y        Matrix
+		expected []T
+	}{
+		{"Greater", a.Greater(b), []T{0, 0, 1, 0}},
+		{"GreaterEqual", a.GreaterEqual(b), []T{0, 1, 1, 0}},
+		{"Less", a.Less(b), []T{1, 0, 0, 1}},
+		{"LessEqual", a.LessEqual(b), []T{1, 1, 0, 1}},
+		{"Equal", a.Equal(b), []T{0, 1, 0, 0}},
+		{"NotEqual", a.NotEqual(b), []T{1, 0, 1, 1}},
+		{"Greater scalar", a.Greater(s), []T{0, 0, 1, 1}},
+		{"Equal scalar", a.Equal(s), []T{0, 1, 0, 0}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, []int{2, 2}, tc.y.Shape())
+			assert.Equal(t, tc.expected, Data[T](tc.y))
+		})
+	}
+
+	require.Panics(t, func() { a.Greater(NewDense[T](WithShape(2, 3))) })
+}
+
+func TestDense_Where(t *testing.T) {
+	t.Run("float32", testDenseWhere[float32])
+	t.Run("float64", testDenseWhere[float64])
+}
+
+func testDenseWhere[T float.DType](t *testing.T) {
+	cond := NewDense[T](WithShape(1, 3), WithBacking([]T{1, 0, 1}))
+	a := NewDense[T](WithShape(1, 3), WithBacking([]T{1, 2, 3}))
+	b := NewDense[T](WithShape(1, 3), WithBacking([]T{T(math.Inf(-1)), 5, 6}))
+
+	assert.Equal(t, []T{1, 5, 3}, Data[T](cond.Where(a, b)))
+	require.Panics(t, func() { cond.Where(a, NewDense[T](WithShape(3, 1))) })
+}
+
+func TestDense_MaskedFill(t *testing.T) {
+	t.Run("float32", testDenseMaskedFill[float32])
+	t.Run("float64", testDenseMaskedFill[float64])
+}
+
+func testDenseMaskedFill[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(1, 3), WithBacking([]T{1, 2, 3}))
+	mask := NewDense[T](WithShape(1, 3), WithBacking([]T{0, 1, 1}))
+
+	y := d.MaskedFill(mask, math.Inf(-1))
+	assert.Equal(t, []T{1, T(math.Inf(-1)), T(math.Inf(-1))}, Data[T](y))
+	assert.Equal(t, []T{1, 2, 3}, d.data)
+	require.Panics(t, func() { d.MaskedFill(Scalar[T](1), 0) })
+}
@@ -15,6 +15,7 @@ type Options struct {
 	RequiresGrad bool // default: false
 	Shape        []int
 	Slice        float.Slice
+	Arena        *Arena
 }
 
 type OptionsFunc func(opt *Options)
@@ -37,6 +38,16 @@ func WithBacking[T float.DType](data []T) OptionsFunc {
 	}
 }
 
+// WithArena sets the arena the matrices resulting from the operations on the
+// new matrix are drawn from (see Arena). The results keep the same arena, so
+// that it extends to the whole computation started from the new matrix.
+// The buffer of the new matrix itself is not drawn from the arena.
+func WithArena(a *Arena) OptionsFunc {
+	return func(opts *Options) {
+		opts.Arena = a
+	}
+}
+
 func NewDense[T float.DType](opts ...OptionsFunc) *Dense[T] {
 	r, err := newDense[T](opts...)
 	if err != nil {
@@ -87,6 +98,7 @@ func newDenseFromSlice[T float.DType](args *Options) (*Dense[T], error) {
 		shape:        shape,
 		data:         float.SliceValueOf[T](args.Slice),
 		requiresGrad: args.RequiresGrad,
+		arena:        args.Arena,
 	}, nil
 }
 
@@ -101,6 +113,7 @@ func newDenseFromShape[T float.DType](args *Options) (*Dense[T], error) {
 		shape:        shape,
 		data:         make([]T, size),
 		requiresGrad: args.RequiresGrad,
+		arena:        args.Arena,
 	}, nil
 }
 
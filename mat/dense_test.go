@@ -6,6 +6,7 @@ package mat
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/nlpodyssey/spago/mat/float"
@@ -3795,3 +3796,105 @@ func assertDenseDims[T float.DType](t *testing.T, expectedRows, expectedCols int
 	assert.Equal(t, expectedSize, d.Size())
 	assert.Len(t, d.Data(), expectedSize)
 }
+
+func TestDense_ArgSort(t *testing.T) {
+	t.Run("float32", testDenseArgSort[float32])
+	t.Run("float64", testDenseArgSort[float64])
+}
+
+func testDenseArgSort[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(5), WithBacking([]T{0.3, -0.1, 0.5, 0.3, 0.0}))
+	assert.Equal(t, []int{1, 4, 0, 3, 2}, d.ArgSort(false))
+	assert.Equal(t, []int{2, 0, 3, 4, 1}, d.ArgSort(true))
+
+	require.Panics(t, func() {
+		NewDense[T](WithShape(2, 2)).ArgSort(false)
+	})
+}
+
+func TestDense_Sort(t *testing.T) {
+	t.Run("float32", testDenseSort[float32])
+	t.Run("float64", testDenseSort[float64])
+}
+
+func testDenseSort[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(1, 4), WithBacking([]T{0.3, -0.1, 0.5, 0.0}))
+
+	y := d.Sort(false)
+	assert.Equal(t, []int{1, 4}, y.Shape())
+	assert.Equal(t, []T{-0.1, 0.0, 0.3, 0.5}, Data[T](y))
+	assert.Equal(t, []T{0.5, 0.3, 0.0, -0.1}, Data[T](d.Sort(true)))
+	assert.Equal(t, []T{0.3, -0.1, 0.5, 0.0}, d.data)
+}
+
+func TestDense_TopK(t *testing.T) {
+	t.Run("float32", testDenseTopK[float32])
+	t.Run("float64", testDenseTopK[float64])
+}
+
+func testDenseTopK[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(5), WithBacking([]T{0.3, -0.1, 0.5, 0.3, 0.0}))
+
+	y, indices := d.TopK(3)
+	assert.Equal(t, []int{3, 1}, y.Shape())
+	assert.Equal(t, []T{0.5, 0.3, 0.3}, Data[T](y))
+	assert.Equal(t, []int{2, 0, 3}, indices)
+
+	y, indices = d.T().TopK(0)
+	assert.Equal(t, []int{1, 0}, y.Shape())
+	assert.Empty(t, indices)
+
+	require.Panics(t, func() { d.TopK(6) })
+	require.Panics(t, func() { d.TopK(-1) })
+}
+
+func TestDense_KMaxPooling(t *testing.T) {
+	t.Run("float32", testDenseKMaxPooling[float32])
+	t.Run("float64", testDenseKMaxPooling[float64])
+}
+
+func testDenseKMaxPooling[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(2, 5), WithBacking([]T{
+		0.3, -0.1, 0.5, 0.4, 0.0,
+		0.1, 0.9, 0.2, 0.8, 0.7,
+	}))
+
+	y, index := d.KMaxPooling(3)
+	assert.Equal(t, []int{2, 3}, y.Shape())
+	assert.Equal(t, []T{
+		0.3, 0.5, 0.4,
+		0.9, 0.8, 0.7,
+	}, Data[T](y))
+	assert.Equal(t, [][]int{{0, 2, 3}, {1, 3, 4}}, index)
+
+	require.Panics(t, func() { d.KMaxPooling(6) })
+}
+
+func TestDense_CumProd(t *testing.T) {
+	t.Run("float32", testDenseCumProd[float32])
+	t.Run("float64", testDenseCumProd[float64])
+}
+
+func testDenseCumProd[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(1, 4), WithBacking([]T{1, 2, -3, 0.5}))
+	y := d.CumProd()
+	assert.Equal(t, []int{4, 1}, y.Shape())
+	assert.InDeltaSlice(t, []T{1, 2, -6, -3}, Data[T](y), 1.0e-6)
+
+	require.Panics(t, func() {
+		NewDense[T](WithShape(2, 2)).CumProd()
+	})
+}
+
+func TestDense_LogCumSumExp(t *testing.T) {
+	t.Run("float32", testDenseLogCumSumExp[float32])
+	t.Run("float64", testDenseLogCumSumExp[float64])
+}
+
+func testDenseLogCumSumExp[T float.DType](t *testing.T) {
+	d := NewDense[T](WithShape(4), WithBacking([]T{T(math.Inf(-1)), 0, 1000, 1000}))
+	y := d.LogCumSumExp()
+	assert.Equal(t, []int{4, 1}, y.Shape())
+	assert.Equal(t, T(math.Inf(-1)), Data[T](y)[0])
+	assert.InDeltaSlice(t, []T{0, 1000, T(1000 + math.Ln2)}, Data[T](y)[1:], 1.0e-4)
+}
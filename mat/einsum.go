@@ -0,0 +1,313 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// EinsumSpec is the parsed representation of an Einstein summation
+// specification, such as "ij,jk->ik".
+type EinsumSpec struct {
+	// Inputs holds the labels of each operand.
+	Inputs []string
+	// Output holds the labels of the result.
+	Output string
+}
+
+// ParseEinsumSpec parses an Einstein summation specification.
+//
+// Labels are ASCII letters. Since matrices are two-dimensional, each operand
+// and the output can have at most two labels: no labels stand for a scalar,
+// one label for a vector, two labels for a matrix. A batch label shared by
+// two-dimensional operands (e.g. "bi,bi->b") is supported, while batched
+// matrix operands with three labels (e.g. "bij,bjk->bik") are rejected.
+//
+// In the explicit form ("ij,jk->ik") the output labels follow the arrow; the
+// output may repeat a label to place values on a diagonal, or introduce a
+// label missing from the inputs to broadcast values, provided that its size
+// is known. In the implicit form ("ij,jk"), the output is made of the labels
+// appearing exactly once, in alphabetical order.
+func ParseEinsumSpec(spec string) (EinsumSpec, error) {
+	spec = strings.ReplaceAll(spec, " ", "")
+	inputs, output, explicit := strings.Cut(spec, "->")
+
+	var s EinsumSpec
+	s.Inputs = strings.Split(inputs, ",")
+	for _, labels := range append(append([]string{}, s.Inputs...), output) {
+		if len(labels) > 2 {
+			return EinsumSpec{}, fmt.Errorf("einsum: too many labels %q: matrices have at most 2 dimensions, "+
+				"so batched specifications such as \"bij,bjk->bik\" must be applied to each item "+
+				"of the batch in their 2-dimensional form (e.g. \"ij,jk->ik\")", labels)
+		}
+		for _, l := range labels {
+			if !(l >= 'a' && l <= 'z' || l >= 'A' && l <= 'Z') {
+				return EinsumSpec{}, fmt.Errorf("einsum: invalid label %q", l)
+			}
+		}
+	}
+	if explicit {
+		s.Output = output
+		return s, nil
+	}
+
+	counts := make(map[rune]int)
+	for _, labels := range s.Inputs {
+		for _, l := range labels {
+			counts[l]++
+		}
+	}
+	var out []rune
+	for l, n := range counts {
+		if n == 1 {
+			out = append(out, l)
+		}
+	}
+	if len(out) > 2 {
+		return EinsumSpec{}, fmt.Errorf("einsum: implicit output %q has more than 2 labels", string(out))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	s.Output = string(out)
+	return s, nil
+}
+
+// String returns the explicit form of the specification.
+func (s EinsumSpec) String() string {
+	return strings.Join(s.Inputs, ",") + "->" + s.Output
+}
+
+// Einsum evaluates the Einstein summation convention on the operands,
+// according to the given specification (see ParseEinsumSpec).
+//
+// For example, "ij,jk->ik" is the matrix multiplication, "ij->ji" the
+// transposition, "ii->i" the diagonal extraction, "ii->" the trace,
+// "bi,bi->b" the batched dot product, and "i,j->ij" the outer product.
+//
+// A one-label operand can be either a row or a column vector; a one-label
+// output is a column vector. It panics if the specification is invalid,
+// or if it does not match the operands.
+func Einsum(spec string, operands ...Matrix) Matrix {
+	s, err := ParseEinsumSpec(spec)
+	if err != nil {
+		panic("mat: " + err.Error())
+	}
+	return s.Eval(nil, operands...)
+}
+
+// Eval evaluates the specification on the operands. The optional sizes
+// provide the dimension of the output labels not found in the inputs.
+func (s EinsumSpec) Eval(sizes map[byte]int, operands ...Matrix) Matrix {
+	if len(operands) == 0 {
+		panic("mat: einsum requires at least one operand")
+	}
+	switch operands[0].(type) {
+	case *Dense[float32]:
+		return einsum[float32](s, sizes, operands)
+	case *Dense[float64]:
+		return einsum[float64](s, sizes, operands)
+	default:
+		panic(fmt.Sprintf("mat: unexpected matrix type %T", operands[0]))
+	}
+}
+
+// LabelSizes returns the size of each label of the operands.
+// It panics if the operands do not match the specification.
+func (s EinsumSpec) LabelSizes(operands ...Matrix) map[byte]int {
+	if len(operands) != len(s.Inputs) {
+		panic(fmt.Sprintf("mat: einsum %q expects %d operands, got %d", s, len(s.Inputs), len(operands)))
+	}
+	sizes := make(map[byte]int)
+	for i, labels := range s.Inputs {
+		dims := operandDims(operands[i], labels)
+		for j := 0; j < len(labels); j++ {
+			if size, ok := sizes[labels[j]]; ok && size != dims[j] {
+				panic(fmt.Sprintf("mat: einsum label %q has inconsistent sizes %d and %d", labels[j], size, dims[j]))
+			}
+			sizes[labels[j]] = dims[j]
+		}
+	}
+	return sizes
+}
+
+// operandDims returns the dimensions of a matrix corresponding to its labels.
+func operandDims(m Matrix, labels string) []int {
+	switch len(labels) {
+	case 0:
+		if m.Size() != 1 {
+			panic(fmt.Sprintf("mat: einsum expected a scalar, got shape %v", m.Shape()))
+		}
+		return nil
+	case 1:
+		if !IsVector(m) {
+			panic(fmt.Sprintf("mat: einsum expected a vector, got shape %v", m.Shape()))
+		}
+		return []int{m.Size()}
+	default:
+		return m.Shape()
+	}
+}
+
+func einsum[T float.DType](s EinsumSpec, sizes map[byte]int, operands []Matrix) *Dense[T] {
+	known := s.LabelSizes(operands...)
+	for l, size := range sizes {
+		known[l] = size
+	}
+
+	out := NewDense[T](WithShape(s.OutputShape(known)...))
+
+	if y, ok := einsumMul(s, operands); ok {
+		copy(out.data, Data[T](y))
+		return out
+	}
+	einsumLoop(s, known, operands, out)
+	return out
+}
+
+// einsumLoop accumulates into out the products of the operands' values
+// for every combination of the labels.
+func einsumLoop[T float.DType](s EinsumSpec, sizes map[byte]int, operands []Matrix, out *Dense[T]) {
+	// Collect all the labels; each one is an axis of the iteration space.
+	var labels []byte
+	position := make(map[byte]int)
+	for _, l := range []byte(strings.Join(s.Inputs, "") + s.Output) {
+		if _, ok := position[l]; !ok {
+			position[l] = len(labels)
+			labels = append(labels, l)
+		}
+	}
+	dims := make([]int, len(labels))
+	for i, l := range labels {
+		dims[i] = einsumSize(sizes, l)
+		if dims[i] == 0 {
+			return
+		}
+	}
+
+	data := make([][]T, len(operands))
+	inStrides := make([][]int, len(operands))
+	for i, m := range operands {
+		data[i] = Data[T](m)
+		inStrides[i] = einsumStrides(s.Inputs[i], m.Shape()[1], position)
+	}
+	outStrides := einsumStrides(s.Output, out.shape[1], position)
+
+	index := make([]int, len(dims))
+	offsets := make([]int, len(operands))
+	outOffset := 0
+	for {
+		v := T(1)
+		for i := range data {
+			v *= data[i][offsets[i]]
+		}
+		out.data[outOffset] += v
+
+		// Advance the odometer, updating the offsets incrementally.
+		k := len(dims) - 1
+		for ; k >= 0; k-- {
+			index[k]++
+			for i := range offsets {
+				offsets[i] += inStrides[i][k]
+			}
+			outOffset += outStrides[k]
+			if index[k] < dims[k] {
+				break
+			}
+			for i := range offsets {
+				offsets[i] -= inStrides[i][k] * dims[k]
+			}
+			outOffset -= outStrides[k] * dims[k]
+			index[k] = 0
+		}
+		if k < 0 {
+			return
+		}
+	}
+}
+
+// einsumStrides returns the stride of each axis of the iteration space in
+// the flat row-major data of a matrix with the given labels. Repeated labels
+// sum up their strides, moving along the diagonal.
+func einsumStrides(labels string, cols int, position map[byte]int) []int {
+	strides := make([]int, len(position))
+	switch len(labels) {
+	case 1:
+		strides[position[labels[0]]]++
+	case 2:
+		strides[position[labels[0]]] += cols
+		strides[position[labels[1]]]++
+	}
+	return strides
+}
+
+// OutputShape returns the shape of the result, given the size of each label.
+func (s EinsumSpec) OutputShape(sizes map[byte]int) []int {
+	shape := []int{1, 1}
+	for i := 0; i < len(s.Output); i++ {
+		shape[i] = einsumSize(sizes, s.Output[i])
+	}
+	return shape
+}
+
+func einsumSize(sizes map[byte]int, label byte) int {
+	size, ok := sizes[label]
+	if !ok {
+		panic(fmt.Sprintf("mat: einsum output label %q not found in the inputs", label))
+	}
+	return size
+}
+
+// einsumMul evaluates the specifications equivalent to a product of two
+// matrices (such as "ij,jk->ik" or "ij,kj->ki") using the matrix
+// multiplication.
+func einsumMul(s EinsumSpec, operands []Matrix) (Matrix, bool) {
+	if len(operands) != 2 || len(s.Inputs[0]) != 2 || len(s.Inputs[1]) != 2 || len(s.Output) != 2 {
+		return nil, false
+	}
+	a, b := s.Inputs[0], s.Inputs[1]
+	if a[0] == a[1] || b[0] == b[1] || s.Output[0] == s.Output[1] {
+		return nil, false
+	}
+	// Find the contracted label, shared by both operands and not in the output.
+	ca, cb := -1, -1
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			if a[i] == b[j] {
+				if ca != -1 {
+					return nil, false
+				}
+				ca, cb = i, j
+			}
+		}
+	}
+	if ca == -1 || strings.IndexByte(s.Output, a[ca]) != -1 {
+		return nil, false
+	}
+	fa, fb := a[1-ca], b[1-cb]
+	var transposeOut bool
+	switch s.Output {
+	case string([]byte{fa, fb}):
+	case string([]byte{fb, fa}):
+		transposeOut = true
+	default:
+		return nil, false
+	}
+
+	x, y := operands[0], operands[1]
+	if ca == 0 {
+		x = x.T()
+	}
+	if cb == 1 {
+		y = y.T()
+	}
+	out := x.Mul(y)
+	if transposeOut {
+		out = out.T()
+	}
+	return out, true
+}
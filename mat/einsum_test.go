@@ -0,0 +1,116 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEinsumSpec(t *testing.T) {
+	s, err := ParseEinsumSpec("ij, jk -> ik")
+	require.NoError(t, err)
+	assert.Equal(t, EinsumSpec{Inputs: []string{"ij", "jk"}, Output: "ik"}, s)
+	assert.Equal(t, "ij,jk->ik", s.String())
+
+	s, err = ParseEinsumSpec("kj,ji")
+	require.NoError(t, err)
+	assert.Equal(t, EinsumSpec{Inputs: []string{"kj", "ji"}, Output: "ik"}, s)
+
+	s, err = ParseEinsumSpec("ii")
+	require.NoError(t, err)
+	assert.Equal(t, "", s.Output)
+
+	for _, spec := range []string{"bij,bjk->bik", "ij->ijk", "i1->i", "ij,kl"} {
+		_, err = ParseEinsumSpec(spec)
+		assert.Error(t, err, spec)
+	}
+
+	_, err = ParseEinsumSpec("bij,bjk->bik")
+	assert.EqualError(t, err, `einsum: too many labels "bij": matrices have at most 2 dimensions, `+
+		`so batched specifications such as "bij,bjk->bik" must be applied to each item `+
+		`of the batch in their 2-dimensional form (e.g. "ij,jk->ik")`)
+	assert.PanicsWithValue(t, `mat: einsum: too many labels "bij": matrices have at most 2 dimensions, `+
+		`so batched specifications such as "bij,bjk->bik" must be applied to each item `+
+		`of the batch in their 2-dimensional form (e.g. "ij,jk->ik")`, func() {
+		Einsum("bij,bjk->bik", NewDense[float32](WithShape(2, 2)), NewDense[float32](WithShape(2, 2)))
+	})
+}
+
+func TestEinsum(t *testing.T) {
+	t.Run("float32", testEinsum[float32])
+	t.Run("float64", testEinsum[float64])
+}
+
+func testEinsum[T float.DType](t *testing.T) {
+	a := NewDense[T](WithShape(2, 3), WithBacking([]T{
+		1, 2, 3,
+		4, 5, 6,
+	}))
+	b := NewDense[T](WithShape(3, 2), WithBacking([]T{
+		1, 2,
+		3, 4,
+		5, 6,
+	}))
+	sq := NewDense[T](WithShape(2, 2), WithBacking([]T{
+		1, 2,
+		3, 4,
+	}))
+	v := NewDense[T](WithShape(3), WithBacking([]T{1, 0, -1}))
+	w := NewDense[T](WithShape(1, 2), WithBacking([]T{2, 3}))
+
+	testCases := []struct {
+		spec     string
+		operands []Matrix
+		shape    []int
+		expected []T
+	}{
+		{"ij,jk->ik", []Matrix{a, b}, []int{2, 2}, []T{22, 28, 49, 64}},
+		{"ij,jk->ki", []Matrix{a, b}, []int{2, 2}, []T{22, 49, 28, 64}},
+		{"ji,kj->ik", []Matrix{b, a}, []int{2, 2}, []T{22, 49, 28, 64}},
+		{"ij,jk", []Matrix{a, b}, []int{2, 2}, []T{22, 28, 49, 64}},
+		{"ij->ji", []Matrix{a}, []int{3, 2}, []T{1, 4, 2, 5, 3, 6}},
+		{"ij->", []Matrix{a}, []int{1, 1}, []T{21}},
+		{"ij->j", []Matrix{a}, []int{3, 1}, []T{5, 7, 9}},
+		{"ii->i", []Matrix{sq}, []int{2, 1}, []T{1, 4}},
+		{"ii->", []Matrix{sq}, []int{1, 1}, []T{5}},
+		{"ij,ij->i", []Matrix{a, a}, []int{2, 1}, []T{14, 77}},
+		{"ij,ij->ij", []Matrix{sq, sq}, []int{2, 2}, []T{1, 4, 9, 16}},
+		{"ij,j->i", []Matrix{a, v}, []int{2, 1}, []T{-2, -2}},
+		{"i,j->ij", []Matrix{v, w}, []int{3, 2}, []T{2, 3, 0, 0, -2, -3}},
+		{"i,i->", []Matrix{w, w}, []int{1, 1}, []T{13}},
+		{"ij,jk,kl->il", []Matrix{sq, sq, sq}, []int{2, 2}, []T{37, 54, 81, 118}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			y := Einsum(tc.spec, tc.operands...)
+			assert.Equal(t, tc.shape, y.Shape())
+			assert.InDeltaSlice(t, tc.expected, Data[T](y), 1.0e-6)
+		})
+	}
+
+	t.Run("broadcast and diagonal output", func(t *testing.T) {
+		s, err := ParseEinsumSpec("i->ij")
+		require.NoError(t, err)
+		y := s.Eval(map[byte]int{'j': 2}, v)
+		assert.Equal(t, []T{1, 1, 0, 0, -1, -1}, Data[T](y))
+
+		s, err = ParseEinsumSpec("i->ii")
+		require.NoError(t, err)
+		y = s.Eval(nil, w)
+		assert.Equal(t, []T{2, 0, 0, 3}, Data[T](y))
+	})
+
+	t.Run("invalid operands", func(t *testing.T) {
+		require.Panics(t, func() { Einsum("ij,jk->ik", a, a) })
+		require.Panics(t, func() { Einsum("ij,jk->ik", a) })
+		require.Panics(t, func() { Einsum("i->i", a) })
+		require.Panics(t, func() { Einsum("ij->ik", a) })
+	})
+}
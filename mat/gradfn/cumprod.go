@@ -0,0 +1,69 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// CumProd is a Function computing the cumulative product of the elements
+// of a vector, as a column vector.
+type CumProd[O mat.Tensor] struct {
+	x O
+}
+
+// NewCumProd returns a new CumProd Function.
+func NewCumProd[O mat.Tensor](x O) *CumProd[O] {
+	return &CumProd[O]{x: x}
+}
+
+// Operands returns the list of operands.
+func (r *CumProd[O]) Operands() []mat.Tensor {
+	return []mat.Tensor{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *CumProd[O]) Forward() (mat.Tensor, error) {
+	return r.x.Value().(mat.Matrix).CumProd(), nil
+}
+
+// Backward computes the backward pass.
+//
+// The gradient of x[j] is the sum, over i >= j, of gy[i] multiplied by
+// the product of all x[k] with k <= i and k != j. It is computed in linear
+// time without any division, so that it is well-defined even when some
+// elements are zero.
+func (r *CumProd[O]) Backward(gy mat.Tensor) error {
+	if !(mat.IsVector(gy) && r.x.Value().Size() == gy.Size()) {
+		return fmt.Errorf("fn: vectors with not compatible size")
+	}
+	if r.x.RequiresGrad() {
+		x := r.x.Value().(mat.Matrix)
+		xData := x.Data().F64()
+		gyData := gy.Data().F64()
+		n := len(xData)
+		gxData := make([]float64, n)
+
+		// s accumulates sum_{i >= j} gy[i] * prod_{j < k <= i} x[k]
+		s := 0.0
+		for j := n - 1; j >= 0; j-- {
+			if j < n-1 {
+				s *= xData[j+1]
+			}
+			s += gyData[j]
+			gxData[j] = s
+		}
+		// multiply by the exclusive prefix product prod_{k < j} x[k]
+		p := 1.0
+		for j := 0; j < n; j++ {
+			gxData[j] *= p
+			p *= xData[j]
+		}
+		r.x.AccGrad(x.NewMatrix(mat.WithShape(x.Shape()...), mat.WithBacking(gxData)))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCumProd_Forward(t *testing.T) {
+	t.Run("float32", testCumProdForward[float32])
+	t.Run("float64", testCumProdForward[float64])
+}
+
+func testCumProdForward[T float.DType](t *testing.T) {
+	x := mat.NewDense[T](mat.WithShape(4), mat.WithBacking([]T{2, 0.5, -3, 4}), mat.WithGrad(true))
+
+	f := NewCumProd(x)
+	assert.Equal(t, []mat.Tensor{x}, f.Operands())
+
+	y, err := f.Forward()
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []T{2, 1, -3, -12}, y.Data(), 1.0e-6)
+
+	err = f.Backward(mat.NewDense[T](mat.WithShape(4), mat.WithBacking([]T{1, 2, 3, 4})))
+	assert.Nil(t, err)
+	// gx[0] = 1 + 2*0.5 + 3*0.5*-3 + 4*0.5*-3*4 = -26.5
+	// gx[1] = 2*2 + 3*2*-3 + 4*2*-3*4 = -110
+	// gx[2] = 3*2*0.5 + 4*2*0.5*4 = 19
+	// gx[3] = 4*2*0.5*-3 = -12
+	assert.InDeltaSlice(t, []T{-26.5, -110, 19, -12}, x.Grad().Data(), 1.0e-5)
+}
+
+func TestCumProd_BackwardWithZeros(t *testing.T) {
+	x := mat.NewDense[float64](mat.WithShape(3), mat.WithBacking([]float64{2, 0, 3}), mat.WithGrad(true))
+
+	f := NewCumProd(x)
+	_, _ = f.Forward()
+	err := f.Backward(mat.NewDense[float64](mat.WithShape(3), mat.WithBacking([]float64{1, 1, 1})))
+	assert.Nil(t, err)
+	// gx[0] = 1 + 0 + 0, gx[1] = 2 + 2*3, gx[2] = 2*0
+	assert.InDeltaSlice(t, []float64{1, 8, 0}, x.Grad().Data(), 1.0e-6)
+}
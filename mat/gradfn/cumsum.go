@@ -0,0 +1,53 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// CumSum is a Function computing the cumulative sum of the elements of
+// a vector, as a column vector.
+type CumSum[O mat.Tensor] struct {
+	x O
+}
+
+// NewCumSum returns a new CumSum Function.
+func NewCumSum[O mat.Tensor](x O) *CumSum[O] {
+	return &CumSum[O]{x: x}
+}
+
+// Operands returns the list of operands.
+func (r *CumSum[O]) Operands() []mat.Tensor {
+	return []mat.Tensor{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *CumSum[O]) Forward() (mat.Tensor, error) {
+	return r.x.Value().(mat.Matrix).CumSum(), nil
+}
+
+// Backward computes the backward pass.
+func (r *CumSum[O]) Backward(gy mat.Tensor) error {
+	if !(mat.IsVector(gy) && r.x.Value().Size() == gy.Size()) {
+		return fmt.Errorf("fn: vectors with not compatible size")
+	}
+	if r.x.RequiresGrad() {
+		// Each element contributes to all the following sums:
+		// the gradient is the reversed cumulative sum of gy.
+		gyData := gy.Data().F64()
+		gxData := make([]float64, len(gyData))
+		acc := 0.0
+		for i := len(gyData) - 1; i >= 0; i-- {
+			acc += gyData[i]
+			gxData[i] = acc
+		}
+		x := r.x.Value().(mat.Matrix)
+		r.x.AccGrad(x.NewMatrix(mat.WithShape(x.Shape()...), mat.WithBacking(gxData)))
+	}
+	return nil
+}
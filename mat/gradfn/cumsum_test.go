@@ -0,0 +1,36 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCumSum_Forward(t *testing.T) {
+	t.Run("float32", testCumSumForward[float32])
+	t.Run("float64", testCumSumForward[float64])
+}
+
+func testCumSumForward[T float.DType](t *testing.T) {
+	x := mat.NewDense[T](mat.WithShape(1, 4), mat.WithBacking([]T{0.1, 0.2, -0.3, 0.4}), mat.WithGrad(true))
+
+	f := NewCumSum(x)
+	assert.Equal(t, []mat.Tensor{x}, f.Operands())
+
+	y, err := f.Forward()
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []T{0.1, 0.3, 0.0, 0.4}, y.Data(), 1.0e-6)
+
+	err = f.Backward(mat.NewDense[T](mat.WithShape(4, 1), mat.WithBacking([]T{1, 2, 3, 4})))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 4}, x.Grad().Shape())
+	assert.InDeltaSlice(t, []T{10, 9, 7, 4}, x.Grad().Data(), 1.0e-6)
+
+	assert.NotNil(t, f.Backward(mat.NewDense[T](mat.WithShape(3, 1))))
+}
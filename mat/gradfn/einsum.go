@@ -0,0 +1,88 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Einsum is a Function evaluating the Einstein summation convention on the
+// operands, as described by mat.Einsum.
+type Einsum[O mat.Tensor] struct {
+	spec mat.EinsumSpec
+	xs   []O
+}
+
+// NewEinsum returns a new Einsum Function.
+// It panics if the specification is invalid.
+func NewEinsum[O mat.Tensor](spec string, xs []O) *Einsum[O] {
+	s, err := mat.ParseEinsumSpec(spec)
+	if err != nil {
+		panic("fn: " + err.Error())
+	}
+	if len(xs) != len(s.Inputs) {
+		panic(fmt.Sprintf("fn: einsum %q expects %d operands, got %d", s, len(s.Inputs), len(xs)))
+	}
+	return &Einsum[O]{
+		spec: s,
+		xs:   xs,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Einsum[O]) Operands() []mat.Tensor {
+	xs := make([]mat.Tensor, len(r.xs))
+	for i, x := range r.xs {
+		xs[i] = x
+	}
+	return xs
+}
+
+// Forward computes the output of the function.
+func (r *Einsum[O]) Forward() (mat.Tensor, error) {
+	return r.spec.Eval(nil, r.values()...), nil
+}
+
+// Backward computes the backward pass.
+//
+// The gradient of each operand is itself an Einstein summation, over the
+// output gradients and the other operands, producing the operand labels.
+func (r *Einsum[O]) Backward(gy mat.Tensor) error {
+	vs := r.values()
+	sizes := r.spec.LabelSizes(vs...)
+	if shape := r.spec.OutputShape(sizes); gy.Shape()[0] != shape[0] || gy.Shape()[1] != shape[1] {
+		return fmt.Errorf("fn: matrices have incompatible dimensions")
+	}
+
+	for k, x := range r.xs {
+		if !x.RequiresGrad() {
+			continue
+		}
+		gs := mat.EinsumSpec{
+			Inputs: []string{r.spec.Output},
+			Output: r.spec.Inputs[k],
+		}
+		operands := []mat.Matrix{gy.(mat.Matrix)}
+		for i, v := range vs {
+			if i != k {
+				gs.Inputs = append(gs.Inputs, r.spec.Inputs[i])
+				operands = append(operands, v)
+			}
+		}
+		gx := gs.Eval(sizes, operands...).ReshapeInPlace(x.Value().Shape()...)
+		x.AccGrad(gx)
+	}
+	return nil
+}
+
+func (r *Einsum[O]) values() []mat.Matrix {
+	vs := make([]mat.Matrix, len(r.xs))
+	for i, x := range r.xs {
+		vs[i] = x.Value().(mat.Matrix)
+	}
+	return vs
+}
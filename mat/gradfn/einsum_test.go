@@ -0,0 +1,121 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEinsum_Forward(t *testing.T) {
+	t.Run("float32", testEinsumForward[float32])
+	t.Run("float64", testEinsumForward[float64])
+}
+
+func testEinsumForward[T float.DType](t *testing.T) {
+	a := mat.NewDense[T](mat.WithShape(2, 3), mat.WithBacking([]T{
+		0.1, 0.2, 0.3,
+		0.4, 0.5, 0.6,
+	}), mat.WithGrad(true))
+	b := mat.NewDense[T](mat.WithShape(3, 2), mat.WithBacking([]T{
+		0.1, -0.2,
+		0.3, 0.4,
+		-0.5, 0.6,
+	}), mat.WithGrad(true))
+
+	f := NewEinsum("ij,jk->ik", []*mat.Dense[T]{a, b})
+	assert.Equal(t, []mat.Tensor{a, b}, f.Operands())
+
+	y, err := f.Forward()
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []T{
+		-0.08, 0.24,
+		-0.11, 0.48,
+	}, y.Data(), 1.0e-6)
+
+	gy := mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{
+		1, 2,
+		3, 4,
+	}))
+	err = f.Backward(gy)
+	assert.Nil(t, err)
+
+	// Same as the gradients of the matrix multiplication.
+	assert.InDeltaSlice(t, gy.Mul(b.T()).Data(), a.Grad().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, a.T().Mul(gy).Data(), b.Grad().Data(), 1.0e-6)
+
+	assert.NotNil(t, f.Backward(mat.NewDense[T](mat.WithShape(3, 2))))
+}
+
+func TestEinsum_Backward(t *testing.T) {
+	sq := []float64{0.1, -0.2, 0.3, 0.4}
+	rect := []float64{0.1, 0.2, 0.3, -0.4, 0.5, 0.6}
+	vec := []float64{0.3, -0.1, 0.2}
+
+	testCases := []struct {
+		spec   string
+		shapes [][]int
+		data   [][]float64
+	}{
+		{"ij->ji", [][]int{{2, 3}}, [][]float64{rect}},
+		{"ij->j", [][]int{{2, 3}}, [][]float64{rect}},
+		{"ii->i", [][]int{{2, 2}}, [][]float64{sq}},
+		{"ii->", [][]int{{2, 2}}, [][]float64{sq}},
+		{"ij,ij->i", [][]int{{2, 3}, {2, 3}}, [][]float64{rect, rect}},
+		{"ij,kj->ki", [][]int{{2, 3}, {2, 3}}, [][]float64{rect, rect}},
+		{"ij,j->i", [][]int{{2, 3}, {1, 3}}, [][]float64{rect, vec}},
+		{"i,j->ij", [][]int{{3, 1}, {1, 2}}, [][]float64{vec, sq[:2]}},
+		{"ij,jk,kl->il", [][]int{{2, 2}, {2, 2}, {2, 2}}, [][]float64{sq, sq, sq}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			xs := make([]*mat.Dense[float64], len(tc.shapes))
+			for i := range xs {
+				data := append([]float64{}, tc.data[i]...)
+				xs[i] = mat.NewDense[float64](mat.WithShape(tc.shapes[i]...), mat.WithBacking(data), mat.WithGrad(true))
+			}
+			f := NewEinsum(tc.spec, xs)
+			y, err := f.Forward()
+			assert.Nil(t, err)
+
+			// Use the sum of the outputs, weighted by gy, as the loss.
+			gy := y.(mat.Matrix).ZerosLike()
+			for i := range mat.Data[float64](gy) {
+				mat.Data[float64](gy)[i] = float64(i + 1)
+			}
+			loss := func() float64 {
+				y, _ := f.Forward()
+				return y.(mat.Matrix).Prod(gy).Sum().Item().F64()
+			}
+			assert.Nil(t, f.Backward(gy))
+
+			const eps = 1.0e-6
+			for i, x := range xs {
+				for j, v := range x.Data().F64() {
+					mat.Data[float64](x)[j] = v + eps
+					plus := loss()
+					mat.Data[float64](x)[j] = v - eps
+					minus := loss()
+					mat.Data[float64](x)[j] = v
+					expected := (plus - minus) / (2 * eps)
+					assert.InDeltaf(t, expected, x.Grad().Data().F64()[j], 1.0e-6, "operand %d, element %d", i, j)
+				}
+			}
+		})
+	}
+}
+
+func TestNewEinsum_BatchedMatrices(t *testing.T) {
+	x := mat.NewDense[float32](mat.WithShape(2, 2))
+	assert.PanicsWithValue(t, `fn: einsum: too many labels "bij": matrices have at most 2 dimensions, `+
+		`so batched specifications such as "bij,bjk->bik" must be applied to each item `+
+		`of the batch in their 2-dimensional form (e.g. "ij,jk->ik")`, func() {
+		NewEinsum("bij,bjk->bik", []mat.Tensor{x, x})
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Gather is a function to collect the values of the input matrix along an
+// axis, at the positions given by an index with the shape of the output:
+//
+//	y[i][j] = x[index[i][j]][j]  // axis 0
+//	y[i][j] = x[i][index[i][j]]  // axis 1
+type Gather[O mat.Tensor] struct {
+	x     O
+	axis  int
+	index [][]int
+}
+
+// NewGather returns a new Gather Function.
+func NewGather[O mat.Tensor](x O, axis int, index [][]int) *Gather[O] {
+	if axis != 0 && axis != 1 {
+		panic("fn: invalid axis")
+	}
+	return &Gather[O]{
+		x:     x,
+		axis:  axis,
+		index: index,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Gather[O]) Operands() []mat.Tensor {
+	return []mat.Tensor{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *Gather[O]) Forward() (mat.Tensor, error) {
+	return r.x.Value().(mat.Matrix).Gather(r.axis, r.index), nil
+}
+
+// Backward computes the backward pass.
+func (r *Gather[O]) Backward(gy mat.Tensor) error {
+	if gy.Shape()[0] != len(r.index) || (len(r.index) > 0 && gy.Shape()[1] != len(r.index[0])) {
+		return fmt.Errorf("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		gx := r.x.Value().(mat.Matrix).ZerosLike()
+		gx.ScatterAddInPlace(r.axis, r.index, gy.(mat.Matrix))
+		r.x.AccGrad(gx)
+	}
+	return nil
+}
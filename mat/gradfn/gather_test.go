@@ -0,0 +1,70 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGather_Forward(t *testing.T) {
+	t.Run("float32", testGatherForward[float32])
+	t.Run("float64", testGatherForward[float64])
+}
+
+func testGatherForward[T float.DType](t *testing.T) {
+	t.Run("axis 0", func(t *testing.T) {
+		x := mat.NewDense[T](mat.WithShape(3, 2), mat.WithBacking([]T{
+			0.1, 0.2,
+			0.3, 0.4,
+			0.5, 0.6,
+		}), mat.WithGrad(true))
+
+		f := NewGather(x, 0, [][]int{{2, 0}, {2, 2}})
+		assert.Equal(t, []mat.Tensor{x}, f.Operands())
+
+		y, err := f.Forward()
+		assert.Nil(t, err)
+		assert.InDeltaSlice(t, []T{
+			0.5, 0.2,
+			0.5, 0.6,
+		}, y.Data(), 1.0e-6)
+
+		err = f.Backward(mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{
+			1, 2,
+			3, 4,
+		})))
+		assert.Nil(t, err)
+		assert.InDeltaSlice(t, []T{
+			0, 2,
+			0, 0,
+			4, 4,
+		}, x.Grad().Data(), 1.0e-6)
+	})
+
+	t.Run("axis 1", func(t *testing.T) {
+		// A typical label lookup: one column for each row.
+		x := mat.NewDense[T](mat.WithShape(2, 3), mat.WithBacking([]T{
+			0.1, 0.2, 0.3,
+			0.4, 0.5, 0.6,
+		}), mat.WithGrad(true))
+
+		f := NewGather(x, 1, [][]int{{2}, {0}})
+		y, err := f.Forward()
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2, 1}, y.Shape())
+		assert.InDeltaSlice(t, []T{0.3, 0.4}, y.Data(), 1.0e-6)
+
+		err = f.Backward(mat.NewDense[T](mat.WithShape(2, 1), mat.WithBacking([]T{1, 2})))
+		assert.Nil(t, err)
+		assert.InDeltaSlice(t, []T{
+			0, 0, 1,
+			2, 0, 0,
+		}, x.Grad().Data(), 1.0e-6)
+	})
+}
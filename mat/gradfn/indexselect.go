@@ -0,0 +1,56 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// IndexSelect is a function to extract the rows (axis 0) or the columns
+// (axis 1) of the input matrix at the given indices.
+type IndexSelect[O mat.Tensor] struct {
+	x       O
+	axis    int
+	indices []int
+}
+
+// NewIndexSelect returns a new IndexSelect Function.
+func NewIndexSelect[O mat.Tensor](x O, axis int, indices []int) *IndexSelect[O] {
+	if axis != 0 && axis != 1 {
+		panic("fn: invalid axis")
+	}
+	return &IndexSelect[O]{
+		x:       x,
+		axis:    axis,
+		indices: indices,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *IndexSelect[O]) Operands() []mat.Tensor {
+	return []mat.Tensor{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *IndexSelect[O]) Forward() (mat.Tensor, error) {
+	return r.x.Value().(mat.Matrix).IndexSelect(r.axis, r.indices...), nil
+}
+
+// Backward computes the backward pass.
+func (r *IndexSelect[O]) Backward(gy mat.Tensor) error {
+	shape := append([]int{}, r.x.Value().Shape()...)
+	shape[r.axis] = len(r.indices)
+	if !(gy.Shape()[0] == shape[0] && gy.Shape()[1] == shape[1]) {
+		return fmt.Errorf("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		gx := r.x.Value().(mat.Matrix).ZerosLike()
+		gx.IndexAddInPlace(r.axis, r.indices, gy.(mat.Matrix))
+		r.x.AccGrad(gx)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexSelect_Forward(t *testing.T) {
+	t.Run("float32", testIndexSelectForward[float32])
+	t.Run("float64", testIndexSelectForward[float64])
+}
+
+func testIndexSelectForward[T float.DType](t *testing.T) {
+	t.Run("rows", func(t *testing.T) {
+		x := mat.NewDense[T](mat.WithShape(3, 2), mat.WithBacking([]T{
+			0.1, 0.2,
+			0.3, 0.4,
+			0.5, 0.6,
+		}), mat.WithGrad(true))
+
+		f := NewIndexSelect(x, 0, []int{2, 0, 2})
+		assert.Equal(t, []mat.Tensor{x}, f.Operands())
+
+		y, err := f.Forward()
+		assert.Nil(t, err)
+		assert.Equal(t, []int{3, 2}, y.Shape())
+		assert.InDeltaSlice(t, []T{
+			0.5, 0.6,
+			0.1, 0.2,
+			0.5, 0.6,
+		}, y.Data(), 1.0e-6)
+
+		err = f.Backward(mat.NewDense[T](mat.WithShape(3, 2), mat.WithBacking([]T{
+			1, 2,
+			3, 4,
+			5, 6,
+		})))
+		assert.Nil(t, err)
+		assert.InDeltaSlice(t, []T{
+			3, 4,
+			0, 0,
+			6, 8,
+		}, x.Grad().Data(), 1.0e-6)
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		x := mat.NewDense[T](mat.WithShape(2, 3), mat.WithBacking([]T{
+			0.1, 0.2, 0.3,
+			0.4, 0.5, 0.6,
+		}), mat.WithGrad(true))
+
+		f := NewIndexSelect(x, 1, []int{1, 1})
+		y, err := f.Forward()
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2, 2}, y.Shape())
+		assert.InDeltaSlice(t, []T{
+			0.2, 0.2,
+			0.5, 0.5,
+		}, y.Data(), 1.0e-6)
+
+		err = f.Backward(mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{
+			1, 2,
+			3, 4,
+		})))
+		assert.Nil(t, err)
+		assert.InDeltaSlice(t, []T{
+			0, 3, 0,
+			0, 7, 0,
+		}, x.Grad().Data(), 1.0e-6)
+	})
+
+	t.Run("incompatible gradients", func(t *testing.T) {
+		x := mat.NewDense[T](mat.WithShape(3, 2), mat.WithGrad(true))
+		f := NewIndexSelect(x, 0, []int{1})
+		_, _ = f.Forward()
+		assert.NotNil(t, f.Backward(mat.NewDense[T](mat.WithShape(2, 2))))
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/backend"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// kernel is a typed implementation of an element-wise function, operating
+// directly on the raw data of dense matrices, so that y[i] = f(x[i]).
+//
+// Any of the two functions can be nil, in which case the generic
+// implementation is used for the corresponding data type.
+type kernel struct {
+	f32 func(x, y []float32)
+	f64 func(x, y []float64)
+}
+
+// apply returns a new matrix, the result of applying the kernel to each
+// element of m. It falls back to m.Apply(fn) if m is not a dense matrix
+// with a data type supported by the kernel.
+func (k kernel) apply(m mat.Matrix, fn func(i, j int, v float64) float64) mat.Matrix {
+	switch d := m.(type) {
+	case *mat.Dense[float32]:
+		if k.f32 != nil {
+			return applyKernel(d, k.f32)
+		}
+	case *mat.Dense[float64]:
+		if k.f64 != nil {
+			return applyKernel(d, k.f64)
+		}
+	}
+	return m.Apply(fn)
+}
+
+func applyKernel[T float.DType](d *mat.Dense[T], fn func(x, y []T)) mat.Matrix {
+	y := make([]T, d.Size())
+	fn(mat.Data[T](d), y)
+	return d.NewMatrix(mat.WithShape(d.Shape()...), mat.WithBacking(y))
+}
+
+var (
+	tanKernel              = kernel{tanVec[float32], tanVec[float64]}
+	tanDerivKernel         = kernel{tanDerivVec[float32], tanDerivVec[float64]}
+	tanhKernel             = kernel{tanhVec[float32], tanhVec[float64]}
+	tanhDerivKernel        = kernel{tanhDerivVec(tanhVec[float32]), tanhDerivVec(tanhVec[float64])}
+	sigmoidDerivKernel     = kernel{sigmoidDerivVec(sigmoidVec[float32]), sigmoidDerivVec(sigmoidVec[float64])}
+	siluKernel             = kernel{siluVec[float32], siluVec[float64]}
+	siluDerivKernel        = kernel{siluDerivVec(sigmoidVec[float32]), siluDerivVec(sigmoidVec[float64])}
+	geluKernel             = kernel{geluVec[float32], geluVec[float64]}
+	geluDerivKernel        = kernel{geluDerivVec(tanhVec[float32]), geluDerivVec(tanhVec[float64])}
+	hardSigmoidKernel      = kernel{hardSigmoidVec[float32], hardSigmoidVec[float64]}
+	hardSigmoidDerivKernel = kernel{hardSigmoidDerivVec[float32], hardSigmoidDerivVec[float64]}
+	hardTanhKernel         = kernel{hardTanhVec[float32], hardTanhVec[float64]}
+	hardTanhDerivKernel    = kernel{hardTanhDerivVec[float32], hardTanhDerivVec[float64]}
+	reluKernel             = kernel{reluVec[float32], reluVec[float64]}
+	reluDerivKernel        = kernel{reluDerivVec[float32], reluDerivVec[float64]}
+	softsignKernel         = kernel{softsignVec[float32], softsignVec[float64]}
+	softsignDerivKernel    = kernel{softsignDerivVec[float32], softsignDerivVec[float64]}
+	cosKernel              = kernel{cosVec[float32], cosVec[float64]}
+	sinKernel              = kernel{sinVec[float32], sinVec[float64]}
+	negSinKernel           = kernel{negSinVec[float32], negSinVec[float64]}
+	negKernel              = kernel{negVec[float32], negVec[float64]}
+	negDerivKernel         = kernel{constVec[float32](-1), constVec[float64](-1)}
+	reciprocalKernel       = kernel{reciprocalVec[float32], reciprocalVec[float64]}
+	reciprocalDerivKernel  = kernel{reciprocalDerivVec[float32], reciprocalDerivVec[float64]}
+	absKernel              = kernel{absVec[float32], absVec[float64]}
+	absDerivKernel         = kernel{absDerivVec[float32], absDerivVec[float64]}
+	mishKernel             = kernel{mishVec[float32], mishVec[float64]}
+	mishDerivKernel        = kernel{mishDerivVec[float32], mishDerivVec[float64]}
+)
+
+func tanhVec[F float.DType](x, y []F) {
+	backend.For[F]().Tanh(x, y)
+}
+
+func sigmoidVec[F float.DType](x, y []F) {
+	backend.For[F]().Sigmoid(x, y)
+}
+
+func siluVec[F float.DType](x, y []F) {
+	backend.For[F]().SiLU(x, y)
+}
+
+func geluVec[F float.DType](x, y []F) {
+	backend.For[F]().GELU(x, y)
+}
+
+func tanVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = F(math.Tan(float64(v)))
+	}
+}
+
+func tanDerivVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		c := math.Cos(float64(v))
+		y[i] = F(1 / (c * c))
+	}
+}
+
+// tanhDerivVec returns a kernel computing 1 - tanh(x)^2 on top of the given
+// tanh kernel.
+func tanhDerivVec[F float32 | float64](tanh func(x, y []F)) func(x, y []F) {
+	return func(x, y []F) {
+		tanh(x, y)
+		for i, t := range y {
+			y[i] = 1 - t*t
+		}
+	}
+}
+
+// sigmoidDerivVec returns a kernel computing s * (1 - s), with s = sigmoid(x),
+// on top of the given sigmoid kernel.
+func sigmoidDerivVec[F float32 | float64](sigmoid func(x, y []F)) func(x, y []F) {
+	return func(x, y []F) {
+		sigmoid(x, y)
+		for i, s := range y {
+			y[i] = s * (1 - s)
+		}
+	}
+}
+
+// siluDerivVec returns a kernel computing s * (1 + x * (1 - s)),
+// with s = sigmoid(x), on top of the given sigmoid kernel.
+func siluDerivVec[F float32 | float64](sigmoid func(x, y []F)) func(x, y []F) {
+	return func(x, y []F) {
+		sigmoid(x, y)
+		for i, s := range y {
+			y[i] = s * (1 + x[i]*(1-s))
+		}
+	}
+}
+
+// geluDerivVec returns a kernel computing the derivative of the tanh
+// approximation of GELU on top of the given tanh kernel.
+func geluDerivVec[F float32 | float64](tanh func(x, y []F)) func(x, y []F) {
+	const c = 0.7978845608028654 // sqrt(2/pi)
+	return func(x, y []F) {
+		for i, v := range x {
+			y[i] = c * (v + 0.044715*v*v*v)
+		}
+		tanh(y, y)
+		for i, t := range y {
+			v := x[i]
+			y[i] = 0.5*(1+t) + 0.5*v*(1-t*t)*c*(1+3*0.044715*v*v)
+		}
+	}
+}
+
+func hardSigmoidVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		switch {
+		case v > 2.5:
+			y[i] = 1
+		case v < -2.5:
+			y[i] = 0
+		default:
+			y[i] = 0.2*v + 0.5
+		}
+	}
+}
+
+func hardSigmoidDerivVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		if v < 2.5 && v > -2.5 {
+			y[i] = 0.2
+		} else {
+			y[i] = 0
+		}
+	}
+}
+
+func hardTanhVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		switch {
+		case v > 1:
+			y[i] = 1
+		case v < -1:
+			y[i] = -1
+		default:
+			y[i] = v
+		}
+	}
+}
+
+func hardTanhDerivVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		if v < 1 && v > -1 {
+			y[i] = 1
+		} else {
+			y[i] = 0
+		}
+	}
+}
+
+func reluVec[F float.DType](x, y []F) {
+	backend.For[F]().ReLU(x, y)
+}
+
+func reluDerivVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		if v >= 0 {
+			y[i] = 1
+		} else {
+			y[i] = 0
+		}
+	}
+}
+
+func softsignVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = v / (1 + abs(v))
+	}
+}
+
+func softsignDerivVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		d := 1 + abs(v)
+		y[i] = 1 / (d * d)
+	}
+}
+
+func cosVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = F(math.Cos(float64(v)))
+	}
+}
+
+func sinVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = F(math.Sin(float64(v)))
+	}
+}
+
+func negSinVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = F(-math.Sin(float64(v)))
+	}
+}
+
+func negVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = -v
+	}
+}
+
+func constVec[F float32 | float64](c F) func(x, y []F) {
+	return func(x, y []F) {
+		for i := range x {
+			y[i] = c
+		}
+	}
+}
+
+func reciprocalVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = 1 / v
+	}
+}
+
+func reciprocalDerivVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = -1 / (v * v)
+	}
+}
+
+func absVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = abs(v)
+	}
+}
+
+func absDerivVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		switch {
+		case v < 0:
+			y[i] = -1
+		case v > 0:
+			y[i] = 1
+		default:
+			y[i] = 0 // undefined
+		}
+	}
+}
+
+func mishVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = F(mish(0, 0, float64(v)))
+	}
+}
+
+func mishDerivVec[F float32 | float64](x, y []F) {
+	for i, v := range x {
+		y[i] = F(mishDeriv(0, 0, float64(v)))
+	}
+}
+
+func abs[F float32 | float64](v F) F {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
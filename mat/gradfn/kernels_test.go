@@ -0,0 +1,151 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+var kernelsForTesting = []struct {
+	name string
+	k    kernel
+	fn   func(i, j int, v float64) float64
+}{
+	{"tan", tanKernel, tan},
+	{"tanDeriv", tanDerivKernel, tanDeriv},
+	{"tanh", tanhKernel, tanh},
+	{"tanhDeriv", tanhDerivKernel, tanhDeriv},
+	{"sigmoidDeriv", sigmoidDerivKernel, sigmoidDeriv},
+	{"silu", siluKernel, silu},
+	{"siluDeriv", siluDerivKernel, swishDeriv},
+	{"gelu", geluKernel, gelu},
+	{"geluDeriv", geluDerivKernel, geluDeriv},
+	{"hardSigmoid", hardSigmoidKernel, hardSigmoid},
+	{"hardSigmoidDeriv", hardSigmoidDerivKernel, hardSigmoidDeriv},
+	{"hardTanh", hardTanhKernel, hardTanh},
+	{"hardTanhDeriv", hardTanhDerivKernel, hardTanhDeriv},
+	{"relu", reluKernel, relu},
+	{"reluDeriv", reluDerivKernel, reluDeriv},
+	{"softsign", softsignKernel, softsign},
+	{"softsignDeriv", softsignDerivKernel, softsignDeriv},
+	{"cos", cosKernel, func(_, _ int, v float64) float64 { return math.Cos(v) }},
+	{"sin", sinKernel, func(_, _ int, v float64) float64 { return math.Sin(v) }},
+	{"negSin", negSinKernel, func(_, _ int, v float64) float64 { return -math.Sin(v) }},
+	{"neg", negKernel, func(_, _ int, v float64) float64 { return -v }},
+	{"negDeriv", negDerivKernel, func(_, _ int, v float64) float64 { return -1 }},
+	{"reciprocal", reciprocalKernel, func(_, _ int, v float64) float64 { return 1 / v }},
+	{"reciprocalDeriv", reciprocalDerivKernel, func(_, _ int, v float64) float64 { return -1 / (v * v) }},
+	{"abs", absKernel, func(_, _ int, v float64) float64 { return math.Abs(v) }},
+	{"absDeriv", absDerivKernel, absDeriv},
+	{"mish", mishKernel, mish},
+	{"mishDeriv", mishDerivKernel, mishDeriv},
+}
+
+func TestKernels(t *testing.T) {
+	t.Run("float32", testKernels[float32])
+	t.Run("float64", testKernels[float64])
+}
+
+func testKernels[T float.DType](t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	data := []float64{-3, -2.5, -1, -0.5, 0, 0.5, 1, 2.5, 3}
+	for i := 0; i < 1001; i++ {
+		data = append(data, r.NormFloat64()*2)
+	}
+	x := mat.NewDense[T](mat.WithShape(10, 101), mat.WithBacking(data))
+
+	for _, tc := range kernelsForTesting {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := x.Apply(tc.fn)
+			actual := tc.k.apply(x, tc.fn)
+			assert.Equal(t, expected.Shape(), actual.Shape())
+			// relative tolerance, since some derivatives grow large
+			exp, act := expected.Data().F64(), actual.Data().F64()
+			for i := range exp {
+				assert.InDelta(t, exp[i], act[i], 1.0e-5*math.Max(1, math.Abs(exp[i])), "x = %g", data[i])
+			}
+		})
+	}
+}
+
+func TestKernel_Fallback(t *testing.T) {
+	k := kernel{f64: reluVec[float64]}
+	x := mat.NewDense[float32](mat.WithShape(2), mat.WithBacking([]float32{-1, 2}))
+	y := k.apply(x, relu)
+	assert.IsType(t, &mat.Dense[float32]{}, y)
+	assert.Equal(t, []float32{0, 2}, mat.Data[float32](y))
+}
+
+func BenchmarkTanh_Forward(b *testing.B) {
+	b.Run("float32", benchmarkUnaryForward[float32](NewTanh[mat.Tensor]))
+	b.Run("float64", benchmarkUnaryForward[float64](NewTanh[mat.Tensor]))
+	b.Run("float32-apply", benchmarkApply[float32](tanh))
+}
+
+func BenchmarkGELU_Forward(b *testing.B) {
+	b.Run("float32", benchmarkUnaryForward[float32](NewGELU[mat.Tensor]))
+	b.Run("float64", benchmarkUnaryForward[float64](NewGELU[mat.Tensor]))
+	b.Run("float32-apply", benchmarkApply[float32](gelu))
+}
+
+func BenchmarkSwish_Forward(b *testing.B) {
+	b.Run("float32", benchmarkUnaryForward[float32](NewSwish[mat.Tensor]))
+	b.Run("float64", benchmarkUnaryForward[float64](NewSwish[mat.Tensor]))
+	b.Run("float32-apply", benchmarkApply[float32](silu))
+}
+
+func BenchmarkGELU_Backward(b *testing.B) {
+	b.Run("float32", benchmarkUnaryBackward[float32](NewGELU[mat.Tensor]))
+	b.Run("float64", benchmarkUnaryBackward[float64](NewGELU[mat.Tensor]))
+}
+
+func benchmarkUnaryForward[T float.DType, F interface{ Forward() (mat.Tensor, error) }](newFn func(mat.Tensor) F) func(b *testing.B) {
+	return func(b *testing.B) {
+		f := newFn(newBenchmarkMatrix[T]())
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = f.Forward()
+		}
+	}
+}
+
+func benchmarkUnaryBackward[T float.DType, F interface{ Backward(mat.Tensor) error }](newFn func(mat.Tensor) F) func(b *testing.B) {
+	return func(b *testing.B) {
+		x := newBenchmarkMatrix[T]()
+		gy := newBenchmarkMatrix[T]()
+		f := newFn(x)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = f.Backward(gy)
+			x.ZeroGrad()
+		}
+	}
+}
+
+func benchmarkApply[T float.DType](fn func(i, j int, v float64) float64) func(b *testing.B) {
+	return func(b *testing.B) {
+		x := newBenchmarkMatrix[T]()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = x.Apply(fn)
+		}
+	}
+}
+
+func newBenchmarkMatrix[T float.DType]() *mat.Dense[T] {
+	r := rand.New(rand.NewSource(42))
+	data := make([]T, 256*256)
+	for i := range data {
+		data[i] = T(r.NormFloat64())
+	}
+	return mat.NewDense[T](mat.WithShape(256, 256), mat.WithBacking(data), mat.WithGrad(true))
+}
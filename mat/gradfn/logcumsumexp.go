@@ -0,0 +1,68 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// LogCumSumExp is a Function computing the logarithm of the cumulative sum
+// of the exponentials of the elements of a vector, as a column vector.
+type LogCumSumExp[O mat.Tensor] struct {
+	x O
+	y mat.Matrix // initialized during the forward pass, required by the backward pass
+}
+
+// NewLogCumSumExp returns a new LogCumSumExp Function.
+func NewLogCumSumExp[O mat.Tensor](x O) *LogCumSumExp[O] {
+	return &LogCumSumExp[O]{x: x}
+}
+
+// Operands returns the list of operands.
+func (r *LogCumSumExp[O]) Operands() []mat.Tensor {
+	return []mat.Tensor{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *LogCumSumExp[O]) Forward() (mat.Tensor, error) {
+	r.y = r.x.Value().(mat.Matrix).LogCumSumExp()
+	return r.y, nil
+}
+
+// Backward computes the backward pass.
+//
+// The gradient of x[j] is the sum, over i >= j, of gy[i] * exp(x[j] - y[i]).
+// It is computed in linear time with the recurrence
+// s[j] = gy[j] + exp(y[j] - y[j+1]) * s[j+1], so that gx[j] = exp(x[j] - y[j]) * s[j],
+// where all the exponents are non-positive.
+func (r *LogCumSumExp[O]) Backward(gy mat.Tensor) error {
+	if !(mat.IsVector(gy) && r.x.Value().Size() == gy.Size()) {
+		return fmt.Errorf("fn: vectors with not compatible size")
+	}
+	if r.x.RequiresGrad() {
+		x := r.x.Value().(mat.Matrix)
+		xData := x.Data().F64()
+		yData := r.y.Data().F64()
+		gyData := gy.Data().F64()
+		n := len(xData)
+		gxData := make([]float64, n)
+
+		s := 0.0
+		for j := n - 1; j >= 0; j-- {
+			if j < n-1 && s != 0 {
+				s *= math.Exp(yData[j] - yData[j+1])
+			}
+			s += gyData[j]
+			if s != 0 && !math.IsInf(xData[j], -1) {
+				gxData[j] = math.Exp(xData[j]-yData[j]) * s
+			}
+		}
+		r.x.AccGrad(x.NewMatrix(mat.WithShape(x.Shape()...), mat.WithBacking(gxData)))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogCumSumExp_Forward(t *testing.T) {
+	t.Run("float32", testLogCumSumExpForward[float32])
+	t.Run("float64", testLogCumSumExpForward[float64])
+}
+
+func testLogCumSumExpForward[T float.DType](t *testing.T) {
+	xs := []float64{0.1, -0.2, 0.3}
+	x := mat.NewDense[T](mat.WithShape(3), mat.WithBacking([]T{0.1, -0.2, 0.3}), mat.WithGrad(true))
+
+	f := NewLogCumSumExp(x)
+	assert.Equal(t, []mat.Tensor{x}, f.Operands())
+
+	y, err := f.Forward()
+	assert.Nil(t, err)
+
+	// Naive reference implementation.
+	ys := make([]float64, len(xs))
+	sum := 0.0
+	for i, v := range xs {
+		sum += math.Exp(v)
+		ys[i] = math.Log(sum)
+	}
+	assert.InDeltaSlice(t, ys, y.Data().F64(), 1.0e-6)
+
+	gy := []float64{1, 2, 3}
+	err = f.Backward(mat.NewDense[T](mat.WithShape(3), mat.WithBacking([]T{1, 2, 3})))
+	assert.Nil(t, err)
+
+	gx := make([]float64, len(xs))
+	for j := range xs {
+		for i := j; i < len(xs); i++ {
+			gx[j] += gy[i] * math.Exp(xs[j]-ys[i])
+		}
+	}
+	assert.InDeltaSlice(t, gx, x.Grad().Data().F64(), 1.0e-6)
+}
+
+func TestLogCumSumExp_BackwardLargeValues(t *testing.T) {
+	x := mat.NewDense[float64](mat.WithShape(3), mat.WithBacking([]float64{1000, 1000, math.Inf(-1)}), mat.WithGrad(true))
+
+	f := NewLogCumSumExp(x)
+	_, _ = f.Forward()
+	err := f.Backward(mat.NewDense[float64](mat.WithShape(3), mat.WithBacking([]float64{1, 1, 1})))
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{2, 1, 0}, x.Grad().Data(), 1.0e-6)
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// MaskedFill is a Function replacing the values of x with a constant
+// where the mask is non-zero.
+// The mask is constant: it does not receive gradients.
+type MaskedFill[O mat.Tensor] struct {
+	x     O
+	mask  mat.Tensor
+	value float64
+}
+
+// NewMaskedFill returns a new MaskedFill Function.
+func NewMaskedFill[O mat.Tensor](x O, mask mat.Tensor, value float64) *MaskedFill[O] {
+	return &MaskedFill[O]{
+		x:     x,
+		mask:  mask,
+		value: value,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *MaskedFill[O]) Operands() []mat.Tensor {
+	return []mat.Tensor{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *MaskedFill[O]) Forward() (mat.Tensor, error) {
+	x := r.x.Value().(mat.Matrix)
+	mask := r.mask.Value().(mat.Matrix)
+	if !mat.SameDims(x, mask) {
+		return nil, fmt.Errorf("fn: matrices have incompatible dimensions")
+	}
+	return x.MaskedFill(mask, r.value), nil
+}
+
+// Backward computes the backward pass.
+func (r *MaskedFill[O]) Backward(gy mat.Tensor) error {
+	if !mat.SameDims(r.x.Value(), gy) {
+		return fmt.Errorf("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		r.x.AccGrad(gy.(mat.Matrix).MaskedFill(r.mask.Value().(mat.Matrix), 0))
+	}
+	return nil
+}
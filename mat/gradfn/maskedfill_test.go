@@ -0,0 +1,35 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskedFill_Forward(t *testing.T) {
+	t.Run("float32", testMaskedFillForward[float32])
+	t.Run("float64", testMaskedFillForward[float64])
+}
+
+func testMaskedFillForward[T float.DType](t *testing.T) {
+	x := mat.NewDense[T](mat.WithShape(1, 4), mat.WithBacking([]T{0.1, 0.2, 0.3, 0.4}), mat.WithGrad(true))
+	mask := mat.NewDense[T](mat.WithShape(1, 4), mat.WithBacking([]T{0, 0, 1, 1}))
+
+	f := NewMaskedFill(x, mask, math.Inf(-1))
+	assert.Equal(t, []mat.Tensor{x}, f.Operands())
+
+	y, err := f.Forward()
+	assert.Nil(t, err)
+	assert.Equal(t, []T{0.1, 0.2, T(math.Inf(-1)), T(math.Inf(-1))}, mat.Data[T](y))
+
+	err = f.Backward(mat.NewDense[T](mat.WithShape(1, 4), mat.WithBacking([]T{1, 2, 3, 4})))
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []T{1, 2, 0, 0}, x.Grad().Data(), 1.0e-6)
+}
@@ -18,9 +18,11 @@ type Tan[O mat.Tensor] struct {
 func NewTan[O mat.Tensor](x O) *Tan[O] {
 	return &Tan[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  tan,
-			df: tanDeriv,
+			x:   x,
+			f:   tan,
+			df:  tanDeriv,
+			fk:  tanKernel,
+			dfk: tanDerivKernel,
 		},
 	}
 }
@@ -34,9 +36,11 @@ type Tanh[O mat.Tensor] struct {
 func NewTanh[O mat.Tensor](x O) *Tanh[O] {
 	return &Tanh[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  tanh,
-			df: tanhDeriv,
+			x:   x,
+			f:   tanh,
+			df:  tanhDeriv,
+			fk:  tanhKernel,
+			dfk: tanhDerivKernel,
 		},
 	}
 }
@@ -50,9 +54,11 @@ type HardSigmoid[O mat.Tensor] struct {
 func NewHardSigmoid[O mat.Tensor](x O) *HardSigmoid[O] {
 	return &HardSigmoid[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  hardSigmoid,
-			df: hardSigmoidDeriv,
+			x:   x,
+			f:   hardSigmoid,
+			df:  hardSigmoidDeriv,
+			fk:  hardSigmoidKernel,
+			dfk: hardSigmoidDerivKernel,
 		},
 	}
 }
@@ -66,9 +72,11 @@ type HardTanh[O mat.Tensor] struct {
 func NewHardTanh[O mat.Tensor](x O) *HardTanh[O] {
 	return &HardTanh[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  hardTanh,
-			df: hardTanhDeriv,
+			x:   x,
+			f:   hardTanh,
+			df:  hardTanhDeriv,
+			fk:  hardTanhKernel,
+			dfk: hardTanhDerivKernel,
 		},
 	}
 }
@@ -82,9 +90,11 @@ type ReLU[O mat.Tensor] struct {
 func NewReLU[O mat.Tensor](x O) *ReLU[O] {
 	return &ReLU[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  relu,
-			df: reluDeriv,
+			x:   x,
+			f:   relu,
+			df:  reluDeriv,
+			fk:  reluKernel,
+			dfk: reluDerivKernel,
 		},
 	}
 }
@@ -98,9 +108,11 @@ type Softsign[O mat.Tensor] struct {
 func NewSoftsign[O mat.Tensor](x O) *Softsign[O] {
 	return &Softsign[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  softsign,
-			df: softsignDeriv,
+			x:   x,
+			f:   softsign,
+			df:  softsignDeriv,
+			fk:  softsignKernel,
+			dfk: softsignDerivKernel,
 		},
 	}
 }
@@ -114,9 +126,11 @@ type Cos[O mat.Tensor] struct {
 func NewCos[O mat.Tensor](x O) *Cos[O] {
 	return &Cos[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(_, _ int, v float64) float64 { return math.Cos(v) },
-			df: func(_, _ int, v float64) float64 { return -math.Sin(v) },
+			x:   x,
+			f:   func(_, _ int, v float64) float64 { return math.Cos(v) },
+			df:  func(_, _ int, v float64) float64 { return -math.Sin(v) },
+			fk:  cosKernel,
+			dfk: negSinKernel,
 		},
 	}
 }
@@ -130,9 +144,11 @@ type Sin[O mat.Tensor] struct {
 func NewSin[O mat.Tensor](x O) *Sin[O] {
 	return &Sin[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return math.Sin(v) },
-			df: func(i, j int, v float64) float64 { return math.Cos(v) },
+			x:   x,
+			f:   func(i, j int, v float64) float64 { return math.Sin(v) },
+			df:  func(i, j int, v float64) float64 { return math.Cos(v) },
+			fk:  sinKernel,
+			dfk: cosKernel,
 		},
 	}
 }
@@ -146,9 +162,11 @@ type Neg[O mat.Tensor] struct {
 func NewNeg[O mat.Tensor](x O) *Neg[O] {
 	return &Neg[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return -v },
-			df: func(i, j int, v float64) float64 { return -1.0 },
+			x:   x,
+			f:   func(i, j int, v float64) float64 { return -v },
+			df:  func(i, j int, v float64) float64 { return -1.0 },
+			fk:  negKernel,
+			dfk: negDerivKernel,
 		},
 	}
 }
@@ -162,9 +180,11 @@ type Reciprocal[O mat.Tensor] struct {
 func NewReciprocal[O mat.Tensor](x O) *Reciprocal[O] {
 	return &Reciprocal[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return 1.0 / v },
-			df: func(i, j int, v float64) float64 { return -1.0 / (v * v) },
+			x:   x,
+			f:   func(i, j int, v float64) float64 { return 1.0 / v },
+			df:  func(i, j int, v float64) float64 { return -1.0 / (v * v) },
+			fk:  reciprocalKernel,
+			dfk: reciprocalDerivKernel,
 		},
 	}
 }
@@ -178,9 +198,11 @@ type Abs[O mat.Tensor] struct {
 func NewAbs[O mat.Tensor](x O) *Abs[O] {
 	return &Abs[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return math.Abs(v) },
-			df: absDeriv,
+			x:   x,
+			f:   func(i, j int, v float64) float64 { return math.Abs(v) },
+			df:  absDeriv,
+			fk:  absKernel,
+			dfk: absDerivKernel,
 		},
 	}
 }
@@ -200,9 +222,11 @@ type Mish[O mat.Tensor] struct {
 func NewMish[O mat.Tensor](x O) *Mish[O] {
 	return &Mish[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  mish,
-			df: mishDeriv,
+			x:   x,
+			f:   mish,
+			df:  mishDeriv,
+			fk:  mishKernel,
+			dfk: mishDerivKernel,
 		},
 	}
 }
@@ -216,9 +240,11 @@ type GELU[O mat.Tensor] struct {
 func NewGELU[O mat.Tensor](x O) *GELU[O] {
 	return &GELU[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  gelu,
-			df: geluDeriv,
+			x:   x,
+			f:   gelu,
+			df:  geluDeriv,
+			fk:  geluKernel,
+			dfk: geluDerivKernel,
 		},
 	}
 }
@@ -229,6 +255,40 @@ func NewSiLU[O mat.Tensor](x O) *Swish[O] {
 	return NewSwish[O](x)
 }
 
+// LogGamma is an operator to perform element-wise natural logarithm of the
+// absolute value of the Gamma function.
+type LogGamma[O mat.Tensor] struct {
+	*UnaryElementwise[O]
+}
+
+// NewLogGamma returns a new UnaryElementwise log-gamma function.
+func NewLogGamma[O mat.Tensor](x O) *LogGamma[O] {
+	return &LogGamma[O]{
+		UnaryElementwise: &UnaryElementwise[O]{
+			x:  x,
+			f:  logGamma,
+			df: digamma,
+		},
+	}
+}
+
+// Digamma is an operator to perform element-wise digamma function, that is
+// the derivative of the log-gamma function.
+type Digamma[O mat.Tensor] struct {
+	*UnaryElementwise[O]
+}
+
+// NewDigamma returns a new UnaryElementwise digamma function.
+func NewDigamma[O mat.Tensor](x O) *Digamma[O] {
+	return &Digamma[O]{
+		UnaryElementwise: &UnaryElementwise[O]{
+			x:  x,
+			f:  digamma,
+			df: trigamma,
+		},
+	}
+}
+
 func absDeriv(_, _ int, v float64) float64 {
 	if v < 0 {
 		return -1
@@ -506,3 +566,47 @@ func geluDeriv(_, _ int, x float64) float64 {
 		(0.0535161*x3+0.398942*x)*
 			math.Pow(1.0/math.Cosh(0.0356774*x3+0.797885*x), 2) + 0.5
 }
+
+func logGamma(_, _ int, v float64) float64 {
+	lg, _ := math.Lgamma(v)
+	return lg
+}
+
+// digamma is computed by shifting v above 6 with the recurrence
+// ψ(x) = ψ(x+1) - 1/x, then applying the asymptotic expansion.
+// Non-positive values use the reflection formula.
+func digamma(_, _ int, v float64) float64 {
+	if v <= 0 {
+		if v == math.Floor(v) {
+			return math.NaN()
+		}
+		return digamma(0, 0, 1-v) - math.Pi/math.Tan(math.Pi*v)
+	}
+	result := 0.0
+	for ; v < 6; v++ {
+		result -= 1 / v
+	}
+	inv2 := 1 / (v * v)
+	return result + math.Log(v) - 0.5/v -
+		inv2*(1.0/12-inv2*(1.0/120-inv2*(1.0/252-inv2*(1.0/240-inv2/132))))
+}
+
+// trigamma is computed like digamma, with the recurrence
+// ψ1(x) = ψ1(x+1) + 1/x² and its own asymptotic expansion.
+func trigamma(_, _ int, v float64) float64 {
+	if v <= 0 {
+		if v == math.Floor(v) {
+			return math.NaN()
+		}
+		s := math.Pi / math.Sin(math.Pi*v)
+		return -trigamma(0, 0, 1-v) + s*s
+	}
+	result := 0.0
+	for ; v < 6; v++ {
+		result += 1 / (v * v)
+	}
+	inv := 1 / v
+	inv2 := inv * inv
+	return result + inv + inv2/2 +
+		inv*inv2*(1.0/6-inv2*(1.0/30-inv2*(1.0/42-inv2/30)))
+}
@@ -298,3 +298,41 @@ func testNewGELUForward[T float.DType](t *testing.T) {
 
 	assert.InDeltaSlice(t, []T{0.5, 0.579522, 0.507979, 0.420478, 0.492021, 1.082964, 1.0, -0.082964, 0.0}, x.Grad().Data(), 1.0e-6)
 }
+
+func TestLogGamma_Forward(t *testing.T) {
+	t.Run("float32", testLogGammaForward[float32])
+	t.Run("float64", testLogGammaForward[float64])
+}
+
+func testLogGammaForward[T float.DType](t *testing.T) {
+	x := mat.NewDense[T](mat.WithBacking([]T{0.5, 1.0, 2.5, 10.0}), mat.WithGrad(true))
+	f := NewLogGamma(x)
+	y, err := f.Forward()
+	assert.Nil(t, err)
+
+	assert.InDeltaSlice(t, []T{0.5723649, 0, 0.2846829, 12.8018275}, y.Data(), 1.0e-5)
+
+	err = f.Backward(mat.NewDense[T](mat.WithBacking([]T{1.0, 0.5, -1.0, 2.0})))
+	assert.Nil(t, err)
+
+	assert.InDeltaSlice(t, []T{-1.9635100, -0.2886078, -0.7031566, 4.5035052}, x.Grad().Data(), 1.0e-5)
+}
+
+func TestDigamma_Forward(t *testing.T) {
+	t.Run("float32", testDigammaForward[float32])
+	t.Run("float64", testDigammaForward[float64])
+}
+
+func testDigammaForward[T float.DType](t *testing.T) {
+	x := mat.NewDense[T](mat.WithBacking([]T{-0.5, 0.5, 1.0, 10.0}), mat.WithGrad(true))
+	f := NewDigamma(x)
+	y, err := f.Forward()
+	assert.Nil(t, err)
+
+	assert.InDeltaSlice(t, []T{0.0364899, -1.9635100, -0.5772157, 2.2517526}, y.Data(), 1.0e-5)
+
+	err = f.Backward(mat.NewDense[T](mat.WithBacking([]T{1.0, 1.0, 1.0, 1.0})))
+	assert.Nil(t, err)
+
+	assert.InDeltaSlice(t, []T{8.9348022, 4.9348022, 1.6449341, 0.1051663}, x.Grad().Data(), 1.0e-5)
+}
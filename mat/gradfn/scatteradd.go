@@ -0,0 +1,64 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// ScatterAdd is a function returning a copy of the input matrix x, adding
+// the values of src along an axis, at the positions given by an index
+// with the shape of src:
+//
+//	y[index[i][j]][j] += src[i][j]  // axis 0
+//	y[i][index[i][j]] += src[i][j]  // axis 1
+//
+// Values are accumulated on repeated indices.
+type ScatterAdd[O mat.Tensor] struct {
+	x     O
+	src   O
+	axis  int
+	index [][]int
+}
+
+// NewScatterAdd returns a new ScatterAdd Function.
+func NewScatterAdd[O mat.Tensor](x O, axis int, index [][]int, src O) *ScatterAdd[O] {
+	if axis != 0 && axis != 1 {
+		panic("fn: invalid axis")
+	}
+	return &ScatterAdd[O]{
+		x:     x,
+		src:   src,
+		axis:  axis,
+		index: index,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *ScatterAdd[O]) Operands() []mat.Tensor {
+	return []mat.Tensor{r.x, r.src}
+}
+
+// Forward computes the output of the function.
+func (r *ScatterAdd[O]) Forward() (mat.Tensor, error) {
+	y := r.x.Value().(mat.Matrix).Clone()
+	return y.ScatterAddInPlace(r.axis, r.index, r.src.Value().(mat.Matrix)), nil
+}
+
+// Backward computes the backward pass.
+func (r *ScatterAdd[O]) Backward(gy mat.Tensor) error {
+	if !mat.SameDims(r.x.Value(), gy) {
+		return fmt.Errorf("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		r.x.AccGrad(gy)
+	}
+	if r.src.RequiresGrad() {
+		r.src.AccGrad(gy.(mat.Matrix).Gather(r.axis, r.index))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScatterAdd_Forward(t *testing.T) {
+	t.Run("float32", testScatterAddForward[float32])
+	t.Run("float64", testScatterAddForward[float64])
+}
+
+func testScatterAddForward[T float.DType](t *testing.T) {
+	x := mat.NewDense[T](mat.WithShape(2, 3), mat.WithBacking([]T{
+		0.1, 0.2, 0.3,
+		0.4, 0.5, 0.6,
+	}), mat.WithGrad(true))
+	src := mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{
+		1, 2,
+		3, 4,
+	}), mat.WithGrad(true))
+
+	f := NewScatterAdd[mat.Tensor](x, 1, [][]int{{0, 0}, {2, 1}}, src)
+	assert.Equal(t, []mat.Tensor{x, src}, f.Operands())
+
+	y, err := f.Forward()
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []T{
+		3.1, 0.2, 0.3,
+		0.4, 4.5, 3.6,
+	}, y.Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{
+		0.1, 0.2, 0.3,
+		0.4, 0.5, 0.6,
+	}, x.Data(), 1.0e-6)
+
+	err = f.Backward(mat.NewDense[T](mat.WithShape(2, 3), mat.WithBacking([]T{
+		1, 2, 3,
+		4, 5, 6,
+	})))
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []T{
+		1, 2, 3,
+		4, 5, 6,
+	}, x.Grad().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{
+		1, 1,
+		6, 5,
+	}, src.Grad().Data(), 1.0e-6)
+}
@@ -6,6 +6,7 @@ package gradfn
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/nlpodyssey/spago/mat"
 )
@@ -39,11 +40,14 @@ func (l *Sigmoid[O]) Backward(gy mat.Tensor) error {
 		return fmt.Errorf("fn: matrices have incompatible dimensions")
 	}
 	if l.x.RequiresGrad() {
-		gx := l.x.Value().(mat.Matrix).Sigmoid().Apply(func(_, _ int, v float64) float64 {
-			return v * (1.0 - v) // derivative of the sigmoid function
-		})
+		gx := sigmoidDerivKernel.apply(l.x.Value().(mat.Matrix), sigmoidDeriv)
 		gx.ProdInPlace(gy.(mat.Matrix))
 		l.x.AccGrad(gx)
 	}
 	return nil
 }
+
+func sigmoidDeriv(_, _ int, v float64) float64 {
+	s := 1 / (1 + math.Exp(-v))
+	return s * (1 - s)
+}
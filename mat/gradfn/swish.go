@@ -30,9 +30,7 @@ func (l *Swish[O]) Operands() []mat.Tensor {
 
 // Forward computes the output of the function.
 func (l *Swish[O]) Forward() (mat.Tensor, error) {
-	x := l.x.Value().(mat.Matrix)
-	s := x.Sigmoid()
-	return s.ProdInPlace(x), nil
+	return siluKernel.apply(l.x.Value().(mat.Matrix), silu), nil
 }
 
 // Backward computes the backward pass.
@@ -41,7 +39,7 @@ func (l *Swish[O]) Backward(gy mat.Tensor) error {
 		return fmt.Errorf("fn: matrices have incompatible dimensions")
 	}
 	if l.x.RequiresGrad() {
-		gx := l.x.Value().(mat.Matrix).Apply(swishDeriv)
+		gx := siluDerivKernel.apply(l.x.Value().(mat.Matrix), swishDeriv)
 		gx.ProdInPlace(gy.(mat.Matrix))
 		l.x.AccGrad(gx)
 	}
@@ -53,3 +51,7 @@ func swishDeriv(_, _ int, v float64) float64 {
 	expPlusOne := exp + 1
 	return exp * (expPlusOne + v) / (expPlusOne * expPlusOne)
 }
+
+func silu(_, _ int, v float64) float64 {
+	return v / (1 + math.Exp(-v))
+}
@@ -12,9 +12,11 @@ import (
 
 // UnaryElementwise is a single-input element-wise function.
 type UnaryElementwise[O mat.Tensor] struct {
-	x  O
-	f  func(i, j int, v float64) float64 // function
-	df func(i, j int, v float64) float64 // derivative
+	x   O
+	f   func(i, j int, v float64) float64 // function
+	df  func(i, j int, v float64) float64 // derivative
+	fk  kernel                            // optional typed kernel of the function
+	dfk kernel                            // optional typed kernel of the derivative
 }
 
 // Operands returns the list of operands.
@@ -24,7 +26,7 @@ func (r *UnaryElementwise[O]) Operands() []mat.Tensor {
 
 // Forward computes the output of this node.
 func (r *UnaryElementwise[O]) Forward() (mat.Tensor, error) {
-	return r.x.Value().(mat.Matrix).Apply(r.f), nil
+	return r.fk.apply(r.x.Value().(mat.Matrix), r.f), nil
 }
 
 // Backward computes the backward pass.
@@ -33,7 +35,7 @@ func (r *UnaryElementwise[O]) Backward(gy mat.Tensor) error {
 		return fmt.Errorf("fn: matrices have incompatible dimensions")
 	}
 	if r.x.RequiresGrad() {
-		gx := r.x.Value().(mat.Matrix).Apply(r.df)
+		gx := r.dfk.apply(r.x.Value().(mat.Matrix), r.df)
 		gx.ProdInPlace(gy.(mat.Matrix))
 		r.x.AccGrad(gx)
 	}
@@ -0,0 +1,61 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Where is a Function selecting the values of x1 where the condition is
+// non-zero, and the values of x2 elsewhere.
+// The condition is a constant mask: it does not receive gradients.
+type Where[O mat.Tensor] struct {
+	cond mat.Tensor
+	x1   O
+	x2   O
+}
+
+// NewWhere returns a new Where Function.
+func NewWhere[O mat.Tensor](cond mat.Tensor, x1 O, x2 O) *Where[O] {
+	return &Where[O]{
+		cond: cond,
+		x1:   x1,
+		x2:   x2,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Where[O]) Operands() []mat.Tensor {
+	return []mat.Tensor{r.x1, r.x2}
+}
+
+// Forward computes the output of the function.
+func (r *Where[O]) Forward() (mat.Tensor, error) {
+	cond := r.cond.Value().(mat.Matrix)
+	x1 := r.x1.Value().(mat.Matrix)
+	x2 := r.x2.Value().(mat.Matrix)
+	if !mat.SameDims(cond, x1) || !mat.SameDims(cond, x2) {
+		return nil, fmt.Errorf("fn: matrices have incompatible dimensions")
+	}
+	return cond.Where(x1, x2), nil
+}
+
+// Backward computes the backward pass.
+func (r *Where[O]) Backward(gy mat.Tensor) error {
+	cond := r.cond.Value().(mat.Matrix)
+	if !mat.SameDims(cond, gy) {
+		return fmt.Errorf("fn: matrices have incompatible dimensions")
+	}
+	g := gy.(mat.Matrix)
+	if r.x1.RequiresGrad() {
+		r.x1.AccGrad(cond.Where(g, g.ZerosLike()))
+	}
+	if r.x2.RequiresGrad() {
+		r.x2.AccGrad(cond.Where(g.ZerosLike(), g))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradfn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhere_Forward(t *testing.T) {
+	t.Run("float32", testWhereForward[float32])
+	t.Run("float64", testWhereForward[float64])
+}
+
+func testWhereForward[T float.DType](t *testing.T) {
+	cond := mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{
+		1, 0,
+		0, 1,
+	}))
+	x1 := mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{
+		0.1, 0.2,
+		0.3, 0.4,
+	}), mat.WithGrad(true))
+	x2 := mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{
+		-0.1, -0.2,
+		-0.3, -0.4,
+	}), mat.WithGrad(true))
+
+	f := NewWhere(cond, x1, x2)
+	assert.Equal(t, []mat.Tensor{x1, x2}, f.Operands())
+
+	y, err := f.Forward()
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []T{
+		0.1, -0.2,
+		-0.3, 0.4,
+	}, y.Data(), 1.0e-6)
+
+	err = f.Backward(mat.NewDense[T](mat.WithShape(2, 2), mat.WithBacking([]T{
+		1, 2,
+		3, 4,
+	})))
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []T{
+		1, 0,
+		0, 4,
+	}, x1.Grad().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{
+		0, 2,
+		3, 0,
+	}, x2.Grad().Data(), 1.0e-6)
+}
@@ -111,6 +111,7 @@ tailLoop:
 	JMP   tailLoop
 
 end:
+	VZEROUPPER
 	RET
 
 // func AddConstAVX64(c float64, x []float64, y []float64)
@@ -220,6 +221,7 @@ tailLoop:
 	JMP   tailLoop
 
 end:
+	VZEROUPPER
 	RET
 
 // func AddConstSSE32(c float32, x []float32, y []float32)
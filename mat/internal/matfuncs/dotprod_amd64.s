@@ -119,6 +119,7 @@ reduce:
 	VHADDPS      X0, X0, X0
 	VHADDPS      X0, X0, X0
 	MOVSS        X0, ret+48(FP)
+	VZEROUPPER
 	RET
 
 // func DotProdAVX64(x1 []float64, x2 []float64) float64
@@ -235,6 +236,7 @@ reduce:
 	VADDPD       X0, X2, X0
 	VHADDPD      X0, X0, X0
 	MOVSD        X0, ret+48(FP)
+	VZEROUPPER
 	RET
 
 // func DotProdSSE32(x1 []float32, x2 []float32) float32
@@ -42,5 +42,14 @@ func Exp32(x, y []float32) {
 
 // Exp64 computes the base-e exponential of each element of x, storing the result in y (64 bits).
 func Exp64(x, y []float64) {
-	exp(x, y)
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	n := 0
+	if hasAVX2 && hasFMA {
+		n = len(x) &^ 3
+		ExpAVX64(x[:n], y[:n])
+	}
+	exp(x[n:], y[n:])
 }
@@ -90,6 +90,7 @@ TEXT ·ExpAVX32(SB), NOSPLIT, $0-48
 	VPADDD       Y2, Y1, Y1
 	VMULPS       Y1, Y0, Y0
 	VMOVUPS      Y0, (CX)
+	VZEROUPPER
 	RET
 
 DATA SSE_LCPI0_0<>+0(SB)/4, $0x42b0c0a5
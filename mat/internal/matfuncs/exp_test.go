@@ -14,7 +14,34 @@ func TestExp32(t *testing.T) {
 }
 
 func TestExp64(t *testing.T) {
-	testExp(t, Exp64, 1e-6)
+	testExp(t, Exp64, 1e-12)
+}
+
+func TestExp64_Range(t *testing.T) {
+	x := []float64{
+		-1000, -746, -745, -740, -708.5, -700, -100, -20, -1, -1e-10, 0,
+		1e-10, 0.3, 0.35, 1, 20, 100, 700, 709, 709.8, 710, 1000,
+		math.Inf(-1), math.Inf(1), math.NaN(),
+	}
+	y := make([]float64, len(x))
+	Exp64(x, y)
+	for i, v := range x {
+		expected := math.Exp(v)
+		switch {
+		case math.IsNaN(expected):
+			if !math.IsNaN(y[i]) {
+				t.Fatalf("exp(%G): expected NaN, actual %G", v, y[i])
+			}
+		case math.IsInf(expected, 0) || expected < 0x1p-1022:
+			if d := math.Abs(y[i] - expected); !(d <= 0x1p-1022) && y[i] != expected {
+				t.Fatalf("exp(%G): expected %G, actual %G", v, expected, y[i])
+			}
+		default:
+			if d := math.Abs(y[i]-expected) / expected; d > 1e-14 {
+				t.Fatalf("exp(%G): expected %G, actual %G (relative error %G)", v, expected, y[i], d)
+			}
+		}
+	}
 }
 
 func testExp[F Float](t *testing.T, fn func(x, y []F), eps float64) {
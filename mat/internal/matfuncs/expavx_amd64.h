@@ -0,0 +1,99 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Macros computing the base-e exponential of the elements of a YMM register,
+// shared by the AVX kernels of the activation functions. They require AVX2
+// and FMA, and use the constants of expConsts32 and expConsts64, defined in
+// expavx_amd64.s.
+//
+// The argument is reduced to r = x - n*ln(2), with n = round(x / ln(2)),
+// and exp(x) = 2^n * exp(r), exp(r) being approximated by a polynomial.
+// NaN values are propagated.
+
+#define EXP32_HI ·expConsts32+0(SB)
+#define EXP32_LO ·expConsts32+32(SB)
+#define EXP32_LOG2E ·expConsts32+64(SB)
+#define EXP32_NEG_LN2_HI ·expConsts32+96(SB)
+#define EXP32_LN2_LO ·expConsts32+128(SB)
+#define EXP32_P0 ·expConsts32+160(SB)
+#define EXP32_P1 ·expConsts32+192(SB)
+#define EXP32_P2 ·expConsts32+224(SB)
+#define EXP32_P3 ·expConsts32+256(SB)
+#define EXP32_P4 ·expConsts32+288(SB)
+#define EXP32_P5 ·expConsts32+320(SB)
+#define EXP32_ONE ·expConsts32+352(SB)
+#define EXP32_BIAS ·expConsts32+384(SB)
+
+// EXP_AVX32 replaces the 8 float32 values of x with their exponential,
+// using t0, t1 and t2 as temporaries. The result is clamped to
+// [2^-126, +Inf], which is enough for the activation functions.
+#define EXP_AVX32(x, t0, t1, t2) \
+	VMOVUPS     EXP32_HI, t0; \
+	VMINPS      x, t0, x; \
+	VMOVUPS     EXP32_LO, t0; \
+	VMAXPS      x, t0, x; \
+	VMULPS      EXP32_LOG2E, x, t0; \
+	VROUNDPS    $0, t0, t0; \
+	VFMADD231PS EXP32_NEG_LN2_HI, t0, x; \
+	VFMADD231PS EXP32_LN2_LO, t0, x; \
+	VMOVUPS     EXP32_P0, t1; \
+	VFMADD213PS EXP32_P1, x, t1; \
+	VFMADD213PS EXP32_P2, x, t1; \
+	VFMADD213PS EXP32_P3, x, t1; \
+	VFMADD213PS EXP32_P4, x, t1; \
+	VFMADD213PS EXP32_P5, x, t1; \
+	VMULPS      x, x, t2; \
+	VFMADD213PS x, t2, t1; \
+	VADDPS      EXP32_ONE, t1, t1; \
+	VCVTPS2DQ   t0, t0; \
+	VPADDD      EXP32_BIAS, t0, t0; \
+	VPSLLD      $23, t0, t0; \
+	VMULPS      t0, t1, x
+
+#define EXP64_HI ·expConsts64+0(SB)
+#define EXP64_LO ·expConsts64+32(SB)
+#define EXP64_LOG2E ·expConsts64+64(SB)
+#define EXP64_NEG_LN2_HI ·expConsts64+96(SB)
+#define EXP64_NEG_LN2_LO ·expConsts64+128(SB)
+#define EXP64_C(k) ·expConsts64+(160+32*(12-k))(SB)
+#define EXP64_BIAS ·expConsts64+576(SB)
+#define EXP64_ONE EXP64_C(0)
+
+// EXP_AVX64 replaces the 4 float64 values of x with their exponential,
+// using t0, t1 and t2 as temporaries; xt0 and xt2 must be the XMM halves
+// of t0 and t2. The scale 2^n is applied in two steps, so that both the
+// overflow to +Inf and the gradual underflow are handled.
+#define EXP_AVX64(x, t0, t1, t2, xt0, xt2) \
+	VMOVUPD     EXP64_HI, t0; \
+	VMINPD      x, t0, x; \
+	VMOVUPD     EXP64_LO, t0; \
+	VMAXPD      x, t0, x; \
+	VMULPD      EXP64_LOG2E, x, t0; \
+	VROUNDPD    $0, t0, t0; \
+	VFMADD231PD EXP64_NEG_LN2_HI, t0, x; \
+	VFMADD231PD EXP64_NEG_LN2_LO, t0, x; \
+	VMOVUPD     EXP64_C(12), t1; \
+	VFMADD213PD EXP64_C(11), x, t1; \
+	VFMADD213PD EXP64_C(10), x, t1; \
+	VFMADD213PD EXP64_C(9), x, t1; \
+	VFMADD213PD EXP64_C(8), x, t1; \
+	VFMADD213PD EXP64_C(7), x, t1; \
+	VFMADD213PD EXP64_C(6), x, t1; \
+	VFMADD213PD EXP64_C(5), x, t1; \
+	VFMADD213PD EXP64_C(4), x, t1; \
+	VFMADD213PD EXP64_C(3), x, t1; \
+	VFMADD213PD EXP64_C(2), x, t1; \
+	VFMADD213PD EXP64_C(1), x, t1; \
+	VFMADD213PD EXP64_C(0), x, t1; \
+	VCVTPD2DQY  t0, xt0; \
+	VPSRAD      $1, xt0, xt2; \
+	VPSUBD      xt2, xt0, xt0; \
+	VPMOVSXDQ   xt0, t0; \
+	VPMOVSXDQ   xt2, t2; \
+	VPADDQ      EXP64_BIAS, t0, t0; \
+	VPADDQ      EXP64_BIAS, t2, t2; \
+	VPSLLQ      $52, t0, t0; \
+	VPSLLQ      $52, t2, t2; \
+	VMULPD      t2, t1, t1; \
+	VMULPD      t0, t1, x
@@ -0,0 +1,241 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+#include "textflag.h"
+#include "expavx_amd64.h"
+
+// upper bound of x, 88.3762626647949
+DATA ·expConsts32+0(SB)/4, $0x42b0c0a5
+DATA ·expConsts32+4(SB)/4, $0x42b0c0a5
+DATA ·expConsts32+8(SB)/4, $0x42b0c0a5
+DATA ·expConsts32+12(SB)/4, $0x42b0c0a5
+DATA ·expConsts32+16(SB)/4, $0x42b0c0a5
+DATA ·expConsts32+20(SB)/4, $0x42b0c0a5
+DATA ·expConsts32+24(SB)/4, $0x42b0c0a5
+DATA ·expConsts32+28(SB)/4, $0x42b0c0a5
+// lower bound of x, ln(2^-126)
+DATA ·expConsts32+32(SB)/4, $0xc2aeac50
+DATA ·expConsts32+36(SB)/4, $0xc2aeac50
+DATA ·expConsts32+40(SB)/4, $0xc2aeac50
+DATA ·expConsts32+44(SB)/4, $0xc2aeac50
+DATA ·expConsts32+48(SB)/4, $0xc2aeac50
+DATA ·expConsts32+52(SB)/4, $0xc2aeac50
+DATA ·expConsts32+56(SB)/4, $0xc2aeac50
+DATA ·expConsts32+60(SB)/4, $0xc2aeac50
+// log2(e)
+DATA ·expConsts32+64(SB)/4, $0x3fb8aa3b
+DATA ·expConsts32+68(SB)/4, $0x3fb8aa3b
+DATA ·expConsts32+72(SB)/4, $0x3fb8aa3b
+DATA ·expConsts32+76(SB)/4, $0x3fb8aa3b
+DATA ·expConsts32+80(SB)/4, $0x3fb8aa3b
+DATA ·expConsts32+84(SB)/4, $0x3fb8aa3b
+DATA ·expConsts32+88(SB)/4, $0x3fb8aa3b
+DATA ·expConsts32+92(SB)/4, $0x3fb8aa3b
+// -ln(2), high part
+DATA ·expConsts32+96(SB)/4, $0xbf318000
+DATA ·expConsts32+100(SB)/4, $0xbf318000
+DATA ·expConsts32+104(SB)/4, $0xbf318000
+DATA ·expConsts32+108(SB)/4, $0xbf318000
+DATA ·expConsts32+112(SB)/4, $0xbf318000
+DATA ·expConsts32+116(SB)/4, $0xbf318000
+DATA ·expConsts32+120(SB)/4, $0xbf318000
+DATA ·expConsts32+124(SB)/4, $0xbf318000
+// ln(2), low part, to be added to the high part
+DATA ·expConsts32+128(SB)/4, $0x395e8083
+DATA ·expConsts32+132(SB)/4, $0x395e8083
+DATA ·expConsts32+136(SB)/4, $0x395e8083
+DATA ·expConsts32+140(SB)/4, $0x395e8083
+DATA ·expConsts32+144(SB)/4, $0x395e8083
+DATA ·expConsts32+148(SB)/4, $0x395e8083
+DATA ·expConsts32+152(SB)/4, $0x395e8083
+DATA ·expConsts32+156(SB)/4, $0x395e8083
+// polynomial coefficients
+DATA ·expConsts32+160(SB)/4, $0x39506967
+DATA ·expConsts32+164(SB)/4, $0x39506967
+DATA ·expConsts32+168(SB)/4, $0x39506967
+DATA ·expConsts32+172(SB)/4, $0x39506967
+DATA ·expConsts32+176(SB)/4, $0x39506967
+DATA ·expConsts32+180(SB)/4, $0x39506967
+DATA ·expConsts32+184(SB)/4, $0x39506967
+DATA ·expConsts32+188(SB)/4, $0x39506967
+DATA ·expConsts32+192(SB)/4, $0x3ab743ce
+DATA ·expConsts32+196(SB)/4, $0x3ab743ce
+DATA ·expConsts32+200(SB)/4, $0x3ab743ce
+DATA ·expConsts32+204(SB)/4, $0x3ab743ce
+DATA ·expConsts32+208(SB)/4, $0x3ab743ce
+DATA ·expConsts32+212(SB)/4, $0x3ab743ce
+DATA ·expConsts32+216(SB)/4, $0x3ab743ce
+DATA ·expConsts32+220(SB)/4, $0x3ab743ce
+DATA ·expConsts32+224(SB)/4, $0x3c088908
+DATA ·expConsts32+228(SB)/4, $0x3c088908
+DATA ·expConsts32+232(SB)/4, $0x3c088908
+DATA ·expConsts32+236(SB)/4, $0x3c088908
+DATA ·expConsts32+240(SB)/4, $0x3c088908
+DATA ·expConsts32+244(SB)/4, $0x3c088908
+DATA ·expConsts32+248(SB)/4, $0x3c088908
+DATA ·expConsts32+252(SB)/4, $0x3c088908
+DATA ·expConsts32+256(SB)/4, $0x3d2aa9c1
+DATA ·expConsts32+260(SB)/4, $0x3d2aa9c1
+DATA ·expConsts32+264(SB)/4, $0x3d2aa9c1
+DATA ·expConsts32+268(SB)/4, $0x3d2aa9c1
+DATA ·expConsts32+272(SB)/4, $0x3d2aa9c1
+DATA ·expConsts32+276(SB)/4, $0x3d2aa9c1
+DATA ·expConsts32+280(SB)/4, $0x3d2aa9c1
+DATA ·expConsts32+284(SB)/4, $0x3d2aa9c1
+DATA ·expConsts32+288(SB)/4, $0x3e2aaaaa
+DATA ·expConsts32+292(SB)/4, $0x3e2aaaaa
+DATA ·expConsts32+296(SB)/4, $0x3e2aaaaa
+DATA ·expConsts32+300(SB)/4, $0x3e2aaaaa
+DATA ·expConsts32+304(SB)/4, $0x3e2aaaaa
+DATA ·expConsts32+308(SB)/4, $0x3e2aaaaa
+DATA ·expConsts32+312(SB)/4, $0x3e2aaaaa
+DATA ·expConsts32+316(SB)/4, $0x3e2aaaaa
+DATA ·expConsts32+320(SB)/4, $0x3f000000
+DATA ·expConsts32+324(SB)/4, $0x3f000000
+DATA ·expConsts32+328(SB)/4, $0x3f000000
+DATA ·expConsts32+332(SB)/4, $0x3f000000
+DATA ·expConsts32+336(SB)/4, $0x3f000000
+DATA ·expConsts32+340(SB)/4, $0x3f000000
+DATA ·expConsts32+344(SB)/4, $0x3f000000
+DATA ·expConsts32+348(SB)/4, $0x3f000000
+// 1
+DATA ·expConsts32+352(SB)/4, $0x3f800000
+DATA ·expConsts32+356(SB)/4, $0x3f800000
+DATA ·expConsts32+360(SB)/4, $0x3f800000
+DATA ·expConsts32+364(SB)/4, $0x3f800000
+DATA ·expConsts32+368(SB)/4, $0x3f800000
+DATA ·expConsts32+372(SB)/4, $0x3f800000
+DATA ·expConsts32+376(SB)/4, $0x3f800000
+DATA ·expConsts32+380(SB)/4, $0x3f800000
+// exponent bias
+DATA ·expConsts32+384(SB)/4, $0x0000007f
+DATA ·expConsts32+388(SB)/4, $0x0000007f
+DATA ·expConsts32+392(SB)/4, $0x0000007f
+DATA ·expConsts32+396(SB)/4, $0x0000007f
+DATA ·expConsts32+400(SB)/4, $0x0000007f
+DATA ·expConsts32+404(SB)/4, $0x0000007f
+DATA ·expConsts32+408(SB)/4, $0x0000007f
+DATA ·expConsts32+412(SB)/4, $0x0000007f
+GLOBL ·expConsts32(SB), RODATA|NOPTR, $416
+
+// upper bound of x, above which the result is +Inf
+DATA ·expConsts64+0(SB)/8, $0x4086300000000000
+DATA ·expConsts64+8(SB)/8, $0x4086300000000000
+DATA ·expConsts64+16(SB)/8, $0x4086300000000000
+DATA ·expConsts64+24(SB)/8, $0x4086300000000000
+// lower bound of x, below which the result is 0
+DATA ·expConsts64+32(SB)/8, $0xc087500000000000
+DATA ·expConsts64+40(SB)/8, $0xc087500000000000
+DATA ·expConsts64+48(SB)/8, $0xc087500000000000
+DATA ·expConsts64+56(SB)/8, $0xc087500000000000
+// log2(e)
+DATA ·expConsts64+64(SB)/8, $0x3ff71547652b82fe
+DATA ·expConsts64+72(SB)/8, $0x3ff71547652b82fe
+DATA ·expConsts64+80(SB)/8, $0x3ff71547652b82fe
+DATA ·expConsts64+88(SB)/8, $0x3ff71547652b82fe
+// -ln(2), high part
+DATA ·expConsts64+96(SB)/8, $0xbfe62e42fee00000
+DATA ·expConsts64+104(SB)/8, $0xbfe62e42fee00000
+DATA ·expConsts64+112(SB)/8, $0xbfe62e42fee00000
+DATA ·expConsts64+120(SB)/8, $0xbfe62e42fee00000
+// -ln(2), low part
+DATA ·expConsts64+128(SB)/8, $0xbdea39ef35793c76
+DATA ·expConsts64+136(SB)/8, $0xbdea39ef35793c76
+DATA ·expConsts64+144(SB)/8, $0xbdea39ef35793c76
+DATA ·expConsts64+152(SB)/8, $0xbdea39ef35793c76
+// 1/12!
+DATA ·expConsts64+160(SB)/8, $0x3e21eed8eff8d898
+DATA ·expConsts64+168(SB)/8, $0x3e21eed8eff8d898
+DATA ·expConsts64+176(SB)/8, $0x3e21eed8eff8d898
+DATA ·expConsts64+184(SB)/8, $0x3e21eed8eff8d898
+// 1/11!
+DATA ·expConsts64+192(SB)/8, $0x3e5ae64567f544e4
+DATA ·expConsts64+200(SB)/8, $0x3e5ae64567f544e4
+DATA ·expConsts64+208(SB)/8, $0x3e5ae64567f544e4
+DATA ·expConsts64+216(SB)/8, $0x3e5ae64567f544e4
+// 1/10!
+DATA ·expConsts64+224(SB)/8, $0x3e927e4fb7789f5c
+DATA ·expConsts64+232(SB)/8, $0x3e927e4fb7789f5c
+DATA ·expConsts64+240(SB)/8, $0x3e927e4fb7789f5c
+DATA ·expConsts64+248(SB)/8, $0x3e927e4fb7789f5c
+// 1/9!
+DATA ·expConsts64+256(SB)/8, $0x3ec71de3a556c734
+DATA ·expConsts64+264(SB)/8, $0x3ec71de3a556c734
+DATA ·expConsts64+272(SB)/8, $0x3ec71de3a556c734
+DATA ·expConsts64+280(SB)/8, $0x3ec71de3a556c734
+// 1/8!
+DATA ·expConsts64+288(SB)/8, $0x3efa01a01a01a01a
+DATA ·expConsts64+296(SB)/8, $0x3efa01a01a01a01a
+DATA ·expConsts64+304(SB)/8, $0x3efa01a01a01a01a
+DATA ·expConsts64+312(SB)/8, $0x3efa01a01a01a01a
+// 1/7!
+DATA ·expConsts64+320(SB)/8, $0x3f2a01a01a01a01a
+DATA ·expConsts64+328(SB)/8, $0x3f2a01a01a01a01a
+DATA ·expConsts64+336(SB)/8, $0x3f2a01a01a01a01a
+DATA ·expConsts64+344(SB)/8, $0x3f2a01a01a01a01a
+// 1/6!
+DATA ·expConsts64+352(SB)/8, $0x3f56c16c16c16c17
+DATA ·expConsts64+360(SB)/8, $0x3f56c16c16c16c17
+DATA ·expConsts64+368(SB)/8, $0x3f56c16c16c16c17
+DATA ·expConsts64+376(SB)/8, $0x3f56c16c16c16c17
+// 1/5!
+DATA ·expConsts64+384(SB)/8, $0x3f81111111111111
+DATA ·expConsts64+392(SB)/8, $0x3f81111111111111
+DATA ·expConsts64+400(SB)/8, $0x3f81111111111111
+DATA ·expConsts64+408(SB)/8, $0x3f81111111111111
+// 1/4!
+DATA ·expConsts64+416(SB)/8, $0x3fa5555555555555
+DATA ·expConsts64+424(SB)/8, $0x3fa5555555555555
+DATA ·expConsts64+432(SB)/8, $0x3fa5555555555555
+DATA ·expConsts64+440(SB)/8, $0x3fa5555555555555
+// 1/3!
+DATA ·expConsts64+448(SB)/8, $0x3fc5555555555555
+DATA ·expConsts64+456(SB)/8, $0x3fc5555555555555
+DATA ·expConsts64+464(SB)/8, $0x3fc5555555555555
+DATA ·expConsts64+472(SB)/8, $0x3fc5555555555555
+// 1/2!
+DATA ·expConsts64+480(SB)/8, $0x3fe0000000000000
+DATA ·expConsts64+488(SB)/8, $0x3fe0000000000000
+DATA ·expConsts64+496(SB)/8, $0x3fe0000000000000
+DATA ·expConsts64+504(SB)/8, $0x3fe0000000000000
+// 1/1!
+DATA ·expConsts64+512(SB)/8, $0x3ff0000000000000
+DATA ·expConsts64+520(SB)/8, $0x3ff0000000000000
+DATA ·expConsts64+528(SB)/8, $0x3ff0000000000000
+DATA ·expConsts64+536(SB)/8, $0x3ff0000000000000
+// 1/0!
+DATA ·expConsts64+544(SB)/8, $0x3ff0000000000000
+DATA ·expConsts64+552(SB)/8, $0x3ff0000000000000
+DATA ·expConsts64+560(SB)/8, $0x3ff0000000000000
+DATA ·expConsts64+568(SB)/8, $0x3ff0000000000000
+// exponent bias
+DATA ·expConsts64+576(SB)/8, $0x00000000000003ff
+DATA ·expConsts64+584(SB)/8, $0x00000000000003ff
+DATA ·expConsts64+592(SB)/8, $0x00000000000003ff
+DATA ·expConsts64+600(SB)/8, $0x00000000000003ff
+GLOBL ·expConsts64(SB), RODATA|NOPTR, $608
+
+// func ExpAVX64(x []float64, y []float64)
+// Requires: AVX, AVX2, FMA3
+TEXT ·ExpAVX64(SB), NOSPLIT, $0-48
+	MOVQ x_base+0(FP), AX
+	MOVQ x_len+8(FP), DX
+	MOVQ y_base+24(FP), CX
+	SHRQ $2, DX
+	JZ   done
+
+loop:
+	VMOVUPD (AX), Y0
+	EXP_AVX64(Y0, Y1, Y2, Y3, X1, X3)
+	VMOVUPD Y0, (CX)
+	ADDQ    $32, AX
+	ADDQ    $32, CX
+	DECQ    DX
+	JNZ     loop
+
+done:
+	VZEROUPPER
+	RET
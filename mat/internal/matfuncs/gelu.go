@@ -0,0 +1,21 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matfuncs
+
+import "math"
+
+// geluC is the constant sqrt(2/pi) used by the tanh approximation of GELU.
+const geluC = 0.7978845608028654
+
+func gelu[F float32 | float64](x, y []F) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	for i, xv := range x {
+		v := float64(xv)
+		y[i] = F(0.5 * v * (1 + math.Tanh(geluC*(v+0.044715*v*v*v))))
+	}
+}
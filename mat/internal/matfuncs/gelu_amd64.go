@@ -0,0 +1,52 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+package matfuncs
+
+// GELU32 computes the GELU (tanh approximation) of each element of x, storing the result in y (32 bits).
+//
+// Since 0.5 * (1 + tanh(u)) equals 1 / (1 + exp(-2u)), it computes
+// x / (1 + exp(-2u)): with the GELUAVX32 kernel when AVX2 and FMA are
+// available, otherwise block by block with the Exp32, AddConst32 and Div32
+// functions.
+func GELU32(x, y []float32) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	if hasAVX2 && hasFMA {
+		n := len(x) &^ 7
+		GELUAVX32(x[:n], y[:n])
+		gelu(x[n:], y[n:])
+		return
+	}
+	var buf [blockSize]float32
+	for len(x) > 0 {
+		n := min(len(x), blockSize)
+		xb, tb := x[:n], buf[:n]
+		for i, v := range xb {
+			tb[i] = -2 * geluC * (v + 0.044715*v*v*v)
+		}
+		Exp32(tb, tb)
+		AddConst32(1, tb, tb)
+		Div32(xb, tb, y[:n])
+		x, y = x[n:], y[n:]
+	}
+}
+
+// GELU64 computes the GELU (tanh approximation) of each element of x, storing the result in y (64 bits).
+func GELU64(x, y []float64) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	n := 0
+	if hasAVX2 && hasFMA {
+		n = len(x) &^ 3
+		GELUAVX64(x[:n], y[:n])
+	}
+	gelu(x[n:], y[n:])
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+#include "textflag.h"
+#include "expavx_amd64.h"
+
+// 0.044715
+DATA geluConsts32<>+0(SB)/4, $0x3d372713
+DATA geluConsts32<>+4(SB)/4, $0x3d372713
+DATA geluConsts32<>+8(SB)/4, $0x3d372713
+DATA geluConsts32<>+12(SB)/4, $0x3d372713
+DATA geluConsts32<>+16(SB)/4, $0x3d372713
+DATA geluConsts32<>+20(SB)/4, $0x3d372713
+DATA geluConsts32<>+24(SB)/4, $0x3d372713
+DATA geluConsts32<>+28(SB)/4, $0x3d372713
+// -2 * sqrt(2 / pi)
+DATA geluConsts32<>+32(SB)/4, $0xbfcc422a
+DATA geluConsts32<>+36(SB)/4, $0xbfcc422a
+DATA geluConsts32<>+40(SB)/4, $0xbfcc422a
+DATA geluConsts32<>+44(SB)/4, $0xbfcc422a
+DATA geluConsts32<>+48(SB)/4, $0xbfcc422a
+DATA geluConsts32<>+52(SB)/4, $0xbfcc422a
+DATA geluConsts32<>+56(SB)/4, $0xbfcc422a
+DATA geluConsts32<>+60(SB)/4, $0xbfcc422a
+GLOBL geluConsts32<>(SB), RODATA|NOPTR, $64
+
+// 0.044715
+DATA geluConsts64<>+0(SB)/8, $0x3fa6e4e26d4801f7
+DATA geluConsts64<>+8(SB)/8, $0x3fa6e4e26d4801f7
+DATA geluConsts64<>+16(SB)/8, $0x3fa6e4e26d4801f7
+DATA geluConsts64<>+24(SB)/8, $0x3fa6e4e26d4801f7
+// -2 * sqrt(2 / pi)
+DATA geluConsts64<>+32(SB)/8, $0xbff9884533d43651
+DATA geluConsts64<>+40(SB)/8, $0xbff9884533d43651
+DATA geluConsts64<>+48(SB)/8, $0xbff9884533d43651
+DATA geluConsts64<>+56(SB)/8, $0xbff9884533d43651
+GLOBL geluConsts64<>(SB), RODATA|NOPTR, $64
+
+// func GELUAVX32(x []float32, y []float32)
+// Requires: AVX, AVX2, FMA3
+TEXT ·GELUAVX32(SB), NOSPLIT, $0-48
+	MOVQ x_base+0(FP), AX
+	MOVQ x_len+8(FP), DX
+	MOVQ y_base+24(FP), CX
+	SHRQ $3, DX
+	JZ   done
+
+loop:
+	VMOVUPS (AX), Y4
+	VMULPS  Y4, Y4, Y0
+	VMULPS  geluConsts32<>+0(SB), Y0, Y0
+	VADDPS  EXP32_ONE, Y0, Y0
+	VMULPS  Y4, Y0, Y0
+	VMULPS  geluConsts32<>+32(SB), Y0, Y0
+	EXP_AVX32(Y0, Y1, Y2, Y3)
+	VADDPS  EXP32_ONE, Y0, Y0
+	VDIVPS  Y0, Y4, Y0
+	VMOVUPS Y0, (CX)
+	ADDQ    $32, AX
+	ADDQ    $32, CX
+	DECQ    DX
+	JNZ     loop
+
+done:
+	VZEROUPPER
+	RET
+
+// func GELUAVX64(x []float64, y []float64)
+// Requires: AVX, AVX2, FMA3
+TEXT ·GELUAVX64(SB), NOSPLIT, $0-48
+	MOVQ x_base+0(FP), AX
+	MOVQ x_len+8(FP), DX
+	MOVQ y_base+24(FP), CX
+	SHRQ $2, DX
+	JZ   done
+
+loop:
+	VMOVUPD (AX), Y4
+	VMULPD  Y4, Y4, Y0
+	VMULPD  geluConsts64<>+0(SB), Y0, Y0
+	VADDPD  EXP64_ONE, Y0, Y0
+	VMULPD  Y4, Y0, Y0
+	VMULPD  geluConsts64<>+32(SB), Y0, Y0
+	EXP_AVX64(Y0, Y1, Y2, Y3, X1, X3)
+	VADDPD  EXP64_ONE, Y0, Y0
+	VDIVPD  Y0, Y4, Y0
+	VMOVUPD Y0, (CX)
+	ADDQ    $32, AX
+	ADDQ    $32, CX
+	DECQ    DX
+	JNZ     loop
+
+done:
+	VZEROUPPER
+	RET
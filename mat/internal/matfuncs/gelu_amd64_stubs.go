@@ -0,0 +1,19 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+package matfuncs
+
+// GELUAVX32 computes the GELU (tanh approximation) of each element of x, storing the result in y (32 bits, AVX2 and FMA required).
+// It processes the elements up to the largest multiple of 8 not greater than len(x).
+//
+//go:noescape
+func GELUAVX32(x []float32, y []float32)
+
+// GELUAVX64 computes the GELU (tanh approximation) of each element of x, storing the result in y (64 bits, AVX2 and FMA required).
+// It processes the elements up to the largest multiple of 4 not greater than len(x).
+//
+//go:noescape
+func GELUAVX64(x []float64, y []float64)
@@ -0,0 +1,17 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !amd64 || !gc || purego
+
+package matfuncs
+
+// GELU32 computes the GELU (tanh approximation) of each element of x, storing the result in y (32 bits).
+func GELU32(x, y []float32) {
+	gelu(x, y)
+}
+
+// GELU64 computes the GELU (tanh approximation) of each element of x, storing the result in y (64 bits).
+func GELU64(x, y []float64) {
+	gelu(x, y)
+}
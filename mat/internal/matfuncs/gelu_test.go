@@ -0,0 +1,33 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matfuncs
+
+import "testing"
+
+func TestGELU32(t *testing.T) {
+	testUnaryFunc(t, GELU32, gelu[float32], 1e-6)
+}
+
+func TestGELU64(t *testing.T) {
+	testUnaryFunc(t, GELU64, gelu[float64], 1e-12)
+}
+
+func BenchmarkGELU32(b *testing.B) {
+	benchmarkUnaryFunc(b, GELU32)
+}
+
+func BenchmarkGELU64(b *testing.B) {
+	benchmarkUnaryFunc(b, GELU64)
+}
+
+// BenchmarkGELU32Generic and BenchmarkGELU64Generic measure the scalar
+// implementations, for comparison with the AVX kernels.
+func BenchmarkGELU32Generic(b *testing.B) {
+	benchmarkUnaryFunc(b, gelu[float32])
+}
+
+func BenchmarkGELU64Generic(b *testing.B) {
+	benchmarkUnaryFunc(b, gelu[float64])
+}
@@ -13,3 +13,20 @@ var (
 	hasAVX2 = cpu.X86.HasAVX2
 	hasFMA  = cpu.X86.HasFMA
 )
+
+// blockSize is the number of elements processed at a time by the functions
+// composed of several AVX kernels, so that intermediate results stay
+// in the L1 cache between passes.
+const blockSize = 512
+
+var (
+	ones32 [blockSize]float32
+	twos32 [blockSize]float32
+)
+
+func init() {
+	for i := range ones32 {
+		ones32[i] = 1
+		twos32[i] = 2
+	}
+}
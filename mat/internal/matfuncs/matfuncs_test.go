@@ -67,3 +67,59 @@ func RequireValueInDelta[F Float](t *testing.T, expected, actual F, eps float64,
 		t.Fatalf("expected %G ± %G, actual %G\n%s", expected, eps, actual, fmt.Sprint(msg...))
 	}
 }
+
+// testUnaryFunc checks fn against the expected function over vectors of
+// many sizes and alignments, and over large-magnitude values.
+func testUnaryFunc[F Float](t *testing.T, fn, expectedFn func(x, y []F), eps float64) {
+	t.Parallel()
+
+	x := make([]F, 0, 2_000)
+	expected := make([]F, 0, 2_000)
+	actual := make([]F, 0, 2_000)
+
+	for size := 0; size < 2_000; size++ {
+		x = x[:size]
+		expected = expected[:size]
+		actual = actual[:size]
+		RandVec(x)
+		expectedFn(x, expected)
+
+		fn(x, actual)
+
+		RequireSlicesInDelta(t, expected, actual, eps)
+	}
+
+	// Try different alignments
+	x = x[:16]
+	expected = expected[:16]
+	actual = actual[:16]
+	for offset := range x {
+		expectedFn(x[offset:], expected[offset:])
+		fn(x[offset:], actual[offset:])
+		RequireSlicesInDelta(t, expected[offset:], actual[offset:], eps)
+	}
+
+	// Saturation
+	x = []F{-1000, -100, -20, -1e-4, 0, 1e-4, 20, 100, 1000}
+	expected = make([]F, len(x))
+	actual = make([]F, len(x))
+	expectedFn(x, expected)
+	fn(x, actual)
+	RequireSlicesInDelta(t, expected, actual, eps)
+
+	// In place
+	copy(actual, x)
+	fn(actual, actual)
+	RequireSlicesInDelta(t, expected, actual, eps)
+}
+
+func benchmarkUnaryFunc[F Float](b *testing.B, fn func(x, y []F)) {
+	size := 100_000
+	x := NewRandVec[F](size)
+	y := make([]F, size)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn(x, y)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matfuncs
+
+import "math"
+
+func sigmoid[F float32 | float64](x, y []F) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	for i, xv := range x {
+		y[i] = F(1 / (1 + math.Exp(-float64(xv))))
+	}
+}
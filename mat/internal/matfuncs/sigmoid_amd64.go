@@ -0,0 +1,48 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+package matfuncs
+
+// Sigmoid32 computes the logistic sigmoid of each element of x, storing the result in y (32 bits).
+//
+// It uses the SigmoidAVX32 kernel when AVX2 and FMA are available.
+// Otherwise, it computes 1 / (1 + exp(-x)) block by block with the Exp32,
+// AddConst32, MulConst32 and Div32 functions.
+func Sigmoid32(x, y []float32) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	if hasAVX2 && hasFMA {
+		n := len(x) &^ 7
+		SigmoidAVX32(x[:n], y[:n])
+		sigmoid(x[n:], y[n:])
+		return
+	}
+	for len(x) > 0 {
+		n := min(len(x), blockSize)
+		xb, yb := x[:n], y[:n]
+		MulConst32(-1, xb, yb)
+		Exp32(yb, yb)
+		AddConst32(1, yb, yb)
+		Div32(ones32[:n], yb, yb)
+		x, y = x[n:], y[n:]
+	}
+}
+
+// Sigmoid64 computes the logistic sigmoid of each element of x, storing the result in y (64 bits).
+func Sigmoid64(x, y []float64) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	n := 0
+	if hasAVX2 && hasFMA {
+		n = len(x) &^ 3
+		SigmoidAVX64(x[:n], y[:n])
+	}
+	sigmoid(x[n:], y[n:])
+}
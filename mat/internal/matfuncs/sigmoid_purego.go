@@ -0,0 +1,17 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !amd64 || !gc || purego
+
+package matfuncs
+
+// Sigmoid32 computes the logistic sigmoid of each element of x, storing the result in y (32 bits).
+func Sigmoid32(x, y []float32) {
+	sigmoid(x, y)
+}
+
+// Sigmoid64 computes the logistic sigmoid of each element of x, storing the result in y (64 bits).
+func Sigmoid64(x, y []float64) {
+	sigmoid(x, y)
+}
@@ -0,0 +1,33 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matfuncs
+
+import "testing"
+
+func TestSigmoid32(t *testing.T) {
+	testUnaryFunc(t, Sigmoid32, sigmoid[float32], 1e-6)
+}
+
+func TestSigmoid64(t *testing.T) {
+	testUnaryFunc(t, Sigmoid64, sigmoid[float64], 1e-12)
+}
+
+func BenchmarkSigmoid32(b *testing.B) {
+	benchmarkUnaryFunc(b, Sigmoid32)
+}
+
+func BenchmarkSigmoid64(b *testing.B) {
+	benchmarkUnaryFunc(b, Sigmoid64)
+}
+
+// BenchmarkSigmoid32Generic and BenchmarkSigmoid64Generic measure the scalar
+// implementations, for comparison with the AVX kernels.
+func BenchmarkSigmoid32Generic(b *testing.B) {
+	benchmarkUnaryFunc(b, sigmoid[float32])
+}
+
+func BenchmarkSigmoid64Generic(b *testing.B) {
+	benchmarkUnaryFunc(b, sigmoid[float64])
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+package matfuncs
+
+// SiLU32 computes the SiLU (x * sigmoid(x)) of each element of x, storing the result in y (32 bits).
+//
+// It uses the SiLUAVX32 kernel when AVX2 and FMA are available.
+// Otherwise, it computes x / (1 + exp(-x)) block by block with the Exp32,
+// AddConst32, MulConst32 and Div32 functions.
+func SiLU32(x, y []float32) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	if hasAVX2 && hasFMA {
+		n := len(x) &^ 7
+		SiLUAVX32(x[:n], y[:n])
+		silu(x[n:], y[n:])
+		return
+	}
+	var buf [blockSize]float32
+	for len(x) > 0 {
+		n := min(len(x), blockSize)
+		xb, tb := x[:n], buf[:n]
+		MulConst32(-1, xb, tb)
+		Exp32(tb, tb)
+		AddConst32(1, tb, tb)
+		Div32(xb, tb, y[:n])
+		x, y = x[n:], y[n:]
+	}
+}
+
+// SiLU64 computes the SiLU (x * sigmoid(x)) of each element of x, storing the result in y (64 bits).
+func SiLU64(x, y []float64) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	n := 0
+	if hasAVX2 && hasFMA {
+		n = len(x) &^ 3
+		SiLUAVX64(x[:n], y[:n])
+	}
+	silu(x[n:], y[n:])
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+#include "textflag.h"
+#include "expavx_amd64.h"
+
+// sign mask
+DATA siluConsts32<>+0(SB)/4, $0x80000000
+DATA siluConsts32<>+4(SB)/4, $0x80000000
+DATA siluConsts32<>+8(SB)/4, $0x80000000
+DATA siluConsts32<>+12(SB)/4, $0x80000000
+DATA siluConsts32<>+16(SB)/4, $0x80000000
+DATA siluConsts32<>+20(SB)/4, $0x80000000
+DATA siluConsts32<>+24(SB)/4, $0x80000000
+DATA siluConsts32<>+28(SB)/4, $0x80000000
+GLOBL siluConsts32<>(SB), RODATA|NOPTR, $32
+
+// sign mask
+DATA siluConsts64<>+0(SB)/8, $0x8000000000000000
+DATA siluConsts64<>+8(SB)/8, $0x8000000000000000
+DATA siluConsts64<>+16(SB)/8, $0x8000000000000000
+DATA siluConsts64<>+24(SB)/8, $0x8000000000000000
+GLOBL siluConsts64<>(SB), RODATA|NOPTR, $32
+
+// func SiLUAVX32(x []float32, y []float32)
+// Requires: AVX, AVX2, FMA3
+TEXT ·SiLUAVX32(SB), NOSPLIT, $0-48
+	MOVQ x_base+0(FP), AX
+	MOVQ x_len+8(FP), DX
+	MOVQ y_base+24(FP), CX
+	SHRQ $3, DX
+	JZ   done
+
+loop:
+	VMOVUPS (AX), Y4
+	VXORPS  siluConsts32<>+0(SB), Y4, Y0
+	EXP_AVX32(Y0, Y1, Y2, Y3)
+	VADDPS  EXP32_ONE, Y0, Y0
+	VDIVPS  Y0, Y4, Y0
+	VMOVUPS Y0, (CX)
+	ADDQ    $32, AX
+	ADDQ    $32, CX
+	DECQ    DX
+	JNZ     loop
+
+done:
+	VZEROUPPER
+	RET
+
+// func SiLUAVX64(x []float64, y []float64)
+// Requires: AVX, AVX2, FMA3
+TEXT ·SiLUAVX64(SB), NOSPLIT, $0-48
+	MOVQ x_base+0(FP), AX
+	MOVQ x_len+8(FP), DX
+	MOVQ y_base+24(FP), CX
+	SHRQ $2, DX
+	JZ   done
+
+loop:
+	VMOVUPD (AX), Y4
+	VXORPD  siluConsts64<>+0(SB), Y4, Y0
+	EXP_AVX64(Y0, Y1, Y2, Y3, X1, X3)
+	VADDPD  EXP64_ONE, Y0, Y0
+	VDIVPD  Y0, Y4, Y0
+	VMOVUPD Y0, (CX)
+	ADDQ    $32, AX
+	ADDQ    $32, CX
+	DECQ    DX
+	JNZ     loop
+
+done:
+	VZEROUPPER
+	RET
@@ -0,0 +1,19 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+package matfuncs
+
+// SiLUAVX32 computes the SiLU (x add_amd64.go add_amd64.s add_amd64_stubs.go add_purego.go add_test.go addconst_amd64.go addconst_amd64.s addconst_amd64_stubs.go addconst_purego.go addconst_test.go cpu div_amd64.go div_amd64.s div_amd64_stubs.go div_purego.go div_test.go dotprod_amd64.go dotprod_amd64.s dotprod_amd64_stubs.go dotprod_purego.go dotprod_test.go exp.go exp_amd64.go exp_amd64.s exp_amd64_stubs.go exp_purego.go exp_test.go expavx_amd64.h expavx_amd64.s expavx_amd64_stubs.go gelu.go gelu_amd64.go gelu_amd64.s gelu_purego.go gelu_test.go log.go log_amd64.go log_amd64.s log_amd64_stubs.go log_purego.go log_test.go matfuncs_amd64.go matfuncs_test.go mulconst_amd64.go mulconst_amd64.s mulconst_amd64_stubs.go mulconst_purego.go mulconst_test.go sigmoid.go sigmoid_amd64.go sigmoid_amd64.s sigmoid_amd64_stubs.go sigmoid_purego.go sigmoid_test.go silu.go silu_amd64.go silu_amd64.s silu_purego.go silu_test.go sub_amd64.go sub_amd64.s sub_amd64_stubs.go sub_purego.go sub_test.go sum_amd64.go sum_amd64.s sum_amd64_stubs.go sum_purego.go sum_test.go tanh.go tanh_amd64.go tanh_amd64.s tanh_purego.go tanh_test.go sigmoid(x)) of each element of x, storing the result in y (32 bits, AVX2 and FMA required).
+// It processes the elements up to the largest multiple of 8 not greater than len(x).
+//
+//go:noescape
+func SiLUAVX32(x []float32, y []float32)
+
+// SiLUAVX64 computes the SiLU (x add_amd64.go add_amd64.s add_amd64_stubs.go add_purego.go add_test.go addconst_amd64.go addconst_amd64.s addconst_amd64_stubs.go addconst_purego.go addconst_test.go cpu div_amd64.go div_amd64.s div_amd64_stubs.go div_purego.go div_test.go dotprod_amd64.go dotprod_amd64.s dotprod_amd64_stubs.go dotprod_purego.go dotprod_test.go exp.go exp_amd64.go exp_amd64.s exp_amd64_stubs.go exp_purego.go exp_test.go expavx_amd64.h expavx_amd64.s expavx_amd64_stubs.go gelu.go gelu_amd64.go gelu_amd64.s gelu_purego.go gelu_test.go log.go log_amd64.go log_amd64.s log_amd64_stubs.go log_purego.go log_test.go matfuncs_amd64.go matfuncs_test.go mulconst_amd64.go mulconst_amd64.s mulconst_amd64_stubs.go mulconst_purego.go mulconst_test.go sigmoid.go sigmoid_amd64.go sigmoid_amd64.s sigmoid_amd64_stubs.go sigmoid_purego.go sigmoid_test.go silu.go silu_amd64.go silu_amd64.s silu_purego.go silu_test.go sub_amd64.go sub_amd64.s sub_amd64_stubs.go sub_purego.go sub_test.go sum_amd64.go sum_amd64.s sum_amd64_stubs.go sum_purego.go sum_test.go tanh.go tanh_amd64.go tanh_amd64.s tanh_purego.go tanh_test.go sigmoid(x)) of each element of x, storing the result in y (64 bits, AVX2 and FMA required).
+// It processes the elements up to the largest multiple of 4 not greater than len(x).
+//
+//go:noescape
+func SiLUAVX64(x []float64, y []float64)
@@ -0,0 +1,17 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !amd64 || !gc || purego
+
+package matfuncs
+
+// SiLU32 computes the SiLU (x * sigmoid(x)) of each element of x, storing the result in y (32 bits).
+func SiLU32(x, y []float32) {
+	silu(x, y)
+}
+
+// SiLU64 computes the SiLU (x * sigmoid(x)) of each element of x, storing the result in y (64 bits).
+func SiLU64(x, y []float64) {
+	silu(x, y)
+}
@@ -0,0 +1,33 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matfuncs
+
+import "testing"
+
+func TestSiLU32(t *testing.T) {
+	testUnaryFunc(t, SiLU32, silu[float32], 1e-6)
+}
+
+func TestSiLU64(t *testing.T) {
+	testUnaryFunc(t, SiLU64, silu[float64], 1e-12)
+}
+
+func BenchmarkSiLU32(b *testing.B) {
+	benchmarkUnaryFunc(b, SiLU32)
+}
+
+func BenchmarkSiLU64(b *testing.B) {
+	benchmarkUnaryFunc(b, SiLU64)
+}
+
+// BenchmarkSiLU32Generic and BenchmarkSiLU64Generic measure the scalar
+// implementations, for comparison with the AVX kernels.
+func BenchmarkSiLU32Generic(b *testing.B) {
+	benchmarkUnaryFunc(b, silu[float32])
+}
+
+func BenchmarkSiLU64Generic(b *testing.B) {
+	benchmarkUnaryFunc(b, silu[float64])
+}
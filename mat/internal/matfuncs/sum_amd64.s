@@ -86,6 +86,7 @@ reduce:
 	VHADDPS      X0, X0, X0
 	VHADDPS      X0, X0, X0
 	MOVSS        X0, ret+24(FP)
+	VZEROUPPER
 	RET
 
 // func SumAVX64(x []float64) float64
@@ -169,6 +170,7 @@ reduce:
 	VADDPD       X0, X2, X0
 	VHADDPD      X0, X0, X0
 	MOVSD        X0, ret+24(FP)
+	VZEROUPPER
 	RET
 
 // func SumSSE32(x []float32) float32
@@ -0,0 +1,80 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+package matfuncs
+
+// Tanh32 computes the hyperbolic tangent of each element of x, storing the result in y (32 bits).
+//
+// It computes 2 / (1 + exp(-2x)) - 1: with the TanhAVX32 kernel when AVX2
+// and FMA are available, otherwise block by block with the Exp32,
+// AddConst32, MulConst32 and Div32 functions. The final subtraction loses
+// relative precision near zero, so the elements smaller than tanhSmall in
+// magnitude are computed with the Taylor polynomial of tanhPoly.
+func Tanh32(x, y []float32) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	if hasAVX2 && hasFMA {
+		n := len(x) &^ 7
+		TanhAVX32(x[:n], y[:n])
+		tanh(x[n:], y[n:])
+		return
+	}
+	var buf [blockSize]float32
+	for len(x) > 0 {
+		n := min(len(x), blockSize)
+		xb, yb := buf[:n], y[:n]
+		copy(xb, x[:n]) // x and y may overlap
+		MulConst32(-2, xb, yb)
+		Exp32(yb, yb)
+		AddConst32(1, yb, yb)
+		Div32(twos32[:n], yb, yb)
+		AddConst32(-1, yb, yb)
+		for i, v := range xb {
+			if v > -tanhSmall && v < tanhSmall {
+				yb[i] = tanhPoly(v)
+			}
+		}
+		x, y = x[n:], y[n:]
+	}
+}
+
+// tanhSmall is the magnitude below which Tanh32 uses tanhPoly.
+const tanhSmall = 0.5
+
+// tanhPoly approximates tanh(x) with its Taylor polynomial of degree 13,
+// whose relative error is below 1e-7 for |x| < tanhSmall.
+func tanhPoly(x float32) float32 {
+	v := float64(x)
+	v2 := v * v
+	p := 21844.0 / 6081075
+	p = p*v2 - 1382.0/155925
+	p = p*v2 + 62.0/2835
+	p = p*v2 - 17.0/315
+	p = p*v2 + 2.0/15
+	p = p*v2 - 1.0/3
+	p = p*v2 + 1
+	return float32(v * p)
+}
+
+// Tanh64 computes the hyperbolic tangent of each element of x, storing the result in y (64 bits).
+//
+// When AVX2 and FMA are available, it uses the TanhAVX64 kernel, which
+// computes 2 / (1 + exp(-2x)) - 1 and the same Taylor polynomial as
+// tanhPoly below 0.1 in magnitude, where its relative error is below 1e-16.
+func Tanh64(x, y []float64) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	n := 0
+	if hasAVX2 && hasFMA {
+		n = len(x) &^ 3
+		TanhAVX64(x[:n], y[:n])
+	}
+	tanh(x[n:], y[n:])
+}
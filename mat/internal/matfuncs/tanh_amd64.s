@@ -0,0 +1,222 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+#include "textflag.h"
+#include "expavx_amd64.h"
+
+// sign mask
+DATA tanhConsts32<>+0(SB)/4, $0x80000000
+DATA tanhConsts32<>+4(SB)/4, $0x80000000
+DATA tanhConsts32<>+8(SB)/4, $0x80000000
+DATA tanhConsts32<>+12(SB)/4, $0x80000000
+DATA tanhConsts32<>+16(SB)/4, $0x80000000
+DATA tanhConsts32<>+20(SB)/4, $0x80000000
+DATA tanhConsts32<>+24(SB)/4, $0x80000000
+DATA tanhConsts32<>+28(SB)/4, $0x80000000
+// absolute value mask
+DATA tanhConsts32<>+32(SB)/4, $0x7fffffff
+DATA tanhConsts32<>+36(SB)/4, $0x7fffffff
+DATA tanhConsts32<>+40(SB)/4, $0x7fffffff
+DATA tanhConsts32<>+44(SB)/4, $0x7fffffff
+DATA tanhConsts32<>+48(SB)/4, $0x7fffffff
+DATA tanhConsts32<>+52(SB)/4, $0x7fffffff
+DATA tanhConsts32<>+56(SB)/4, $0x7fffffff
+DATA tanhConsts32<>+60(SB)/4, $0x7fffffff
+// 2
+DATA tanhConsts32<>+64(SB)/4, $0x40000000
+DATA tanhConsts32<>+68(SB)/4, $0x40000000
+DATA tanhConsts32<>+72(SB)/4, $0x40000000
+DATA tanhConsts32<>+76(SB)/4, $0x40000000
+DATA tanhConsts32<>+80(SB)/4, $0x40000000
+DATA tanhConsts32<>+84(SB)/4, $0x40000000
+DATA tanhConsts32<>+88(SB)/4, $0x40000000
+DATA tanhConsts32<>+92(SB)/4, $0x40000000
+// threshold of the polynomial, tanhSmall
+DATA tanhConsts32<>+96(SB)/4, $0x3f000000
+DATA tanhConsts32<>+100(SB)/4, $0x3f000000
+DATA tanhConsts32<>+104(SB)/4, $0x3f000000
+DATA tanhConsts32<>+108(SB)/4, $0x3f000000
+DATA tanhConsts32<>+112(SB)/4, $0x3f000000
+DATA tanhConsts32<>+116(SB)/4, $0x3f000000
+DATA tanhConsts32<>+120(SB)/4, $0x3f000000
+DATA tanhConsts32<>+124(SB)/4, $0x3f000000
+// Taylor coefficients of tanh(x) / x, in x^2
+DATA tanhConsts32<>+128(SB)/4, $0x3b6b69e8
+DATA tanhConsts32<>+132(SB)/4, $0x3b6b69e8
+DATA tanhConsts32<>+136(SB)/4, $0x3b6b69e8
+DATA tanhConsts32<>+140(SB)/4, $0x3b6b69e8
+DATA tanhConsts32<>+144(SB)/4, $0x3b6b69e8
+DATA tanhConsts32<>+148(SB)/4, $0x3b6b69e8
+DATA tanhConsts32<>+152(SB)/4, $0x3b6b69e8
+DATA tanhConsts32<>+156(SB)/4, $0x3b6b69e8
+DATA tanhConsts32<>+160(SB)/4, $0xbc11371b
+DATA tanhConsts32<>+164(SB)/4, $0xbc11371b
+DATA tanhConsts32<>+168(SB)/4, $0xbc11371b
+DATA tanhConsts32<>+172(SB)/4, $0xbc11371b
+DATA tanhConsts32<>+176(SB)/4, $0xbc11371b
+DATA tanhConsts32<>+180(SB)/4, $0xbc11371b
+DATA tanhConsts32<>+184(SB)/4, $0xbc11371b
+DATA tanhConsts32<>+188(SB)/4, $0xbc11371b
+DATA tanhConsts32<>+192(SB)/4, $0x3cb327a4
+DATA tanhConsts32<>+196(SB)/4, $0x3cb327a4
+DATA tanhConsts32<>+200(SB)/4, $0x3cb327a4
+DATA tanhConsts32<>+204(SB)/4, $0x3cb327a4
+DATA tanhConsts32<>+208(SB)/4, $0x3cb327a4
+DATA tanhConsts32<>+212(SB)/4, $0x3cb327a4
+DATA tanhConsts32<>+216(SB)/4, $0x3cb327a4
+DATA tanhConsts32<>+220(SB)/4, $0x3cb327a4
+DATA tanhConsts32<>+224(SB)/4, $0xbd5d0dd1
+DATA tanhConsts32<>+228(SB)/4, $0xbd5d0dd1
+DATA tanhConsts32<>+232(SB)/4, $0xbd5d0dd1
+DATA tanhConsts32<>+236(SB)/4, $0xbd5d0dd1
+DATA tanhConsts32<>+240(SB)/4, $0xbd5d0dd1
+DATA tanhConsts32<>+244(SB)/4, $0xbd5d0dd1
+DATA tanhConsts32<>+248(SB)/4, $0xbd5d0dd1
+DATA tanhConsts32<>+252(SB)/4, $0xbd5d0dd1
+DATA tanhConsts32<>+256(SB)/4, $0x3e088889
+DATA tanhConsts32<>+260(SB)/4, $0x3e088889
+DATA tanhConsts32<>+264(SB)/4, $0x3e088889
+DATA tanhConsts32<>+268(SB)/4, $0x3e088889
+DATA tanhConsts32<>+272(SB)/4, $0x3e088889
+DATA tanhConsts32<>+276(SB)/4, $0x3e088889
+DATA tanhConsts32<>+280(SB)/4, $0x3e088889
+DATA tanhConsts32<>+284(SB)/4, $0x3e088889
+DATA tanhConsts32<>+288(SB)/4, $0xbeaaaaab
+DATA tanhConsts32<>+292(SB)/4, $0xbeaaaaab
+DATA tanhConsts32<>+296(SB)/4, $0xbeaaaaab
+DATA tanhConsts32<>+300(SB)/4, $0xbeaaaaab
+DATA tanhConsts32<>+304(SB)/4, $0xbeaaaaab
+DATA tanhConsts32<>+308(SB)/4, $0xbeaaaaab
+DATA tanhConsts32<>+312(SB)/4, $0xbeaaaaab
+DATA tanhConsts32<>+316(SB)/4, $0xbeaaaaab
+GLOBL tanhConsts32<>(SB), RODATA|NOPTR, $320
+
+// sign mask
+DATA tanhConsts64<>+0(SB)/8, $0x8000000000000000
+DATA tanhConsts64<>+8(SB)/8, $0x8000000000000000
+DATA tanhConsts64<>+16(SB)/8, $0x8000000000000000
+DATA tanhConsts64<>+24(SB)/8, $0x8000000000000000
+// absolute value mask
+DATA tanhConsts64<>+32(SB)/8, $0x7fffffffffffffff
+DATA tanhConsts64<>+40(SB)/8, $0x7fffffffffffffff
+DATA tanhConsts64<>+48(SB)/8, $0x7fffffffffffffff
+DATA tanhConsts64<>+56(SB)/8, $0x7fffffffffffffff
+// 2
+DATA tanhConsts64<>+64(SB)/8, $0x4000000000000000
+DATA tanhConsts64<>+72(SB)/8, $0x4000000000000000
+DATA tanhConsts64<>+80(SB)/8, $0x4000000000000000
+DATA tanhConsts64<>+88(SB)/8, $0x4000000000000000
+// threshold of the polynomial
+DATA tanhConsts64<>+96(SB)/8, $0x3fb999999999999a
+DATA tanhConsts64<>+104(SB)/8, $0x3fb999999999999a
+DATA tanhConsts64<>+112(SB)/8, $0x3fb999999999999a
+DATA tanhConsts64<>+120(SB)/8, $0x3fb999999999999a
+// Taylor coefficients of tanh(x) / x, in x^2
+DATA tanhConsts64<>+128(SB)/8, $0x3f6d6d3d0e157de0
+DATA tanhConsts64<>+136(SB)/8, $0x3f6d6d3d0e157de0
+DATA tanhConsts64<>+144(SB)/8, $0x3f6d6d3d0e157de0
+DATA tanhConsts64<>+152(SB)/8, $0x3f6d6d3d0e157de0
+DATA tanhConsts64<>+160(SB)/8, $0xbf8226e355e6c23d
+DATA tanhConsts64<>+168(SB)/8, $0xbf8226e355e6c23d
+DATA tanhConsts64<>+176(SB)/8, $0xbf8226e355e6c23d
+DATA tanhConsts64<>+184(SB)/8, $0xbf8226e355e6c23d
+DATA tanhConsts64<>+192(SB)/8, $0x3f9664f4882c10fa
+DATA tanhConsts64<>+200(SB)/8, $0x3f9664f4882c10fa
+DATA tanhConsts64<>+208(SB)/8, $0x3f9664f4882c10fa
+DATA tanhConsts64<>+216(SB)/8, $0x3f9664f4882c10fa
+DATA tanhConsts64<>+224(SB)/8, $0xbfaba1ba1ba1ba1c
+DATA tanhConsts64<>+232(SB)/8, $0xbfaba1ba1ba1ba1c
+DATA tanhConsts64<>+240(SB)/8, $0xbfaba1ba1ba1ba1c
+DATA tanhConsts64<>+248(SB)/8, $0xbfaba1ba1ba1ba1c
+DATA tanhConsts64<>+256(SB)/8, $0x3fc1111111111111
+DATA tanhConsts64<>+264(SB)/8, $0x3fc1111111111111
+DATA tanhConsts64<>+272(SB)/8, $0x3fc1111111111111
+DATA tanhConsts64<>+280(SB)/8, $0x3fc1111111111111
+DATA tanhConsts64<>+288(SB)/8, $0xbfd5555555555555
+DATA tanhConsts64<>+296(SB)/8, $0xbfd5555555555555
+DATA tanhConsts64<>+304(SB)/8, $0xbfd5555555555555
+DATA tanhConsts64<>+312(SB)/8, $0xbfd5555555555555
+GLOBL tanhConsts64<>(SB), RODATA|NOPTR, $320
+
+// func TanhAVX32(x []float32, y []float32)
+// Requires: AVX, AVX2, FMA3
+TEXT ·TanhAVX32(SB), NOSPLIT, $0-48
+	MOVQ x_base+0(FP), AX
+	MOVQ x_len+8(FP), DX
+	MOVQ y_base+24(FP), CX
+	SHRQ $3, DX
+	JZ   done
+
+loop:
+	VMOVUPS     (AX), Y4
+	VADDPS      Y4, Y4, Y0
+	VXORPS      tanhConsts32<>+0(SB), Y0, Y0
+	EXP_AVX32(Y0, Y1, Y2, Y3)
+	VADDPS      EXP32_ONE, Y0, Y0
+	VMOVUPS     tanhConsts32<>+64(SB), Y1
+	VDIVPS      Y0, Y1, Y0
+	VSUBPS      EXP32_ONE, Y0, Y0
+	VMULPS      Y4, Y4, Y5
+	VMOVUPS     tanhConsts32<>+128(SB), Y6
+	VFMADD213PS tanhConsts32<>+160(SB), Y5, Y6
+	VFMADD213PS tanhConsts32<>+192(SB), Y5, Y6
+	VFMADD213PS tanhConsts32<>+224(SB), Y5, Y6
+	VFMADD213PS tanhConsts32<>+256(SB), Y5, Y6
+	VFMADD213PS tanhConsts32<>+288(SB), Y5, Y6
+	VFMADD213PS EXP32_ONE, Y5, Y6
+	VMULPS      Y4, Y6, Y6
+	VANDPS      tanhConsts32<>+32(SB), Y4, Y7
+	VCMPPS      $1, tanhConsts32<>+96(SB), Y7, Y7
+	VBLENDVPS   Y7, Y6, Y0, Y0
+	VMOVUPS Y0, (CX)
+	ADDQ    $32, AX
+	ADDQ    $32, CX
+	DECQ    DX
+	JNZ     loop
+
+done:
+	VZEROUPPER
+	RET
+
+// func TanhAVX64(x []float64, y []float64)
+// Requires: AVX, AVX2, FMA3
+TEXT ·TanhAVX64(SB), NOSPLIT, $0-48
+	MOVQ x_base+0(FP), AX
+	MOVQ x_len+8(FP), DX
+	MOVQ y_base+24(FP), CX
+	SHRQ $2, DX
+	JZ   done
+
+loop:
+	VMOVUPD     (AX), Y4
+	VADDPD      Y4, Y4, Y0
+	VXORPD      tanhConsts64<>+0(SB), Y0, Y0
+	EXP_AVX64(Y0, Y1, Y2, Y3, X1, X3)
+	VADDPD      EXP64_ONE, Y0, Y0
+	VMOVUPD     tanhConsts64<>+64(SB), Y1
+	VDIVPD      Y0, Y1, Y0
+	VSUBPD      EXP64_ONE, Y0, Y0
+	VMULPD      Y4, Y4, Y5
+	VMOVUPD     tanhConsts64<>+128(SB), Y6
+	VFMADD213PD tanhConsts64<>+160(SB), Y5, Y6
+	VFMADD213PD tanhConsts64<>+192(SB), Y5, Y6
+	VFMADD213PD tanhConsts64<>+224(SB), Y5, Y6
+	VFMADD213PD tanhConsts64<>+256(SB), Y5, Y6
+	VFMADD213PD tanhConsts64<>+288(SB), Y5, Y6
+	VFMADD213PD EXP64_ONE, Y5, Y6
+	VMULPD      Y4, Y6, Y6
+	VANDPD      tanhConsts64<>+32(SB), Y4, Y7
+	VCMPPD      $1, tanhConsts64<>+96(SB), Y7, Y7
+	VBLENDVPD   Y7, Y6, Y0, Y0
+	VMOVUPD Y0, (CX)
+	ADDQ    $32, AX
+	ADDQ    $32, CX
+	DECQ    DX
+	JNZ     loop
+
+done:
+	VZEROUPPER
+	RET
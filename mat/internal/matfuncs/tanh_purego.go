@@ -0,0 +1,17 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !amd64 || !gc || purego
+
+package matfuncs
+
+// Tanh32 computes the hyperbolic tangent of each element of x, storing the result in y (32 bits).
+func Tanh32(x, y []float32) {
+	tanh(x, y)
+}
+
+// Tanh64 computes the hyperbolic tangent of each element of x, storing the result in y (64 bits).
+func Tanh64(x, y []float64) {
+	tanh(x, y)
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matfuncs
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTanh32(t *testing.T) {
+	testUnaryFunc(t, Tanh32, tanh[float32], 1e-6)
+}
+
+func TestTanh32_NearZero(t *testing.T) {
+	x := []float32{1e-7, -3e-6, 1e-4, -2.5e-3, 0.01, -0.1, 0.3, -0.49, 0.5, -0.75}
+	y := make([]float32, len(x))
+	Tanh32(x, y)
+	for i, v := range x {
+		expected := math.Tanh(float64(v))
+		if d := math.Abs(float64(y[i])-expected) / math.Abs(expected); d > 1e-6 {
+			t.Fatalf("tanh(%G): expected %G, actual %G (relative error %G)", v, expected, y[i], d)
+		}
+	}
+}
+
+func TestTanh64(t *testing.T) {
+	testUnaryFunc(t, Tanh64, tanh[float64], 1e-12)
+}
+
+func TestTanh64_NearZero(t *testing.T) {
+	x := []float64{1e-300, -1e-12, 3e-8, -1e-4, 0.01, -0.05, 0.0999, -0.1, 0.2, -0.5, 0.75, -3}
+	y := make([]float64, len(x))
+	Tanh64(x, y)
+	for i, v := range x {
+		expected := math.Tanh(v)
+		if d := math.Abs(y[i]-expected) / math.Abs(expected); d > 1e-14 {
+			t.Fatalf("tanh(%G): expected %G, actual %G (relative error %G)", v, expected, y[i], d)
+		}
+	}
+}
+
+func BenchmarkTanh32(b *testing.B) {
+	benchmarkUnaryFunc(b, Tanh32)
+}
+
+func BenchmarkTanh64(b *testing.B) {
+	benchmarkUnaryFunc(b, Tanh64)
+}
+
+// BenchmarkTanh32Generic and BenchmarkTanh64Generic measure the scalar
+// implementations, for comparison with the AVX kernels.
+func BenchmarkTanh32Generic(b *testing.B) {
+	benchmarkUnaryFunc(b, tanh[float32])
+}
+
+func BenchmarkTanh64Generic(b *testing.B) {
+	benchmarkUnaryFunc(b, tanh[float64])
+}
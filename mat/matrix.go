@@ -47,6 +47,20 @@ type Matrix interface {
 	// given positions. The parameters "fromRow" and "fromCol" are inclusive,
 	// while "toRow" and "toCol" are exclusive.
 	Slice(fromRow, fromCol, toRow, toCol int) Matrix
+	// IndexSelect returns a new matrix made of the rows (axis 0) or the
+	// columns (axis 1) of the receiver at the given indices, in order.
+	IndexSelect(axis int, indices ...int) Matrix
+	// IndexAddInPlace adds the rows (axis 0) or the columns (axis 1) of src
+	// to the rows or columns of the receiver at the given indices,
+	// accumulating the values on repeated indices.
+	IndexAddInPlace(axis int, indices []int, src Matrix) Matrix
+	// Gather returns a new matrix with the same shape of index, collecting
+	// the values of the receiver along the given axis.
+	Gather(axis int, index [][]int) Matrix
+	// ScatterAddInPlace adds the values of src to the receiver, at the
+	// positions given by index along the given axis, accumulating the
+	// values on repeated indices.
+	ScatterAddInPlace(axis int, index [][]int, src Matrix) Matrix
 	// Reshape returns a copy of the matrix.
 	// It panics if the dimensions are incompatible.
 	Reshape(shape ...int) Matrix
@@ -122,6 +136,30 @@ type Matrix interface {
 	Maximum(other Matrix) Matrix
 	// Minimum returns a new matrix containing the element-wise minima.
 	Minimum(other Matrix) Matrix
+	// Greater returns a new mask matrix, set to 1 where the receiver's values
+	// are greater than the other's (or than the other scalar), and to 0 elsewhere.
+	Greater(other Matrix) Matrix
+	// GreaterEqual returns a new mask matrix, set to 1 where the receiver's
+	// values are greater than or equal to the other's, and to 0 elsewhere.
+	GreaterEqual(other Matrix) Matrix
+	// Less returns a new mask matrix, set to 1 where the receiver's values
+	// are less than the other's, and to 0 elsewhere.
+	Less(other Matrix) Matrix
+	// LessEqual returns a new mask matrix, set to 1 where the receiver's
+	// values are less than or equal to the other's, and to 0 elsewhere.
+	LessEqual(other Matrix) Matrix
+	// Equal returns a new mask matrix, set to 1 where the receiver's values
+	// are equal to the other's, and to 0 elsewhere.
+	Equal(other Matrix) Matrix
+	// NotEqual returns a new mask matrix, set to 1 where the receiver's values
+	// differ from the other's, and to 0 elsewhere.
+	NotEqual(other Matrix) Matrix
+	// Where returns a new matrix taking the values of a where the receiver is
+	// non-zero, and the values of b elsewhere.
+	Where(a, b Matrix) Matrix
+	// MaskedFill returns a copy of the matrix, with the given value in place
+	// of the elements where the mask is non-zero.
+	MaskedFill(mask Matrix, value float64) Matrix
 	// Abs returns a new matrix applying the absolute value function to all elements.
 	Abs() Matrix
 	// Pow returns a new matrix, applying the power function with given exponent
@@ -143,12 +181,31 @@ type Matrix interface {
 	Min() Matrix
 	// ArgMax returns the index of the vector's element with the maximum value.
 	ArgMax() int
+	// ArgSort returns the indices that would sort the vector, in ascending
+	// order, or descending if specified.
+	ArgSort(descending bool) []int
+	// Sort returns a copy of the vector, with the values sorted in ascending
+	// order, or descending if specified.
+	Sort(descending bool) Matrix
+	// TopK returns the k largest values of the vector, in descending order,
+	// and their indices.
+	TopK(k int) (Matrix, []int)
+	// KMaxPooling returns, for each row of the matrix, its k largest values
+	// in their original order, and their column indices.
+	KMaxPooling(k int) (Matrix, [][]int)
 	// Softmax applies the softmax function to the vector, returning the
 	// result as a new column vector.
 	Softmax() Matrix
 	// CumSum computes the cumulative sum of the vector's elements, returning
 	// the result as a new column vector.
 	CumSum() Matrix
+	// CumProd computes the cumulative product of the vector's elements,
+	// returning the result as a new column vector.
+	CumProd() Matrix
+	// LogCumSumExp computes the logarithm of the cumulative sum of the
+	// exponentials of the vector's elements, returning the result as a new
+	// column vector.
+	LogCumSumExp() Matrix
 	// Range creates a new vector initialized with data extracted from the
 	// matrix raw data, from start (inclusive) to end (exclusive).
 	Range(start, end int) Matrix
@@ -288,7 +345,7 @@ func ConcatV[T float.DType](vs ...Matrix) *Dense[T] {
 		size += v.Size()
 	}
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](size), size, 1)
+	out := newDenseIn[T](arenaOf(vs...), size, 1)
 	data := out.data[:0] // convenient for using append below
 	for _, v := range vs {
 		data = append(data, Data[T](v)...)
@@ -308,7 +365,7 @@ func Stack[T float.DType](vs ...Matrix) *Dense[T] {
 	}
 	cols := vs[0].Size()
 	// Note: Consider that for performance optimization, it's not necessary to initialize the underlying slice to zero.
-	out := makeDense[T](malloc[T](len(vs)*cols), len(vs), cols)
+	out := newDenseIn[T](arenaOf(vs...), len(vs), cols)
 	data := out.data
 	for i, v := range vs {
 		if !IsVector(v) {
@@ -333,17 +390,3 @@ func Equal(a, b Tensor) bool {
 func InDelta(a, b Matrix, delta float64) bool {
 	return areSlicesEqual(a.Shape(), b.Shape()) && a.Data().InDelta(b.Data(), delta)
 }
-
-func float32Data(m Matrix) []float32 {
-	if d, ok := m.(*Dense[float32]); ok {
-		return d.data
-	}
-	return m.Data().F32()
-}
-
-func float64Data(m Matrix) []float64 {
-	if d, ok := m.(*Dense[float64]); ok {
-		return d.data
-	}
-	return m.Data().F64()
-}
@@ -0,0 +1,85 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+// QR computes the thin QR decomposition of m, by means of Householder
+// reflections, so that m = q·r.
+//
+// The matrix m must have at least as many rows as columns. Given m of
+// shape (rows × cols), q has shape (rows × cols) with orthonormal columns,
+// and r is an upper triangular matrix of shape (cols × cols).
+// The computation is always carried out in float64 precision, and the
+// results have the same type of m.
+func QR(m Matrix) (q, r Matrix) {
+	rows, cols := m.Shape()[0], m.Shape()[1]
+	if rows < cols {
+		panic("mat: QR requires at least as many rows as columns")
+	}
+	a := append([]float64(nil), m.Data().F64()...)
+	vs := householder(a, rows, cols)
+
+	rData := make([]float64, cols*cols)
+	for i := 0; i < cols; i++ {
+		copy(rData[i*cols+i:(i+1)*cols], a[i*cols+i:(i+1)*cols])
+	}
+
+	qData := make([]float64, rows*cols)
+	for i := 0; i < cols; i++ {
+		qData[i*cols+i] = 1
+	}
+	for k := cols - 1; k >= 0; k-- {
+		reflect(qData, rows, cols, k, k, vs[k])
+	}
+
+	q = m.NewMatrix(WithShape(rows, cols), WithBacking(qData))
+	r = m.NewMatrix(WithShape(cols, cols), WithBacking(rData))
+	return q, r
+}
+
+// householder reduces the row-major matrix a to upper triangular form in
+// place, returning the unit Householder vectors of each step.
+func householder(a []float64, rows, cols int) [][]float64 {
+	vs := make([][]float64, cols)
+	for k := 0; k < cols; k++ {
+		v := make([]float64, rows-k)
+		norm := 0.0
+		for i := range v {
+			v[i] = a[(k+i)*cols+k]
+			norm += v[i] * v[i]
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			vs[k] = v // all zeros: the reflection is the identity
+			continue
+		}
+		v[0] += math.Copysign(norm, v[0])
+		normV := math.Sqrt(2 * norm * (norm + math.Abs(a[k*cols+k])))
+		for i := range v {
+			v[i] /= normV
+		}
+		reflect(a, rows, cols, k, k, v)
+		vs[k] = v
+	}
+	return vs
+}
+
+// reflect applies the Householder reflection (I - 2·v·vᵀ) to the rows
+// [row0, rows) of the row-major matrix a, for the columns [col0, cols).
+func reflect(a []float64, rows, cols, row0, col0 int, v []float64) {
+	for j := col0; j < cols; j++ {
+		dot := 0.0
+		for i := row0; i < rows; i++ {
+			dot += v[i-row0] * a[i*cols+j]
+		}
+		if dot == 0 {
+			continue
+		}
+		for i := row0; i < rows; i++ {
+			a[i*cols+j] -= 2 * dot * v[i-row0]
+		}
+	}
+}
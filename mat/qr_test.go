@@ -0,0 +1,65 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQR(t *testing.T) {
+	t.Run("float32", testQR[float32])
+	t.Run("float64", testQR[float64])
+}
+
+func testQR[T float.DType](t *testing.T) {
+	t.Run("square", func(t *testing.T) {
+		m := NewDense[T](WithShape(3, 3), WithBacking([]T{
+			12, -51, 4,
+			6, 167, -68,
+			-4, 24, -41,
+		}))
+		q, r := QR(m)
+		assertQR(t, m, q, r)
+		assert.InDeltaSlice(t, []T{-14, -21, 14, 0, -175, 70, 0, 0, 35}, r.Data(), 1.0e-4)
+	})
+
+	t.Run("tall", func(t *testing.T) {
+		m := NewDense[T](WithShape(4, 2), WithBacking([]T{
+			1, 2,
+			3, 4,
+			5, 6,
+			7, 8,
+		}))
+		q, r := QR(m)
+		assert.Equal(t, []int{4, 2}, q.Shape())
+		assert.Equal(t, []int{2, 2}, r.Shape())
+		assertQR(t, m, q, r)
+	})
+
+	t.Run("rank deficient", func(t *testing.T) {
+		m := NewDense[T](WithShape(3, 2), WithBacking([]T{1, 0, 2, 0, 3, 0}))
+		q, r := QR(m)
+		assert.InDeltaSlice(t, m.Data(), q.Mul(r).Data(), 1.0e-5)
+	})
+
+	t.Run("wide", func(t *testing.T) {
+		assert.Panics(t, func() { QR(NewDense[T](WithShape(2, 3))) })
+	})
+}
+
+func assertQR(t *testing.T, m, q, r Matrix) {
+	t.Helper()
+	cols := m.Shape()[1]
+	assert.InDeltaSlice(t, m.Data(), q.Mul(r).Data(), 1.0e-4)
+	assert.InDeltaSlice(t, CreateIdentityMatrix[float64](cols), q.T().Mul(q).Data().F64(), 1.0e-5)
+	for i := 0; i < cols; i++ {
+		for j := 0; j < i; j++ {
+			assert.Zero(t, r.At(i, j).Item().F64())
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package beta
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/mat/rand/gamma"
+)
+
+// Beta is a source of Beta distributed random numbers.
+// See: https://en.wikipedia.org/wiki/Beta_distribution.
+type Beta struct {
+	Alpha     float64
+	Beta      float64
+	generator *rand.LockedRand
+}
+
+// New returns a new Beta, initialized with the given positive alpha and
+// beta parameters.
+func New(alpha, beta float64, generator *rand.LockedRand) *Beta {
+	if alpha <= 0 || beta <= 0 {
+		panic("beta: alpha and beta must be positive")
+	}
+	return &Beta{
+		Alpha:     alpha,
+		Beta:      beta,
+		generator: generator,
+	}
+}
+
+// Next returns a random sample drawn from the distribution.
+// It is computed as X / (X + Y), with X ~ Gamma(alpha, 1) and Y ~ Gamma(beta, 1).
+func (b Beta) Next() float64 {
+	x := gamma.Sample(b.Alpha, b.generator)
+	y := gamma.Sample(b.Beta, b.generator)
+	return x / (x + y)
+}
+
+// Distribution creates a new matrix initialized with Beta distribution.
+func Distribution[T float.DType](r, c int, alpha, beta float64, generator *rand.LockedRand) mat.Matrix {
+	dist := New(alpha, beta, generator)
+	data := make([]T, r*c)
+	for i := range data {
+		data[i] = T(dist.Next())
+	}
+	return mat.NewDense[T](mat.WithShape(r, c), mat.WithBacking(data))
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package categorical
+
+import (
+	"math"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+// Categorical is a source of categorically distributed indices, with the
+// probabilities given by the softmax of unnormalized log-probabilities.
+// See: https://en.wikipedia.org/wiki/Categorical_distribution.
+type Categorical struct {
+	Logits    []float64
+	cdf       []float64
+	generator *rand.LockedRand
+}
+
+// New returns a new Categorical, initialized with the given logits.
+func New(logits []float64, generator *rand.LockedRand) *Categorical {
+	if len(logits) == 0 {
+		panic("categorical: logits cannot be empty")
+	}
+	return &Categorical{
+		Logits:    logits,
+		cdf:       cumulativeSoftmax(logits),
+		generator: generator,
+	}
+}
+
+// cumulativeSoftmax returns the cumulative sums of the softmax of the logits.
+func cumulativeSoftmax(logits []float64) []float64 {
+	maxLogit := math.Inf(-1)
+	for _, v := range logits {
+		maxLogit = math.Max(maxLogit, v)
+	}
+	cdf := make([]float64, len(logits))
+	sum := 0.0
+	for i, v := range logits {
+		sum += math.Exp(v - maxLogit)
+		cdf[i] = sum
+	}
+	for i := range cdf {
+		cdf[i] /= sum
+	}
+	return cdf
+}
+
+// Probs returns the probability of each category.
+func (c Categorical) Probs() []float64 {
+	out := make([]float64, len(c.cdf))
+	prev := 0.0
+	for i, v := range c.cdf {
+		out[i] = v - prev
+		prev = v
+	}
+	return out
+}
+
+// Next returns the index of a category drawn from the distribution.
+func (c Categorical) Next() int {
+	u := c.generator.Float64()
+	i := sort.Search(len(c.cdf), func(i int) bool { return c.cdf[i] > u })
+	if i == len(c.cdf) {
+		return len(c.cdf) - 1 // guard against rounding errors
+	}
+	return i
+}
+
+// Multinomial returns the number of times each category is drawn in n
+// independent trials.
+func (c Categorical) Multinomial(n int) []int {
+	counts := make([]int, len(c.cdf))
+	for i := 0; i < n; i++ {
+		counts[c.Next()]++
+	}
+	return counts
+}
+
+// Distribution creates a new matrix with r rows, each one holding the counts
+// of n multinomial trials over the categories defined by the logits.
+// With n = 1 each row is a one-hot vector.
+func Distribution[T float.DType](r, n int, logits []float64, generator *rand.LockedRand) mat.Matrix {
+	dist := New(logits, generator)
+	k := len(logits)
+	data := make([]T, r*k)
+	for i := 0; i < r; i++ {
+		for j := 0; j < n; j++ {
+			data[i*k+dist.Next()]++
+		}
+	}
+	return mat.NewDense[T](mat.WithShape(r, k), mat.WithBacking(data))
+}
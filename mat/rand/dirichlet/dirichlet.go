@@ -0,0 +1,71 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dirichlet
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/mat/rand/gamma"
+)
+
+// Dirichlet is a source of Dirichlet distributed random vectors.
+// See: https://en.wikipedia.org/wiki/Dirichlet_distribution.
+type Dirichlet struct {
+	Alpha     []float64
+	generator *rand.LockedRand
+}
+
+// New returns a new Dirichlet, initialized with the given concentration
+// parameters, which must all be positive.
+func New(alpha []float64, generator *rand.LockedRand) *Dirichlet {
+	if len(alpha) == 0 {
+		panic("dirichlet: alpha cannot be empty")
+	}
+	for _, a := range alpha {
+		if a <= 0 {
+			panic("dirichlet: alpha must be positive")
+		}
+	}
+	return &Dirichlet{
+		Alpha:     alpha,
+		generator: generator,
+	}
+}
+
+// Next returns a random vector drawn from the distribution, whose
+// elements are positive and sum up to one.
+func (d Dirichlet) Next() []float64 {
+	out := make([]float64, len(d.Alpha))
+	d.fill(out)
+	return out
+}
+
+func (d Dirichlet) fill(out []float64) {
+	sum := 0.0
+	for i, a := range d.Alpha {
+		out[i] = gamma.Sample(a, d.generator)
+		sum += out[i]
+	}
+	for i := range out {
+		out[i] /= sum
+	}
+}
+
+// Distribution creates a new matrix with r rows, each one being a sample
+// drawn from a Dirichlet distribution with the given concentration parameters.
+func Distribution[T float.DType](r int, alpha []float64, generator *rand.LockedRand) mat.Matrix {
+	dist := New(alpha, generator)
+	c := len(alpha)
+	data := make([]T, r*c)
+	row := make([]float64, c)
+	for i := 0; i < r; i++ {
+		dist.fill(row)
+		for j, v := range row {
+			data[i*c+j] = T(v)
+		}
+	}
+	return mat.NewDense[T](mat.WithShape(r, c), mat.WithBacking(data))
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rand_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/mat/rand/beta"
+	"github.com/nlpodyssey/spago/mat/rand/categorical"
+	"github.com/nlpodyssey/spago/mat/rand/dirichlet"
+	"github.com/nlpodyssey/spago/mat/rand/gamma"
+	"github.com/nlpodyssey/spago/mat/rand/gumbel"
+	"github.com/nlpodyssey/spago/mat/rand/laplace"
+	"github.com/nlpodyssey/spago/mat/rand/poisson"
+	"github.com/nlpodyssey/spago/mat/rand/truncnormal"
+	"github.com/stretchr/testify/assert"
+)
+
+const numSamples = 200_000
+
+// moments returns the sample mean and variance of n draws from next.
+func moments(next func() float64) (mean, variance float64) {
+	var sum, sumSq float64
+	for i := 0; i < numSamples; i++ {
+		v := next()
+		sum += v
+		sumSq += v * v
+	}
+	mean = sum / numSamples
+	return mean, sumSq/numSamples - mean*mean
+}
+
+func TestDistributions_Moments(t *testing.T) {
+	const eulerGamma = 0.5772156649015329
+
+	testCases := []struct {
+		name     string
+		next     func(*rand.LockedRand) func() float64
+		mean     float64
+		variance float64
+	}{
+		{"gamma", func(g *rand.LockedRand) func() float64 { return gamma.New(2.5, 2, g).Next }, 5, 10},
+		{"gamma small shape", func(g *rand.LockedRand) func() float64 { return gamma.New(0.3, 1, g).Next }, 0.3, 0.3},
+		{"beta", func(g *rand.LockedRand) func() float64 { return beta.New(2, 5, g).Next }, 2.0 / 7, 10.0 / (49 * 8)},
+		{"poisson small", func(g *rand.LockedRand) func() float64 { return poisson.New(3.5, g).Next }, 3.5, 3.5},
+		{"poisson large", func(g *rand.LockedRand) func() float64 { return poisson.New(120, g).Next }, 120, 120},
+		{"gumbel", func(g *rand.LockedRand) func() float64 { return gumbel.New(1, 2, g).Next }, 1 + 2*eulerGamma, math.Pi * math.Pi / 6 * 4},
+		{"laplace", func(g *rand.LockedRand) func() float64 { return laplace.New(-1, 0.5, g).Next }, -1, 0.5},
+		// Standard normal truncated to [-1, 1].
+		{"truncnormal", func(g *rand.LockedRand) func() float64 { return truncnormal.New(1, 0, -1, 1, g).Next }, 0, 0.2911},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mean, variance := moments(tc.next(rand.NewLockedRand(42)))
+			assert.InDelta(t, tc.mean, mean, 0.02*math.Max(1, math.Abs(tc.mean)))
+			assert.InDelta(t, tc.variance, variance, 0.03*math.Max(1, tc.variance))
+		})
+	}
+}
+
+func TestPoisson_Integral(t *testing.T) {
+	for _, lambda := range []float64{0, 0.5, 30} {
+		p := poisson.New(lambda, rand.NewLockedRand(1))
+		for i := 0; i < 1000; i++ {
+			v := p.Next()
+			assert.True(t, v >= 0 && v == math.Trunc(v))
+		}
+	}
+}
+
+func TestTruncNormal_Bounds(t *testing.T) {
+	d := truncnormal.New(1, 0, 3, 4, rand.NewLockedRand(1))
+	for i := 0; i < 1000; i++ {
+		v := d.Next()
+		assert.True(t, v >= 3 && v <= 4)
+	}
+}
+
+func TestDirichlet(t *testing.T) {
+	alpha := []float64{1, 2, 7}
+	m := dirichlet.Distribution[float64](numSamples/10, alpha, rand.NewLockedRand(42))
+	assert.Equal(t, []int{numSamples / 10, 3}, m.Shape())
+
+	means := make([]float64, 3)
+	for i := 0; i < m.Shape()[0]; i++ {
+		row := mat.Data[float64](m.ExtractRow(i))
+		assert.InDelta(t, 1.0, row[0]+row[1]+row[2], 1e-12)
+		for j, v := range row {
+			means[j] += v / float64(m.Shape()[0])
+		}
+	}
+	assert.InDeltaSlice(t, []float64{0.1, 0.2, 0.7}, means, 0.005)
+}
+
+func TestCategorical(t *testing.T) {
+	logits := []float64{math.Log(0.2), math.Log(0.5), math.Log(0.3)}
+	c := categorical.New(logits, rand.NewLockedRand(42))
+	assert.InDeltaSlice(t, []float64{0.2, 0.5, 0.3}, c.Probs(), 1e-12)
+
+	counts := c.Multinomial(numSamples)
+	for i, p := range c.Probs() {
+		assert.InDelta(t, p, float64(counts[i])/numSamples, 0.005)
+	}
+
+	m := categorical.Distribution[float32](10, 1, logits, rand.NewLockedRand(42))
+	assert.Equal(t, []int{10, 3}, m.Shape())
+	for i := 0; i < 10; i++ {
+		row := mat.Data[float32](m.ExtractRow(i))
+		assert.Equal(t, float32(1), row[0]+row[1]+row[2])
+	}
+}
+
+func TestDistribution_Reproducible(t *testing.T) {
+	a := gamma.Distribution[float32](3, 4, 2, 1, rand.NewLockedRand(7))
+	b := gamma.Distribution[float32](3, 4, 2, 1, rand.NewLockedRand(7))
+	assert.Equal(t, []int{3, 4}, a.Shape())
+	assert.Equal(t, a.Data(), b.Data())
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gamma
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+// Gamma is a source of Gamma distributed random numbers.
+// See: https://en.wikipedia.org/wiki/Gamma_distribution.
+type Gamma struct {
+	Shape     float64
+	Scale     float64
+	generator *rand.LockedRand
+}
+
+// New returns a new Gamma, initialized with the given shape (k > 0) and
+// scale (θ > 0) parameters.
+func New(shape, scale float64, generator *rand.LockedRand) *Gamma {
+	if shape <= 0 || scale <= 0 {
+		panic("gamma: shape and scale must be positive")
+	}
+	return &Gamma{
+		Shape:     shape,
+		Scale:     scale,
+		generator: generator,
+	}
+}
+
+// Next returns a random sample drawn from the distribution.
+func (g Gamma) Next() float64 {
+	return Sample(g.Shape, g.generator) * g.Scale
+}
+
+// Sample draws a value from a Gamma distribution with the given shape and
+// unit scale, using the method of Marsaglia and Tsang (2000).
+// Shapes lower than one are boosted with U^(1/shape).
+func Sample(shape float64, generator *rand.LockedRand) float64 {
+	if shape < 1 {
+		u := generator.Float64()
+		return Sample(shape+1, generator) * math.Pow(u, 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := generator.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := generator.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// Distribution creates a new matrix initialized with Gamma distribution.
+func Distribution[T float.DType](r, c int, shape, scale float64, generator *rand.LockedRand) mat.Matrix {
+	dist := New(shape, scale, generator)
+	data := make([]T, r*c)
+	for i := range data {
+		data[i] = T(dist.Next())
+	}
+	return mat.NewDense[T](mat.WithShape(r, c), mat.WithBacking(data))
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gumbel
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+// Gumbel is a source of Gumbel distributed random numbers.
+// See: https://en.wikipedia.org/wiki/Gumbel_distribution.
+type Gumbel struct {
+	Loc       float64
+	Scale     float64
+	generator *rand.LockedRand
+}
+
+// New returns a new Gumbel, initialized with the given location and
+// scale (> 0) parameters.
+func New(loc, scale float64, generator *rand.LockedRand) *Gumbel {
+	if scale <= 0 {
+		panic("gumbel: scale must be positive")
+	}
+	return &Gumbel{
+		Loc:       loc,
+		Scale:     scale,
+		generator: generator,
+	}
+}
+
+// Next returns a random sample drawn from the distribution.
+func (g Gumbel) Next() float64 {
+	u := g.generator.Float64()
+	for u == 0 {
+		u = g.generator.Float64()
+	}
+	return g.Loc - g.Scale*math.Log(-math.Log(u))
+}
+
+// Distribution creates a new matrix initialized with Gumbel distribution.
+func Distribution[T float.DType](r, c int, loc, scale float64, generator *rand.LockedRand) mat.Matrix {
+	dist := New(loc, scale, generator)
+	data := make([]T, r*c)
+	for i := range data {
+		data[i] = T(dist.Next())
+	}
+	return mat.NewDense[T](mat.WithShape(r, c), mat.WithBacking(data))
+}
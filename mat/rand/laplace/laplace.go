@@ -0,0 +1,57 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package laplace
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+// Laplace is a source of Laplace distributed random numbers.
+// See: https://en.wikipedia.org/wiki/Laplace_distribution.
+type Laplace struct {
+	Loc       float64
+	Scale     float64
+	generator *rand.LockedRand
+}
+
+// New returns a new Laplace, initialized with the given location and
+// scale (> 0) parameters.
+func New(loc, scale float64, generator *rand.LockedRand) *Laplace {
+	if scale <= 0 {
+		panic("laplace: scale must be positive")
+	}
+	return &Laplace{
+		Loc:       loc,
+		Scale:     scale,
+		generator: generator,
+	}
+}
+
+// Next returns a random sample drawn from the distribution.
+func (l Laplace) Next() float64 {
+	u := l.generator.Float64()
+	for u == 0 {
+		u = l.generator.Float64()
+	}
+	u -= 0.5
+	if u < 0 {
+		return l.Loc + l.Scale*math.Log(1+2*u)
+	}
+	return l.Loc - l.Scale*math.Log(1-2*u)
+}
+
+// Distribution creates a new matrix initialized with Laplace distribution.
+func Distribution[T float.DType](r, c int, loc, scale float64, generator *rand.LockedRand) mat.Matrix {
+	dist := New(loc, scale, generator)
+	data := make([]T, r*c)
+	for i := range data {
+		data[i] = T(dist.Next())
+	}
+	return mat.NewDense[T](mat.WithShape(r, c), mat.WithBacking(data))
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package poisson
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+// Poisson is a source of Poisson distributed random numbers.
+// See: https://en.wikipedia.org/wiki/Poisson_distribution.
+type Poisson struct {
+	Lambda    float64
+	generator *rand.LockedRand
+}
+
+// New returns a new Poisson, initialized with the given rate (lambda >= 0).
+func New(lambda float64, generator *rand.LockedRand) *Poisson {
+	if lambda < 0 {
+		panic("poisson: lambda cannot be negative")
+	}
+	return &Poisson{
+		Lambda:    lambda,
+		generator: generator,
+	}
+}
+
+// Next returns a random sample drawn from the distribution.
+// The value is always a non-negative integer.
+func (p Poisson) Next() float64 {
+	if p.Lambda < 10 {
+		return p.knuth()
+	}
+	return p.ptrs()
+}
+
+// knuth multiplies uniform samples until their product drops below e^-lambda.
+func (p Poisson) knuth() float64 {
+	limit := math.Exp(-p.Lambda)
+	k := 0.0
+	for prod := p.generator.Float64(); prod > limit; prod *= p.generator.Float64() {
+		k++
+	}
+	return k
+}
+
+// ptrs implements the transformed rejection method with squeeze of
+// Hörmann (1993), suitable for large rates.
+func (p Poisson) ptrs() float64 {
+	lam := p.Lambda
+	slam := math.Sqrt(lam)
+	logLam := math.Log(lam)
+	b := 0.931 + 2.53*slam
+	a := -0.059 + 0.02483*b
+	invAlpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+	for {
+		u := p.generator.Float64() - 0.5
+		v := p.generator.Float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lam + 0.43)
+		if us >= 0.07 && v <= vr {
+			return k
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		lg, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invAlpha)-math.Log(a/(us*us)+b) <= -lam+k*logLam-lg {
+			return k
+		}
+	}
+}
+
+// Distribution creates a new matrix initialized with Poisson distribution.
+func Distribution[T float.DType](r, c int, lambda float64, generator *rand.LockedRand) mat.Matrix {
+	dist := New(lambda, generator)
+	data := make([]T, r*c)
+	for i := range data {
+		data[i] = T(dist.Next())
+	}
+	return mat.NewDense[T](mat.WithShape(r, c), mat.WithBacking(data))
+}
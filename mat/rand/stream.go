@@ -0,0 +1,132 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rand
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+
+	"github.com/nlpodyssey/spago/mat/internal/rand"
+)
+
+// golden is the 64-bit golden ratio, the increment of SplitMix64.
+const golden = 0x9e3779b97f4a7c15
+
+// Stream is a deterministic, splittable stream of random numbers.
+//
+// It is counter-based: the i-th number of a stream is a hash of the stream
+// key and i, so it never depends on what other streams, or other users of
+// the same stream, have drawn before. Streams derived with Split and SplitN
+// from a seed and a stable key are therefore reproducible regardless of the
+// order in which goroutines are scheduled.
+//
+// A Stream is immutable and safe for concurrent use. It implements
+// encoding.BinaryMarshaler, so that it can be serialized with gob as part
+// of a model.
+type Stream struct {
+	key uint64
+}
+
+// NewStream returns the root stream for the given seed.
+func NewStream(seed uint64) *Stream {
+	return &Stream{key: mix64(seed)}
+}
+
+// Split returns the sub-stream identified by the given key (for example,
+// the path of a parameter, or the name of a layer).
+func (s *Stream) Split(key string) *Stream {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return s.derive(h.Sum64())
+}
+
+// SplitN returns the n-th sub-stream (for example, of a training step or of
+// an example in a batch).
+func (s *Stream) SplitN(n uint64) *Stream {
+	return s.derive(n)
+}
+
+func (s *Stream) derive(v uint64) *Stream {
+	return &Stream{key: mix64(s.key ^ mix64(v+golden))}
+}
+
+// MarshalBinary returns the binary encoding of the stream.
+func (s *Stream) MarshalBinary() ([]byte, error) {
+	return binary.LittleEndian.AppendUint64(nil, s.key), nil
+}
+
+// UnmarshalBinary restores a stream encoded by MarshalBinary.
+func (s *Stream) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return errors.New("rand: invalid stream encoding")
+	}
+	s.key = binary.LittleEndian.Uint64(data)
+	return nil
+}
+
+// Uint64At returns the i-th pseudo-random 64-bit value of the stream.
+func (s *Stream) Uint64At(i uint64) uint64 {
+	return splitMix64(s.key, i)
+}
+
+// Float64At returns the i-th pseudo-random number of the stream in [0.0,1.0).
+func (s *Stream) Float64At(i uint64) float64 {
+	return float64(s.Uint64At(i)>>11) / (1 << 53)
+}
+
+// NormFloat64At returns the i-th normally distributed number of the stream,
+// with standard normal distribution (mean = 0, stddev = 1).
+//
+// It is computed with the Box-Muller transform from the values at 2i and
+// 2i+1 of a dedicated sub-stream.
+func (s *Stream) NormFloat64At(i uint64) float64 {
+	n := s.derive(math.MaxUint64)
+	u1 := 1 - n.Float64At(2*i) // (0, 1]
+	u2 := n.Float64At(2*i + 1)
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
+
+// Rand returns a new generator drawing the numbers of the stream in order,
+// from the first one. Generators returned by different calls are independent
+// of each other, and all of them produce the same sequence.
+func (s *Stream) Rand() *LockedRand {
+	return &LockedRand{
+		r: rand.New(&streamSource{key: s.key}),
+	}
+}
+
+// streamSource is a rand.Source drawing the values of a Stream in order.
+type streamSource struct {
+	key     uint64
+	counter uint64
+}
+
+// Uint64 returns the next value of the stream.
+func (s *streamSource) Uint64() uint64 {
+	v := splitMix64(s.key, s.counter)
+	s.counter++
+	return v
+}
+
+// Seed resets the source to the beginning of the root stream for the given seed.
+func (s *streamSource) Seed(seed uint64) {
+	s.key = mix64(seed)
+	s.counter = 0
+}
+
+// splitMix64 returns the i-th output of the SplitMix64 generator with the
+// given initial state.
+func splitMix64(key, i uint64) uint64 {
+	return mix64(key + (i+1)*golden)
+}
+
+// mix64 is the finalizer of SplitMix64, a bijective mixing function.
+func mix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rand
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_Deterministic(t *testing.T) {
+	a := NewStream(42).Split("encoder").SplitN(3)
+	b := NewStream(42).Split("encoder").SplitN(3)
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, NewStream(43).Split("encoder").SplitN(3))
+	assert.NotEqual(t, a, NewStream(42).Split("decoder").SplitN(3))
+	assert.NotEqual(t, a, NewStream(42).SplitN(3).Split("encoder"))
+
+	for i := uint64(0); i < 10; i++ {
+		assert.Equal(t, a.Uint64At(i), b.Uint64At(i))
+	}
+}
+
+func TestStream_Rand(t *testing.T) {
+	s := NewStream(42)
+	r1, r2 := s.Rand(), s.Rand()
+	for i := uint64(0); i < 10; i++ {
+		v := r1.Uint64()
+		assert.Equal(t, s.Uint64At(i), v)
+		assert.Equal(t, v, r2.Uint64())
+	}
+
+	r1.Seed(7)
+	assert.Equal(t, NewStream(7).Uint64At(0), r1.Uint64())
+}
+
+func TestStream_Gob(t *testing.T) {
+	s := NewStream(42).Split("encoder")
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(s))
+	var decoded *Stream
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+	assert.Equal(t, s, decoded)
+
+	assert.Error(t, decoded.UnmarshalBinary([]byte{1, 2, 3}))
+	assert.Equal(t, s, decoded)
+}
+
+func TestStream_SchedulingIndependence(t *testing.T) {
+	root := NewStream(1)
+	draw := func(key string) []float64 {
+		r := root.Split(key).Rand()
+		v := make([]float64, 100)
+		for i := range v {
+			v[i] = r.NormFloat64()
+		}
+		return v
+	}
+
+	keys := []string{"a", "b", "c", "d"}
+	expected := make(map[string][]float64)
+	for _, k := range keys {
+		expected[k] = draw(k)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := len(keys) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func(k string) {
+			defer wg.Done()
+			v := draw(k)
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, expected[k], v)
+		}(keys[i])
+	}
+	wg.Wait()
+}
+
+func TestStream_Distribution(t *testing.T) {
+	s := NewStream(42)
+	const n = 100_000
+	var sum, sumSq, normSum, normSumSq float64
+	for i := uint64(0); i < n; i++ {
+		u := s.Float64At(i)
+		assert.True(t, u >= 0 && u < 1)
+		sum += u
+		sumSq += u * u
+		z := s.NormFloat64At(i)
+		normSum += z
+		normSumSq += z * z
+	}
+	assert.InDelta(t, 0.5, sum/n, 0.01)
+	assert.InDelta(t, 1.0/12, sumSq/n-math.Pow(sum/n, 2), 0.01)
+	assert.InDelta(t, 0.0, normSum/n, 0.02)
+	assert.InDelta(t, 1.0, normSumSq/n, 0.02)
+}
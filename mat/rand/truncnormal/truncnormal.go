@@ -0,0 +1,68 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package truncnormal
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+)
+
+// TruncNormal is a source of normally distributed random numbers, truncated
+// to the interval [Min, Max].
+// See: https://en.wikipedia.org/wiki/Truncated_normal_distribution.
+type TruncNormal struct {
+	Std       float64
+	Mean      float64
+	Min       float64
+	Max       float64
+	generator *rand.LockedRand
+}
+
+// New returns a new TruncNormal, initialized with the given standard
+// deviation and mean of the underlying normal distribution, and with the
+// bounds of the truncation interval.
+func New(std, mean, min, max float64, generator *rand.LockedRand) *TruncNormal {
+	if std <= 0 {
+		panic("truncnormal: std must be positive")
+	}
+	if min >= max {
+		panic("truncnormal: min must be lower than max")
+	}
+	return &TruncNormal{
+		Std:       std,
+		Mean:      mean,
+		Min:       min,
+		Max:       max,
+		generator: generator,
+	}
+}
+
+// Next returns a random sample drawn from the distribution.
+// It maps a uniform sample through the inverse CDF of the normal
+// distribution, restricted to the truncation interval.
+func (t TruncNormal) Next() float64 {
+	lo := normCDF((t.Min - t.Mean) / t.Std)
+	hi := normCDF((t.Max - t.Mean) / t.Std)
+	u := lo + t.generator.Float64()*(hi-lo)
+	x := t.Mean + t.Std*math.Sqrt2*math.Erfinv(2*u-1)
+	return math.Max(t.Min, math.Min(t.Max, x))
+}
+
+func normCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+// Distribution creates a new matrix initialized with truncated normal distribution.
+func Distribution[T float.DType](r, c int, std, mean, min, max float64, generator *rand.LockedRand) mat.Matrix {
+	dist := New(std, mean, min, max, generator)
+	data := make([]T, r*c)
+	for i := range data {
+		data[i] = T(dist.Next())
+	}
+	return mat.NewDense[T](mat.WithShape(r, c), mat.WithBacking(data))
+}
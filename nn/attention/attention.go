@@ -33,7 +33,7 @@ func ScaledDotProductAttention(q []mat.Tensor, k, v, scaleFactor mat.Tensor, use
 
 		if causalMaskEnabled {
 			causalMask := k.Value().(mat.Matrix).NewMatrix(mat.WithBacking(makeCausalMask(i, kRows))) // TODO: use external cache for causal mask?
-			scores = ag.Add(scores, causalMask)
+			scores = ag.MaskedFill(scores, causalMask, math.Inf(-1))
 		}
 
 		weights[i] = ag.Softmax(scores)
@@ -46,13 +46,13 @@ func ScaledDotProductAttention(q []mat.Tensor, k, v, scaleFactor mat.Tensor, use
 	return attention, weights
 }
 
-// makeCausalMask returns a slice of size seqLength filled with zeros until curIndex, and the rest with -inf.
+// makeCausalMask returns a mask of size seqLength filled with zeros until curIndex, and the rest with ones,
+// marking the positions to be hidden.
 // FIXME: avoid specific float64 type, later passed to NewVec
 func makeCausalMask(curIndex, seqLength int) []float64 {
-	negInf := math.Inf(-1)
 	causalMask := make([]float64, seqLength)
 	for k := curIndex + 1; k < seqLength; k++ {
-		causalMask[k] = negInf
+		causalMask[k] = 1
 	}
 	return causalMask
 }
@@ -0,0 +1,363 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package checkpoint implements a versioned, self-describing container for
+// the parameters of a model, which does not depend on gob nor on the Go
+// types of the model.
+//
+// A checkpoint starts with an 8-byte magic string and the 4-byte
+// little-endian format version, followed by three sections, each prefixed
+// by its 8-byte little-endian size:
+//
+//   - the header, a JSON object (see Header);
+//   - the model configuration, as JSON, possibly empty;
+//   - the tensors, as a sequence of entries made of a name and of a matrix
+//     encoded with the flatbuffers encoding of mat.Dense (MarshalBinary).
+//
+// Tensors are named after their path within the model (see
+// nn.NamedParameters and nn.NamedBuffers), so that a checkpoint survives
+// the renaming of Go types and packages. When the structure of a model
+// changes, migrations registered with RegisterMigration upgrade the
+// checkpoints saved by the previous versions.
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/fbs/dense"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+// FormatVersion is the version of the container format written by this
+// package. Checkpoints with a greater version cannot be read.
+const FormatVersion = 1
+
+// magic identifies a checkpoint.
+const magic = "SPAGOCKP"
+
+// Data types reported by Header.DType.
+const (
+	Float32 = "float32"
+	Float64 = "float64"
+	Mixed   = "mixed"
+)
+
+// maxSectionSize is an upper limit for the size of the header, of the
+// configuration and of the tensor names, preventing huge allocations when
+// reading corrupted files.
+const maxSectionSize = 100 << 20
+
+// Header describes the content of a checkpoint.
+type Header struct {
+	// FormatVersion is the version of the container format.
+	FormatVersion int `json:"format_version"`
+	// DType is the data type of the tensors (Float32, Float64 or Mixed),
+	// empty if there are no tensors.
+	DType string `json:"dtype"`
+	// SpagoVersion is the version of spaGO which wrote the checkpoint.
+	SpagoVersion string `json:"spago_version"`
+	// Model is an optional identifier of the kind of model, used to look up
+	// the migrations (see RegisterMigration).
+	Model string `json:"model,omitempty"`
+	// ModelVersion is the version of the structure of the model.
+	ModelVersion int `json:"model_version"`
+	// Metadata contains free-form user metadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Checkpoint is the content of a checkpoint.
+type Checkpoint struct {
+	Header Header
+	// Config is the JSON configuration of the model, if any.
+	Config json.RawMessage
+	// Tensors are the values of the parameters and buffers of the model,
+	// keyed by their path.
+	Tensors map[string]mat.Matrix
+}
+
+// FromModel returns a new checkpoint holding a copy of the parameters and
+// buffers of the model (see nn.StateDict) and the JSON encoding of config,
+// unless it is nil.
+func FromModel(m nn.Model, config any) (*Checkpoint, error) {
+	c := &Checkpoint{
+		Header: Header{
+			FormatVersion: FormatVersion,
+			SpagoVersion:  spagoVersion(),
+		},
+		Tensors: nn.StateDict(m),
+	}
+	if config != nil {
+		data, err := json.Marshal(config)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint: encoding config: %w", err)
+		}
+		c.Config = data
+	}
+	c.Header.DType = dataType(c.Tensors)
+	return c, nil
+}
+
+// DecodeConfig decodes the JSON configuration into the value pointed to by v.
+func (c *Checkpoint) DecodeConfig(v any) error {
+	if len(c.Config) == 0 {
+		return errors.New("checkpoint: no config")
+	}
+	if err := json.Unmarshal(c.Config, v); err != nil {
+		return fmt.Errorf("checkpoint: decoding config: %w", err)
+	}
+	return nil
+}
+
+// Apply loads the tensors into the parameters and buffers of the model with
+// the same path, as done by nn.LoadStateDict.
+func (c *Checkpoint) Apply(m nn.Model, strict bool) (nn.LoadReport, error) {
+	return nn.LoadStateDict(m, c.Tensors, strict)
+}
+
+// Write writes the checkpoint to w.
+func (c *Checkpoint) Write(w io.Writer) error {
+	header := c.Header
+	header.FormatVersion = FormatVersion
+	header.DType = dataType(c.Tensors)
+	if header.SpagoVersion == "" {
+		header.SpagoVersion = spagoVersion()
+	}
+	hdata, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("checkpoint: encoding header: %w", err)
+	}
+
+	bw := bufio.NewWriter(w)
+	var buf [8]byte
+	write := func(p []byte) {
+		if err == nil {
+			_, err = bw.Write(p)
+		}
+	}
+	writeUint := func(v uint64, size int) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		write(buf[:size])
+	}
+
+	write([]byte(magic))
+	writeUint(FormatVersion, 4)
+	writeUint(uint64(len(hdata)), 8)
+	write(hdata)
+	writeUint(uint64(len(c.Config)), 8)
+	write(c.Config)
+
+	names := c.TensorNames()
+	writeUint(uint64(len(names)), 8)
+	for _, name := range names {
+		data, e := marshalTensor(c.Tensors[name])
+		if e != nil {
+			return fmt.Errorf("checkpoint: encoding tensor %q: %w", name, e)
+		}
+		writeUint(uint64(len(name)), 8)
+		write([]byte(name))
+		writeUint(uint64(len(data)), 8)
+		write(data)
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// WriteFile writes the checkpoint to the named file.
+func (c *Checkpoint) WriteFile(filename string) (err error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := f.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	return c.Write(f)
+}
+
+// TensorNames returns the names of the tensors in ascending order.
+func (c *Checkpoint) TensorNames() []string {
+	names := make([]string, 0, len(c.Tensors))
+	for name := range c.Tensors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Read reads a checkpoint from r.
+func Read(r io.Reader) (*Checkpoint, error) {
+	cr := reader{r: bufio.NewReader(r)}
+
+	if string(cr.read(uint64(len(magic)))) != magic && cr.err == nil {
+		return nil, errors.New("checkpoint: not a checkpoint")
+	}
+	if v := cr.uint(4); v > FormatVersion && cr.err == nil {
+		return nil, fmt.Errorf("checkpoint: unsupported format version %d", v)
+	}
+
+	c := &Checkpoint{Tensors: make(map[string]mat.Matrix)}
+	if hdata := cr.section(); cr.err == nil {
+		if err := json.Unmarshal(hdata, &c.Header); err != nil {
+			return nil, fmt.Errorf("checkpoint: decoding header: %w", err)
+		}
+	}
+	if config := cr.section(); len(config) > 0 {
+		c.Config = config
+	}
+
+	count := cr.uint(8)
+	for i := uint64(0); i < count && cr.err == nil; i++ {
+		name := string(cr.section())
+		data := cr.read(cr.uint(8))
+		if cr.err != nil {
+			break
+		}
+		m, err := decodeTensor(data)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint: decoding tensor %q: %w", name, err)
+		}
+		c.Tensors[name] = m
+	}
+	if cr.err != nil {
+		if cr.err == io.EOF {
+			cr.err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("checkpoint: %w", cr.err)
+	}
+	return c, nil
+}
+
+// ReadFile reads the checkpoint from the named file.
+func ReadFile(filename string) (_ *Checkpoint, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := f.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	return Read(f)
+}
+
+// reader reads the blocks of a checkpoint, retaining the first error.
+type reader struct {
+	r   io.Reader
+	err error
+}
+
+func (cr *reader) read(n uint64) []byte {
+	if cr.err != nil {
+		return nil
+	}
+	// The buffer grows along with the data actually read, so that a
+	// corrupted size does not cause a huge allocation.
+	var buf bytes.Buffer
+	buf.Grow(int(min(n, 1<<20)))
+	var read int64
+	read, cr.err = io.CopyN(&buf, cr.r, int64(n))
+	if cr.err == nil && uint64(read) != n {
+		cr.err = io.ErrUnexpectedEOF
+	}
+	return buf.Bytes()
+}
+
+func (cr *reader) uint(size int) uint64 {
+	var buf [8]byte
+	copy(buf[:], cr.read(uint64(size)))
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// section reads a block prefixed by its size, which must not exceed
+// maxSectionSize.
+func (cr *reader) section() []byte {
+	n := cr.uint(8)
+	if n > maxSectionSize && cr.err == nil {
+		cr.err = fmt.Errorf("invalid section size %d", n)
+	}
+	return cr.read(n)
+}
+
+// marshalTensor encodes a matrix with its flatbuffers encoding.
+func marshalTensor(m mat.Matrix) ([]byte, error) {
+	bm, ok := m.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("unsupported matrix type %T", m)
+	}
+	return bm.MarshalBinary()
+}
+
+// decodeTensor decodes a matrix from its flatbuffers encoding. The dtype is
+// the first field of both the float32 and the float64 tables.
+func decodeTensor(data []byte) (_ mat.Matrix, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("corrupted data: %v", r)
+		}
+	}()
+	var m mat.Matrix
+	switch dtype := dense.GetRootAsDenseFloat32(data, 0).Dtype(); dtype {
+	case dense.DTypeFloat32:
+		m = new(mat.Dense[float32])
+	case dense.DTypeFloat64:
+		m = new(mat.Dense[float64])
+	default:
+		return nil, fmt.Errorf("unexpected dtype %d", dtype)
+	}
+	if err := m.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// dataType returns the data type shared by all the tensors.
+func dataType(tensors map[string]mat.Matrix) string {
+	dtype := ""
+	for _, t := range tensors {
+		d := Float64
+		if t.Data().BitSize() == 32 {
+			d = Float32
+		}
+		if dtype != "" && dtype != d {
+			return Mixed
+		}
+		dtype = d
+	}
+	return dtype
+}
+
+// spagoVersion returns the version of the spaGO module in use, as recorded
+// in the build information of the binary.
+func spagoVersion() string {
+	const path = "github.com/nlpodyssey/spago"
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(unknown)"
+	}
+	if info.Main.Path == path {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			return dep.Version
+		}
+	}
+	return "(unknown)"
+}
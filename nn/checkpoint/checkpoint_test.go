@@ -0,0 +1,165 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checkpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/linear"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	In, Out int
+}
+
+type testModel struct {
+	nn.Module
+	Layers []*linear.Model
+	Scale  *nn.Buffer
+}
+
+func newTestModel[T float.DType](values ...T) *testModel {
+	m := &testModel{
+		Layers: []*linear.Model{linear.New[T](2, 1), linear.New[T](1, 1)},
+		Scale:  nn.Buf(mat.NewDense[T](mat.WithBacking([]T{0}))),
+	}
+	i := 0
+	for _, p := range nn.NamedParameters(m) {
+		data := make([]T, p.Param.Size())
+		for j := range data {
+			data[j] = values[i%len(values)]
+			i++
+		}
+		p.Param.SetData(mat.NewDense[T](mat.WithBacking(data)).Data())
+	}
+	m.Scale.Value().(mat.Matrix).SetData(mat.NewDense[T](mat.WithBacking([]T{values[0]})).Data())
+	return m
+}
+
+func TestCheckpoint_RoundTrip(t *testing.T) {
+	src := newTestModel[float32](1.5, -2, 3.25)
+	c, err := FromModel(src, testConfig{In: 2, Out: 1})
+	require.NoError(t, err)
+	c.Header.Model = "test"
+	c.Header.ModelVersion = 3
+	c.Header.Metadata = map[string]string{"epoch": "7"}
+
+	filename := filepath.Join(t.TempDir(), "model.ckpt")
+	require.NoError(t, c.WriteFile(filename))
+
+	c2, err := ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, FormatVersion, c2.Header.FormatVersion)
+	assert.Equal(t, Float32, c2.Header.DType)
+	assert.NotEmpty(t, c2.Header.SpagoVersion)
+	assert.Equal(t, "test", c2.Header.Model)
+	assert.Equal(t, 3, c2.Header.ModelVersion)
+	assert.Equal(t, map[string]string{"epoch": "7"}, c2.Header.Metadata)
+	assert.Equal(t, []string{"Layers.0.B", "Layers.0.W", "Layers.1.B", "Layers.1.W", "Scale"}, c2.TensorNames())
+
+	var config testConfig
+	require.NoError(t, c2.DecodeConfig(&config))
+	assert.Equal(t, testConfig{In: 2, Out: 1}, config)
+
+	dst := newTestModel[float32](0)
+	report, err := c2.Apply(dst, true)
+	require.NoError(t, err)
+	assert.True(t, report.Complete())
+	assert.Equal(t, nn.StateDict(src), nn.StateDict(dst))
+}
+
+func TestCheckpoint_Float64(t *testing.T) {
+	src := newTestModel[float64](0.1, 0.2)
+	c, err := FromModel(src, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Float64, c.Header.DType)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.Write(&buf))
+	c2, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Nil(t, c2.Config)
+	assert.Error(t, c2.DecodeConfig(&testConfig{}))
+	assert.Equal(t, []float64{0.1, 0.2}, c2.Tensors["Layers.0.W"].Data().F64())
+
+	// the values are converted to the data type of the model
+	dst := newTestModel[float32](0)
+	_, err = c2.Apply(dst, true)
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2}, dst.Layers[0].W.Data().F32())
+}
+
+func TestRead_Errors(t *testing.T) {
+	c, err := FromModel(newTestModel[float32](1), nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, c.Write(&buf))
+	data := buf.Bytes()
+
+	_, err = Read(bytes.NewReader([]byte("not a checkpoint at all")))
+	assert.EqualError(t, err, "checkpoint: not a checkpoint")
+
+	future := append([]byte(nil), data...)
+	future[len(magic)] = FormatVersion + 1
+	_, err = Read(bytes.NewReader(future))
+	assert.EqualError(t, err, "checkpoint: unsupported format version 2")
+
+	_, err = Read(bytes.NewReader(data[:len(data)-3]))
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), err)
+}
+
+func TestCheckpoint_Upgrade(t *testing.T) {
+	// version 0 named the layers "FFN"; version 1 also added the Bias config
+	RegisterMigration("upgrade-test", 0, func(c *Checkpoint) error {
+		return c.RenameTensors("FFN", "Layers")
+	})
+	RegisterMigration("upgrade-test", 1, func(c *Checkpoint) error {
+		var config map[string]any
+		if err := c.DecodeConfig(&config); err != nil {
+			return err
+		}
+		config["Bias"] = true
+		data, err := json.Marshal(config)
+		c.Config = data
+		return err
+	})
+	assert.Panics(t, func() { RegisterMigration("upgrade-test", 1, nil) })
+
+	c, err := FromModel(newTestModel[float32](1), map[string]any{"In": 2})
+	require.NoError(t, err)
+	require.NoError(t, c.RenameTensors("Layers", "FFN"))
+	assert.Equal(t, []string{"FFN.0.B", "FFN.0.W", "FFN.1.B", "FFN.1.W", "Scale"}, c.TensorNames())
+	c.Header.Model = "upgrade-test"
+
+	require.NoError(t, c.Upgrade(2))
+	assert.Equal(t, 2, c.Header.ModelVersion)
+	assert.Equal(t, []string{"Layers.0.B", "Layers.0.W", "Layers.1.B", "Layers.1.W", "Scale"}, c.TensorNames())
+	assert.JSONEq(t, `{"In": 2, "Bias": true}`, string(c.Config))
+
+	assert.NoError(t, c.Upgrade(2))
+	assert.EqualError(t, c.Upgrade(3), `checkpoint: no migration of model "upgrade-test" from version 2`)
+	assert.EqualError(t, c.Upgrade(1), `checkpoint: cannot downgrade model "upgrade-test" from version 2 to 1`)
+}
+
+func TestCheckpoint_RenameTensors(t *testing.T) {
+	c, err := FromModel(newTestModel[float32](1), nil)
+	require.NoError(t, err)
+	assert.Error(t, c.RenameTensors("Layers.0", "Layers.1"))
+	assert.Len(t, c.Tensors, 5)
+
+	require.NoError(t, c.RenameTensors("Scale", "Layers.2.Scale"))
+	assert.Contains(t, c.Tensors, "Layers.2.Scale")
+	assert.NotContains(t, c.Tensors, "Scale")
+}
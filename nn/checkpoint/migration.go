@@ -0,0 +1,93 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checkpoint
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Migration upgrades a checkpoint from a version of the model to the next
+// one, e.g. renaming the tensors of a layer whose struct changed, or
+// setting the default value of a new configuration field.
+type Migration func(c *Checkpoint) error
+
+var (
+	migrationsMu sync.RWMutex
+	migrations   = make(map[string]map[int]Migration)
+)
+
+// RegisterMigration registers the migration upgrading the checkpoints of the
+// given kind of model (see Header.Model) from version from to version
+// from+1. It is typically called from the init function of the package
+// defining the model. It panics if a migration for the same model and
+// version is already registered.
+func RegisterMigration(model string, from int, m Migration) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	if migrations[model] == nil {
+		migrations[model] = make(map[int]Migration)
+	}
+	if _, ok := migrations[model][from]; ok {
+		panic(fmt.Sprintf("checkpoint: migration of model %q from version %d already registered", model, from))
+	}
+	migrations[model][from] = m
+}
+
+// Upgrade applies in sequence the registered migrations bringing the
+// checkpoint from its model version to the given one, which becomes the
+// new Header.ModelVersion. It returns an error if a migration is missing,
+// or if the checkpoint is more recent than the requested version.
+func (c *Checkpoint) Upgrade(version int) error {
+	if c.Header.ModelVersion > version {
+		return fmt.Errorf("checkpoint: cannot downgrade model %q from version %d to %d",
+			c.Header.Model, c.Header.ModelVersion, version)
+	}
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+	for v := c.Header.ModelVersion; v < version; v++ {
+		m, ok := migrations[c.Header.Model][v]
+		if !ok {
+			return fmt.Errorf("checkpoint: no migration of model %q from version %d", c.Header.Model, v)
+		}
+		if err := m(c); err != nil {
+			return fmt.Errorf("checkpoint: migrating model %q from version %d: %w", c.Header.Model, v, err)
+		}
+		c.Header.ModelVersion = v + 1
+	}
+	return nil
+}
+
+// RenameTensors renames the tensor with path oldPrefix, and all the tensors
+// below it, replacing oldPrefix with newPrefix (e.g. renaming "FFN" to
+// "FeedForward" turns "FFN.W" into "FeedForward.W"). It returns an error if
+// a renamed tensor would replace an existing one.
+func (c *Checkpoint) RenameTensors(oldPrefix, newPrefix string) error {
+	renamed := make(map[string]string)
+	for name := range c.Tensors {
+		if name == oldPrefix || strings.HasPrefix(name, oldPrefix+".") {
+			renamed[name] = newPrefix + strings.TrimPrefix(name, oldPrefix)
+		}
+	}
+	for oldName, newName := range renamed {
+		if _, exists := c.Tensors[newName]; exists {
+			if _, moved := renamed[newName]; !moved {
+				return fmt.Errorf("checkpoint: cannot rename %q to %q: tensor already exists", oldName, newName)
+			}
+		}
+	}
+	values := make(map[string]mat.Matrix, len(renamed))
+	for oldName := range renamed {
+		values[oldName] = c.Tensors[oldName]
+		delete(c.Tensors, oldName)
+	}
+	for oldName, newName := range renamed {
+		c.Tensors[newName] = values[oldName]
+	}
+	return nil
+}
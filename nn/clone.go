@@ -0,0 +1,187 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"reflect"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// CloneHook is implemented by models holding unexported state that must be
+// reset on the copy produced by Clone (e.g. the bookkeeping of gradients).
+// AfterClone is called on the copy, once all its fields have been set.
+type CloneHook interface {
+	AfterClone()
+}
+
+// Clone returns a deep copy of the model.
+//
+// Every Param and Buffer gets a new value, with the same data; the gradients
+// and the optimizer state of the parameters are not copied. The sharing
+// structure of the model is preserved: a Param, Buffer or sub-model which is
+// reachable from more than one field (e.g. tied weights, or an embedding
+// wrapped by embedding.Shared) is copied once, and shared by the clone in
+// the same way.
+//
+// Only exported fields are deep-copied; unexported ones are copied as they
+// are, and models can implement CloneHook to fix them up.
+func Clone[M Model](m M) M {
+	c := cloner{memo: make(map[cloneKey]reflect.Value)}
+	return c.clone(reflect.ValueOf(m)).Interface().(M)
+}
+
+// ConvertDType converts, in place, the values of all the parameters and
+// buffers of the model to the data type T. Values already of type T are
+// left untouched. Converted parameters keep their requires-grad flag, while
+// their optimizer state is discarded.
+func ConvertDType[T float.DType](m Model) {
+	for _, p := range NamedParameters(m) {
+		if _, ok := p.Param.Matrix.(*mat.Dense[T]); ok {
+			continue
+		}
+		requiresGrad := p.Param.RequiresGrad()
+		p.Param.ReplaceValue(convertDense[T](p.Param.Matrix))
+		p.Param.SetRequiresGrad(requiresGrad)
+	}
+	for _, b := range NamedBuffers(m) {
+		v := b.Buffer.Value().(mat.Matrix)
+		if _, ok := v.(*mat.Dense[T]); ok {
+			continue
+		}
+		*b.Buffer = *Buf(convertDense[T](v))
+	}
+}
+
+func convertDense[T float.DType](m mat.Matrix) *mat.Dense[T] {
+	return mat.NewDense[T](
+		mat.WithShape(append([]int(nil), m.Shape()...)...),
+		mat.WithBacking(float.SliceValueOf[T](m.Data())),
+	)
+}
+
+// cloneKey identifies a pointer already visited by a cloner. The type
+// distinguishes a struct from its first field, which share the address.
+type cloneKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// cloner performs the deep copy of Clone, memoizing the copy of each
+// pointer to preserve the sharing structure (and to handle cycles).
+type cloner struct {
+	memo map[cloneKey]reflect.Value
+	// share makes the copy reuse the parameters, buffers and matrices of the
+	// source, instead of copying them.
+	share bool
+	// wrap, if not nil, can replace the copy dst of the value src held by an
+	// interface of type iface.
+	wrap func(iface reflect.Type, src, dst reflect.Value) reflect.Value
+}
+
+var (
+	paramType  = reflect.TypeOf(&Param{})
+	bufferType = reflect.TypeOf(&Buffer{})
+	matrixType = reflect.TypeOf((*mat.Matrix)(nil)).Elem()
+)
+
+func (c cloner) clone(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		return c.clonePtr(v)
+	case reflect.Interface:
+		out := reflect.New(v.Type()).Elem()
+		if !v.IsNil() {
+			dst := c.clone(v.Elem())
+			if c.wrap != nil {
+				dst = c.wrap(v.Type(), v.Elem(), dst)
+			}
+			out.Set(dst)
+		}
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		c.copyStruct(out, v)
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), c.clone(iter.Value()))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func (c cloner) clonePtr(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return v
+	}
+	key := cloneKey{ptr: v.Pointer(), typ: v.Type()}
+	if out, ok := c.memo[key]; ok {
+		return out
+	}
+	if c.share && (v.Type() == paramType || v.Type() == bufferType || v.Type().Implements(matrixType)) {
+		return v
+	}
+
+	var out reflect.Value
+	switch {
+	case v.Type() == paramType:
+		p := v.Interface().(*Param)
+		out = reflect.ValueOf(&Param{Matrix: p.Matrix.Clone(), frozen: p.frozen})
+		out.Interface().(*Param).SetRequiresGrad(p.RequiresGrad())
+	case v.Type() == bufferType:
+		b := v.Interface().(*Buffer)
+		out = reflect.ValueOf(Buf(b.Value().(mat.Matrix).Clone()))
+	case v.Type().Implements(matrixType):
+		out = reflect.ValueOf(v.Interface().(mat.Matrix).Clone())
+	case v.Elem().Kind() == reflect.Struct:
+		out = reflect.New(v.Type().Elem())
+		c.memo[key] = out // before recursion, for cycles
+		c.copyStruct(out.Elem(), v.Elem())
+		if h, ok := out.Interface().(CloneHook); ok {
+			h.AfterClone()
+		}
+	default:
+		out = reflect.New(v.Type().Elem())
+		c.memo[key] = out
+		out.Elem().Set(c.clone(v.Elem()))
+	}
+	c.memo[key] = out
+	return out
+}
+
+// copyStruct makes dst a copy of src, deep-copying the exported fields.
+func (c cloner) copyStruct(dst, src reflect.Value) {
+	dst.Set(src)
+	t := src.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			dst.Field(i).Set(c.clone(src.Field(i)))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	src := newStateRoot()
+	src.Heads["a"].W.WithGrad(false)
+	src.Encoder.Layers[1].W.AccGrad(mat.NewDense[float32](mat.WithShape(2, 2)))
+
+	c := Clone(src)
+	require.NotSame(t, src, c)
+	assert.Equal(t, StateDict(src), StateDict(c))
+
+	for i, p := range NamedParameters(c) {
+		assert.NotSame(t, NamedParameters(src)[i].Param, p.Param)
+		assert.Nil(t, p.Param.Grad())
+	}
+	for i, b := range NamedBuffers(c) {
+		assert.NotSame(t, NamedBuffers(src)[i].Buffer, b.Buffer)
+	}
+	assert.False(t, c.Heads["a"].W.RequiresGrad())
+	assert.True(t, c.Heads["b"].W.RequiresGrad())
+
+	// tied weights stay tied
+	assert.Same(t, c.Encoder.Layers[0].W, c.Shared)
+
+	// the clone is independent of the source
+	c.Shared.SetData(mat.NewDense[float32](mat.WithShape(2, 2)).Data())
+	assert.Equal(t, []float32{1, 2, 3, 1}, src.Shared.Data().F32())
+	assert.Equal(t, []float32{0, 0, 0, 0}, c.Encoder.Layers[0].W.Data().F32())
+}
+
+func TestConvertDType(t *testing.T) {
+	m := newStateRoot()
+	m.Heads["a"].W.WithGrad(false)
+	ConvertDType[float64](m)
+
+	for _, p := range NamedParameters(m) {
+		require.IsType(t, &mat.Dense[float64]{}, p.Param.Matrix, p.Name)
+	}
+	for _, b := range NamedBuffers(m) {
+		require.IsType(t, &mat.Dense[float64]{}, b.Buffer.Value(), b.Name)
+	}
+	assert.Equal(t, []float64{1, 2, 3, 5}, m.Heads["a"].W.Data().F64())
+	assert.Equal(t, []int{2, 2}, m.Heads["a"].W.Shape())
+	assert.Equal(t, []float64{4, 0}, m.Heads["b"].Mean.Value().Data().F64())
+	assert.False(t, m.Heads["a"].W.RequiresGrad())
+	assert.True(t, m.Heads["b"].W.RequiresGrad())
+	assert.Same(t, m.Encoder.Layers[0].W, m.Shared)
+}
@@ -9,15 +9,25 @@ import (
 
 	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
 	"github.com/nlpodyssey/spago/nn"
 )
 
-var _ nn.Model = &Model{}
+var (
+	_ nn.Model        = &Model{}
+	_ nn.ModeSetter   = &Model{}
+	_ nn.StreamSetter = &Model{}
+)
 
 // Model is a parameter-free model.
 type Model struct {
 	nn.Module
 	P float64
+	// Stream, if set, is the stream the masks are drawn from, instead of the
+	// global generator of package ag (see SetStream).
+	Stream *rand.Stream
+
+	mode nn.Mode
 }
 
 func init() {
@@ -31,10 +41,36 @@ func New(p float64) *Model {
 	}
 }
 
+// SetStream makes the model draw its masks from s: the i-th input of
+// Forward always gets the mask of s.SplitN(i). The masks are therefore the
+// same at every call, until a new stream is set, typically with
+// nn.SetStreams at each training step.
+func (m *Model) SetStream(s *rand.Stream) {
+	m.Stream = s
+}
+
+// SetMode sets the running mode of the model.
+func (m *Model) SetMode(mode nn.Mode) {
+	m.mode = mode
+}
+
+// Mode returns the running mode of the model.
+func (m *Model) Mode() nn.Mode {
+	return m.mode
+}
+
 // Forward performs the forward step for each input node and returns the result.
+// In nn.EvalMode the inputs are returned unchanged.
 func (m *Model) Forward(xs ...mat.Tensor) []mat.Tensor {
-	if m.P == 0 {
+	if m.P == 0 || m.mode == nn.EvalMode {
 		return xs
 	}
-	return ag.Map(ag.DropoutFunc(m.P), xs)
+	if m.Stream == nil {
+		return ag.Map(ag.DropoutFunc(m.P), xs)
+	}
+	ys := make([]mat.Tensor, len(xs))
+	for i, x := range xs {
+		ys[i] = ag.DropoutWithStream(x, m.P, m.Stream.SplitN(uint64(i)))
+	}
+	return ys
 }
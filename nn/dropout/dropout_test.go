@@ -0,0 +1,62 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dropout
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModel_Forward(t *testing.T) {
+	x := mat.NewDense[float32](mat.WithShape(100), mat.WithBacking(mat.CreateInitializedSlice[float32](100, 1)))
+	m := New(0.5)
+
+	y := m.Forward(x)[0]
+	assert.NotEqual(t, x.Data(), y.Value().Data())
+
+	nn.Eval(m)
+	assert.Same(t, x, m.Forward(x)[0])
+
+	nn.Train(m)
+	assert.NotEqual(t, x.Data(), m.Forward(x)[0].Value().Data())
+}
+
+func TestModel_SetStream(t *testing.T) {
+	x := mat.NewDense[float32](mat.WithShape(100), mat.WithBacking(mat.CreateInitializedSlice[float32](100, 1)))
+	m1, m2 := New(0.5), New(0.5)
+	nn.SetStreams(m1, rand.NewStream(1), 0)
+	nn.SetStreams(m2, rand.NewStream(1), 0)
+
+	y1 := m1.Forward(x, x)
+	y2 := m2.Forward(x, x)
+	assert.Equal(t, y1[0].Value().Data(), y2[0].Value().Data())
+	assert.Equal(t, y1[1].Value().Data(), y2[1].Value().Data())
+	assert.NotEqual(t, y1[0].Value().Data(), y1[1].Value().Data())
+	assert.Equal(t, y1[0].Value().Data(), m1.Forward(x)[0].Value().Data())
+
+	nn.SetStreams(m1, rand.NewStream(1), 1)
+	assert.NotEqual(t, y1[0].Value().Data(), m1.Forward(x)[0].Value().Data())
+}
+
+func TestModel_Gob(t *testing.T) {
+	x := mat.NewDense[float32](mat.WithShape(100), mat.WithBacking(mat.CreateInitializedSlice[float32](100, 1)))
+	m := New(0.5)
+	nn.SetStreams(m, rand.NewStream(1), 3)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(m))
+	var loaded *Model
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&loaded))
+	assert.Equal(t, m.Stream, loaded.Stream)
+	assert.Equal(t, m.Forward(x)[0].Value().Data(), loaded.Forward(x)[0].Value().Data())
+	assert.Equal(t, m.Stream, nn.Clone(m).Stream)
+}
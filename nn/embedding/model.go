@@ -14,7 +14,11 @@ import (
 	"github.com/nlpodyssey/spago/nn"
 )
 
-var _ nn.ParamsTraverser = &Model{}
+var (
+	_ nn.ParamsTraverser = &Model{}
+	_ nn.CloneHook       = &Model{}
+	_ nn.ZeroGradHook    = &Model{}
+)
 
 // Model implements a simple lookup table that stores fixed-size embeddings
 // for a predefined dictionary. It is commonly used to store and retrieve word
@@ -54,6 +58,25 @@ func (m *Model) TraverseParams(callback func(param *nn.Param)) {
 	}
 }
 
+// AfterClone resets the gradients bookkeeping of a copy made by nn.Clone,
+// which does not copy the gradients.
+func (m *Model) AfterClone() {
+	m.mu = sync.Mutex{}
+	m.embedGradIdx = make(map[int]struct{})
+}
+
+// AfterZeroGrad resets the gradients bookkeeping after nn.ZeroGrad, which
+// zeroes the gradients of the embeddings without going through Embedding.
+func (m *Model) AfterZeroGrad() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for idx := range m.embedGradIdx {
+		if !m.Weights[idx].HasGrad() {
+			delete(m.embedGradIdx, idx)
+		}
+	}
+}
+
 func (m *Model) Embedding(idx int) (*Embedding, error) {
 	if idx < 0 || idx >= m.Size {
 		return nil, nn.ErrInvalidIndex
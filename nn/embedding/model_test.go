@@ -100,3 +100,54 @@ func TestModel_TraverseParams(t *testing.T) {
 		require.Len(t, embeddingsWithGrad, 0)
 	})
 }
+
+func TestClone_Shared(t *testing.T) {
+	type T = float32
+	type model struct {
+		nn.Module
+		Encoder *embedding.Model
+		Decoder embedding.Shared
+	}
+	emb := embedding.New[T](3, 2)
+	src := &model{Encoder: emb, Decoder: embedding.Shared{Model: emb}}
+
+	c := nn.Clone(src)
+	require.NotSame(t, src.Encoder, c.Encoder)
+	assert.Same(t, c.Encoder, c.Decoder.Model)
+	assert.Len(t, c.Encoder.Weights, 3)
+	assert.NotSame(t, src.Encoder.Weights[0], c.Encoder.Weights[0])
+
+	e, err := c.Decoder.Embedding(1)
+	require.NoError(t, err)
+	e.AccGrad(mat.NewDense[T](mat.WithBacking([]T{1, 2})))
+	assert.Equal(t, 1, c.Encoder.CountEmbedWithGrad())
+	assert.Equal(t, 0, src.Encoder.CountEmbedWithGrad())
+}
+
+func TestModel_ZeroGrad(t *testing.T) {
+	type T = float32
+	type model struct {
+		nn.Module
+		Encoder *embedding.Model
+		Decoder embedding.Shared
+	}
+	emb := embedding.New[T](3, 2)
+	m := &model{Encoder: emb, Decoder: embedding.Shared{Model: emb}}
+
+	for _, e := range emb.MustEncode([]int{0, 2}) {
+		e.AccGrad(mat.NewDense[T](mat.WithBacking([]T{1, 2})))
+	}
+	require.Equal(t, 2, emb.CountEmbedWithGrad())
+
+	nn.ZeroGrad(m)
+	assert.Equal(t, 0, emb.CountEmbedWithGrad())
+	assert.False(t, emb.Weights[0].HasGrad())
+	assert.False(t, emb.Weights[2].HasGrad())
+
+	// the embedding model itself can be the root model
+	for _, e := range emb.MustEncode([]int{1}) {
+		e.AccGrad(mat.NewDense[T](mat.WithBacking([]T{1, 2})))
+	}
+	nn.ZeroGrad(emb)
+	assert.Equal(t, 0, emb.CountEmbedWithGrad())
+}
@@ -0,0 +1,235 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Freeze disables the gradients of the parameters of the model selected by
+// the patterns, so that they are neither updated by the optimizers nor
+// considered by the gradient clippers, and clears their gradients, which
+// would be stale once they are unfrozen. With no patterns, the whole model
+// is frozen. It returns the number of parameters matched.
+//
+// A pattern is a dotted path, as reported by ForEachNamedParam, whose
+// segments are matched with path.Match (e.g. "Layers.*.W"); the special
+// segment "**" matches any number of segments. A pattern selects both the
+// parameters at that path and all the parameters below it, so that "Encoder"
+// selects every parameter of the Encoder sub-model.
+//
+// It panics if a pattern is malformed.
+func Freeze(m Model, patterns ...string) int {
+	return setRequiresGrad(m, false, matchPatterns(patterns))
+}
+
+// Unfreeze enables the gradients of the parameters of the model selected by
+// the patterns, undoing Freeze. See Freeze for the syntax of the patterns.
+func Unfreeze(m Model, patterns ...string) int {
+	return setRequiresGrad(m, true, matchPatterns(patterns))
+}
+
+// FreezeType disables the gradients of the parameters of all the sub-models
+// of type M, including m itself. It returns the number of parameters matched.
+func FreezeType[M Model](m Model) int {
+	return setRequiresGrad(m, false, matchModelType[M](m))
+}
+
+// UnfreezeType enables the gradients of the parameters of all the sub-models
+// of type M, including m itself, undoing FreezeType.
+func UnfreezeType[M Model](m Model) int {
+	return setRequiresGrad(m, true, matchModelType[M](m))
+}
+
+// SelectParameters returns the parameters of the model selected by the
+// patterns, with the syntax of Freeze, in traversal order. With no
+// patterns, all the parameters are returned. A parameter shared by several
+// sub-models is selected by any of its paths, and returned once, under the
+// first matching path.
+func SelectParameters(m Model, patterns ...string) []NamedParam {
+	match := matchPatterns(patterns)
+	var params []NamedParam
+	seen := make(map[*Param]bool)
+	ForEachNamedParam(m, func(name string, p *Param) {
+		if !seen[p] && match(name) {
+			seen[p] = true
+			params = append(params, NamedParam{Name: name, Param: p})
+		}
+	})
+	return params
+}
+
+// IsFrozen reports whether the parameter has been frozen with Freeze or
+// FreezeType, and not unfrozen since. A parameter which does not require
+// gradients for other reasons (e.g. created with WithGrad(false), or as a
+// literal &Param{}) is not frozen. The frozen state is not serialized.
+func IsFrozen(p *Param) bool {
+	return p.frozen
+}
+
+// UnfreezeStage is a stage of an UnfreezeSchedule.
+type UnfreezeStage struct {
+	// Step is the first step (e.g. epoch) of the stage.
+	Step int
+	// Patterns select the parameters to unfreeze, as in Unfreeze.
+	Patterns []string
+}
+
+// UnfreezeSchedule describes the gradual unfreezing of a model, commonly
+// used for fine-tuning: the model starts frozen, and the parameters of each
+// stage become trainable from its step onwards.
+type UnfreezeSchedule []UnfreezeStage
+
+// Apply makes trainable the parameters of all the stages reached at the
+// given step, and freezes all the others. Only the parameters which leave
+// the trainable set are frozen, so the gradients of the parameters which
+// stay trainable (e.g. accumulated over micro-batches) are preserved.
+// Calling it again with the same step has no further effect, so it can be
+// called at the beginning of every epoch.
+func (s UnfreezeSchedule) Apply(m Model, step int) {
+	var matches []func(name string) bool
+	for _, stage := range s {
+		if stage.Step <= step {
+			matches = append(matches, matchPatterns(stage.Patterns))
+		}
+	}
+	trainable := make(map[*Param]bool)
+	ForEachNamedParam(m, func(name string, p *Param) {
+		for _, match := range matches {
+			if match(name) {
+				trainable[p] = true
+				return
+			}
+		}
+	})
+	seen := make(map[*Param]bool)
+	ForEachNamedParam(m, func(_ string, p *Param) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		if trainable[p] {
+			setFrozen(p, false)
+		} else if !p.frozen {
+			setFrozen(p, true)
+		}
+	})
+}
+
+func setRequiresGrad(m Model, value bool, match func(name string) bool) int {
+	seen := make(map[*Param]bool)
+	ForEachNamedParam(m, func(name string, p *Param) {
+		if !seen[p] && match(name) {
+			seen[p] = true
+			setFrozen(p, !value)
+		}
+	})
+	return len(seen)
+}
+
+// setFrozen freezes or unfreezes the parameter, clearing its gradients when
+// it is frozen.
+func setFrozen(p *Param, frozen bool) {
+	p.SetRequiresGrad(!frozen)
+	p.frozen = frozen
+	if frozen {
+		p.ZeroGrad()
+	}
+}
+
+// matchPatterns returns a function reporting whether a parameter path is
+// selected by any of the patterns. With no patterns, every path is selected.
+func matchPatterns(patterns []string) func(name string) bool {
+	if len(patterns) == 0 {
+		return func(string) bool { return true }
+	}
+	split := make([][]string, len(patterns))
+	for i, p := range patterns {
+		split[i] = splitPath(p)
+		for _, seg := range split[i] {
+			if _, err := path.Match(seg, ""); err != nil {
+				panic(fmt.Sprintf("nn: invalid pattern %q: %v", p, err))
+			}
+		}
+	}
+	return func(name string) bool {
+		segments := splitPath(name)
+		for _, p := range split {
+			if matchPrefix(p, segments) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matchModelType returns a function reporting whether a parameter path lies
+// within a sub-model of type M.
+func matchModelType[M Model](m Model) func(name string) bool {
+	var prefixes [][]string
+	if _, ok := m.(M); ok {
+		prefixes = append(prefixes, nil)
+	}
+	paramsTraversal{
+		modelsFunc: func(name string, model Model) {
+			if _, ok := model.(M); ok {
+				prefixes = append(prefixes, splitPath(name))
+			}
+		},
+		exploreSubModels: true,
+		bypassTraversers: true,
+	}.walk(m, "")
+
+	return func(name string) bool {
+		segments := splitPath(name)
+		for _, p := range prefixes {
+			if len(p) <= len(segments) && equalSegments(p, segments[:len(p)]) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matchPrefix reports whether the pattern matches the whole path or any
+// leading part of it.
+func matchPrefix(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPrefix(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchPrefix(pattern[1:], segments[1:])
+}
+
+func splitPath(name string) []string {
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, ".")
+}
+
+func equalSegments(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
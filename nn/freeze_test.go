@@ -0,0 +1,130 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+)
+
+func frozenNames(m Model) []string {
+	var names []string
+	for _, p := range NamedParameters(m) {
+		if IsFrozen(p.Param) {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+func TestFreeze(t *testing.T) {
+	m := newStateRoot()
+	assert.Empty(t, frozenNames(m))
+
+	assert.Equal(t, 2, Freeze(m, "Encoder"))
+	assert.Equal(t, []string{"Encoder.Layers.0.W", "Encoder.Layers.1.W"}, frozenNames(m))
+
+	assert.Equal(t, 1, Unfreeze(m, "Encoder.Layers.1"))
+	assert.Equal(t, []string{"Encoder.Layers.0.W"}, frozenNames(m))
+
+	Unfreeze(m)
+	assert.Equal(t, 2, Freeze(m, "Heads.*.W"))
+	assert.Equal(t, []string{"Heads.a.W", "Heads.b.W"}, frozenNames(m))
+
+	Unfreeze(m)
+	assert.Equal(t, 4, Freeze(m, "**.W"))
+	assert.Equal(t, 0, Freeze(m, "Decoder"))
+
+	// a shared parameter is selected by any of its paths
+	Unfreeze(m)
+	assert.Equal(t, 1, Freeze(m, "Shared"))
+	assert.Equal(t, []string{"Encoder.Layers.0.W"}, frozenNames(m))
+
+	assert.Panics(t, func() { Freeze(m, "Heads.[a") })
+}
+
+func TestFreeze_Gradients(t *testing.T) {
+	m := newStateRoot()
+	w := m.Heads["a"].W
+	w.AccGrad(mat.NewDense[float32](mat.WithShape(w.Shape()...)))
+
+	Freeze(m, "Heads.a")
+	assert.True(t, IsFrozen(w))
+	assert.False(t, w.RequiresGrad())
+	assert.False(t, w.HasGrad(), "the stale gradients are cleared")
+
+	Unfreeze(m)
+	assert.False(t, IsFrozen(w))
+	assert.True(t, w.RequiresGrad())
+
+	assert.False(t, IsFrozen(&Param{Matrix: mat.NewDense[float32](mat.WithShape(2))}))
+	assert.False(t, IsFrozen(NewParam(mat.NewDense[float32](mat.WithShape(2))).WithGrad(false)))
+}
+
+func TestFreezeType(t *testing.T) {
+	m := newStateRoot()
+	assert.Equal(t, 4, FreezeType[*stateLeaf](m))
+	assert.Len(t, frozenNames(m), 4)
+
+	assert.Equal(t, 2, UnfreezeType[*stateEncoder](m))
+	assert.Equal(t, []string{"Heads.a.W", "Heads.b.W"}, frozenNames(m))
+
+	assert.Equal(t, 4, UnfreezeType[*stateRoot](m))
+	assert.Empty(t, frozenNames(m))
+}
+
+func TestUnfreezeSchedule(t *testing.T) {
+	m := newStateRoot()
+	s := UnfreezeSchedule{
+		{Step: 0, Patterns: []string{"Heads"}},
+		{Step: 2, Patterns: []string{"Encoder.Layers.1"}},
+		{Step: 3, Patterns: []string{"Encoder.Layers.0"}},
+	}
+
+	s.Apply(m, 0)
+	assert.Equal(t, []string{"Encoder.Layers.0.W", "Encoder.Layers.1.W"}, frozenNames(m))
+	s.Apply(m, 1)
+	assert.Equal(t, []string{"Encoder.Layers.0.W", "Encoder.Layers.1.W"}, frozenNames(m))
+	s.Apply(m, 2)
+	assert.Equal(t, []string{"Encoder.Layers.0.W"}, frozenNames(m))
+	s.Apply(m, 5)
+	assert.Empty(t, frozenNames(m))
+}
+
+func TestUnfreezeSchedule_Gradients(t *testing.T) {
+	m := newStateRoot()
+	s := UnfreezeSchedule{
+		{Step: 0, Patterns: []string{"Heads"}},
+		{Step: 1, Patterns: []string{"Encoder"}},
+	}
+	head := m.Heads["a"].W
+	layer := m.Encoder.Layers[0].W
+
+	s.Apply(m, 0)
+	head.AccGrad(mat.NewDense[float32](mat.WithShape(head.Shape()...)))
+	s.Apply(m, 0)
+	assert.True(t, head.HasGrad(), "the gradients of the parameters staying trainable are preserved")
+
+	s.Apply(m, 1)
+	assert.True(t, head.HasGrad())
+	layer.AccGrad(mat.NewDense[float32](mat.WithShape(layer.Shape()...)))
+
+	s.Apply(m, 0)
+	assert.True(t, head.HasGrad())
+	assert.True(t, IsFrozen(layer))
+	assert.False(t, layer.HasGrad(), "the gradients of the parameters leaving the trainable set are cleared")
+}
+
+func TestSelectParameters(t *testing.T) {
+	m := newStateRoot()
+	var names []string
+	for _, p := range SelectParameters(m, "Heads.b", "Shared") {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"Heads.b.W", "Shared"}, names)
+	assert.Len(t, SelectParameters(m), 4)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/nn"
 	"github.com/nlpodyssey/spago/nn/activation"
+	"github.com/nlpodyssey/spago/nn/dropout"
 	"github.com/nlpodyssey/spago/nn/linear"
 )
 
@@ -18,14 +19,26 @@ type FeedForward struct {
 	Layers nn.ModuleList[nn.StandardModel]
 }
 
-func newFeedForward[T float.DType](dim, hiddenDim int, act activation.Activation, dropout T) *FeedForward {
+// newFeedForward returns a new FeedForward. The dropout layers, following the
+// activation and the output, are only included if p > 0, so that the layers
+// keep the same indices as without dropout.
+func newFeedForward[T float.DType](dim, hiddenDim int, act activation.Activation, p float64) *FeedForward {
+	if p <= 0 {
+		return &FeedForward{
+			Layers: []nn.StandardModel{
+				linear.New[T](dim, hiddenDim),
+				activation.New(act),
+				linear.New[T](hiddenDim, dim),
+			},
+		}
+	}
 	return &FeedForward{
 		Layers: []nn.StandardModel{
 			linear.New[T](dim, hiddenDim),
 			activation.New(act),
-			// dropout.New(dropout),
+			dropout.New(p),
 			linear.New[T](hiddenDim, dim),
-			// dropout.New(dropout),
+			dropout.New(p),
 		},
 	}
 }
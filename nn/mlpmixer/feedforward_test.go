@@ -0,0 +1,25 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mlpmixer
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/nn/activation"
+	"github.com/nlpodyssey/spago/nn/dropout"
+	"github.com/nlpodyssey/spago/nn/linear"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFeedForward_Dropout(t *testing.T) {
+	ff := newFeedForward[float32](3, 4, activation.ReLU, 0)
+	assert.Len(t, ff.Layers, 3)
+	assert.IsType(t, &linear.Model{}, ff.Layers[2])
+
+	ff = newFeedForward[float32](3, 4, activation.ReLU, 0.1)
+	assert.Len(t, ff.Layers, 5)
+	assert.IsType(t, &dropout.Model{}, ff.Layers[2])
+	assert.IsType(t, &dropout.Model{}, ff.Layers[4])
+}
@@ -38,6 +38,9 @@ type Config struct {
 	ActFunctionTokenMixer   activation.Activation
 	ActFunctionChannelMixer activation.Activation
 	Eps                     float64
+	// Dropout is the dropout probability of the feed-forward layers,
+	// disabled in nn.EvalMode. No dropout layers are added if it is 0.
+	Dropout float64
 }
 
 func init() {
@@ -48,9 +51,9 @@ func init() {
 func New[T float.DType](config Config) *MixerBlock {
 	return &MixerBlock{
 		Config:           config,
-		TokenMixerFF:     newFeedForward[T](config.Channels, config.HiddenSizeTokenMixer, config.ActFunctionTokenMixer, 0),
+		TokenMixerFF:     newFeedForward[T](config.Channels, config.HiddenSizeTokenMixer, config.ActFunctionTokenMixer, config.Dropout),
 		TokenLayerNorm:   layernorm.New[T](config.InputSize, config.Eps),
-		ChannelMixerFF:   newFeedForward[T](config.InputSize, config.HiddenSizeChannelMixer, config.ActFunctionChannelMixer, 0),
+		ChannelMixerFF:   newFeedForward[T](config.InputSize, config.HiddenSizeChannelMixer, config.ActFunctionChannelMixer, config.Dropout),
 		ChannelLayerNorm: layernorm.New[T](config.InputSize, config.Eps),
 	}
 }
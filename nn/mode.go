@@ -0,0 +1,64 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+// Mode is the running mode of a model. It affects the layers behaving
+// differently during training and inference, such as dropout and batch
+// normalization.
+type Mode int
+
+const (
+	// DefaultMode is the mode of a model which has never been set with Train
+	// or Eval. Each layer keeps its stand-alone behavior: for example, dropout
+	// is applied, and batch normalization uses the running statistics.
+	DefaultMode Mode = iota
+	// TrainMode is the mode set by Train.
+	TrainMode
+	// EvalMode is the mode set by Eval.
+	EvalMode
+)
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	switch m {
+	case TrainMode:
+		return "train"
+	case EvalMode:
+		return "eval"
+	default:
+		return "default"
+	}
+}
+
+// ModeSetter is implemented by the models whose behavior depends on the
+// running mode, such as dropout and batch normalization. They keep the mode
+// in an unexported field, so that it is not serialized: a model loaded from
+// disk is in DefaultMode.
+type ModeSetter interface {
+	// SetMode sets the running mode of the model alone, not of its sub-models.
+	SetMode(mode Mode)
+	// Mode returns the running mode of the model.
+	Mode() Mode
+}
+
+// Train sets the TrainMode to the model and to all its sub-models.
+func Train(m Model) {
+	SetMode(m, TrainMode)
+}
+
+// Eval sets the EvalMode to the model and to all its sub-models.
+func Eval(m Model) {
+	SetMode(m, EvalMode)
+}
+
+// SetMode sets the given mode to the model and to all its sub-models,
+// visited with Apply, which implement ModeSetter.
+func SetMode(m Model, mode Mode) {
+	Apply(m, func(model Model) {
+		if s, ok := model.(ModeSetter); ok {
+			s.SetMode(mode)
+		}
+	})
+}
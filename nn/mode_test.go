@@ -0,0 +1,53 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type modeLeaf struct {
+	Module
+	P    *Param
+	mode Mode
+}
+
+func (m *modeLeaf) SetMode(mode Mode) { m.mode = mode }
+
+func (m *modeLeaf) Mode() Mode { return m.mode }
+
+func TestTrainEval(t *testing.T) {
+	type root struct {
+		Module
+		Layers []Model
+		Named  map[string]*modeLeaf
+	}
+
+	m := &root{
+		Layers: []Model{&modeLeaf{}, &modeLeaf{}},
+		Named:  map[string]*modeLeaf{"a": {}},
+	}
+	modes := func(m Model) []Mode {
+		var out []Mode
+		Apply(m, func(model Model) {
+			if v, ok := model.(ModeSetter); ok {
+				out = append(out, v.Mode())
+			}
+		})
+		return out
+	}
+
+	assert.Equal(t, []Mode{DefaultMode, DefaultMode, DefaultMode}, modes(m))
+	Train(m)
+	assert.Equal(t, []Mode{TrainMode, TrainMode, TrainMode}, modes(m))
+	Eval(m)
+	assert.Equal(t, []Mode{EvalMode, EvalMode, EvalMode}, modes(m))
+	Train(m.Layers[0])
+	assert.Equal(t, []Mode{TrainMode, EvalMode, EvalMode}, modes(m))
+	assert.Equal(t, modes(m), modes(Clone(m)))
+	assert.Equal(t, "train", TrainMode.String())
+}
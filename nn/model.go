@@ -21,9 +21,9 @@ func Apply(m Model, fn func(model Model)) {
 	fn(m)
 	paramsTraversal{
 		paramsFunc:       nil,
-		modelsFunc:       fn,
+		modelsFunc:       func(_ string, model Model) { fn(model) },
 		exploreSubModels: true,
-	}.walk(m)
+	}.walk(m, "")
 }
 
 type ParamChannelFunc func(ctx context.Context) <-chan *Param
@@ -35,7 +35,7 @@ func Parameters(m Model) ParamChannelFunc {
 		go func() {
 			defer close(paramChan)
 			paramsTraversal{
-				paramsFunc: func(param *Param) {
+				paramsFunc: func(_ string, param *Param) {
 					select {
 					case <-ctx.Done():
 						return // Stop sending to the channel if context is done
@@ -44,7 +44,7 @@ func Parameters(m Model) ParamChannelFunc {
 				},
 				modelsFunc:       nil,
 				exploreSubModels: true,
-			}.walk(m)
+			}.walk(m, "")
 		}()
 
 		return paramChan
@@ -74,26 +74,65 @@ func StreamParams(params []*Param) ParamChannelFunc {
 // ForEachParam iterate all the parameters of a model also exploring the sub-parameters recursively.
 func ForEachParam(m Model, fn func(param *Param)) {
 	paramsTraversal{
-		paramsFunc:       fn,
+		paramsFunc:       ignoreName(fn),
 		modelsFunc:       nil,
 		exploreSubModels: true,
-	}.walk(m)
+	}.walk(m, "")
 }
 
 // ForEachParamStrict iterate all the parameters of a model without exploring the sub-models.
 func ForEachParamStrict(m Model, fn func(param *Param)) {
 	paramsTraversal{
-		paramsFunc:       fn,
+		paramsFunc:       ignoreName(fn),
 		modelsFunc:       nil,
 		exploreSubModels: false,
-	}.walk(m)
+	}.walk(m, "")
+}
+
+// ForEachNamedParam iterates all the parameters of a model, also exploring the
+// sub-models recursively, calling fn with each parameter and its path.
+//
+// The path is made of the names of the struct fields, the slice indices and
+// the map keys leading to the parameter, joined by dots (e.g. "Layers.0.W").
+//
+// Unlike ForEachParam, custom ParamsTraverser implementations are ignored and
+// the exported fields are always explored, so that every parameter is visited
+// with a stable name, regardless of the state of its gradient.
+func ForEachNamedParam(m Model, fn func(name string, param *Param)) {
+	paramsTraversal{
+		paramsFunc:       fn,
+		modelsFunc:       nil,
+		exploreSubModels: true,
+		bypassTraversers: true,
+	}.walk(m, "")
+}
+
+// ZeroGradHook is implemented by models keeping their own bookkeeping of the
+// parameters with gradients (e.g. the sparse gradients of the embeddings),
+// which must be reset together with the gradients. AfterZeroGrad is called
+// by ZeroGrad, once the gradients of the parameters have been zeroed.
+type ZeroGradHook interface {
+	AfterZeroGrad()
 }
 
 // ZeroGrad set the gradients of all model's parameters (including sub-params) to zeros.
+// Then, it calls AfterZeroGrad on the model and on all its sub-models
+// implementing ZeroGradHook.
 func ZeroGrad(m Model) {
 	ForEachParam(m, func(param *Param) {
 		param.ZeroGrad()
 	})
+	if h, ok := m.(ZeroGradHook); ok {
+		h.AfterZeroGrad()
+	}
+	paramsTraversal{
+		modelsFunc: func(_ string, model Model) {
+			if h, ok := model.(ZeroGradHook); ok {
+				h.AfterZeroGrad()
+			}
+		},
+		exploreSubModels: true,
+	}.walk(m, "")
 }
 
 // StandardModel consists of a model that implements a Forward variadic function that accepts mat.Tensor and returns a slice of mat.Tensor.
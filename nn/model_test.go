@@ -7,6 +7,7 @@ package nn
 import (
 	"testing"
 
+	"github.com/nlpodyssey/spago/mat"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,3 +46,65 @@ func TestForEachParamStrict(t *testing.T) {
 		})
 	}
 }
+
+func TestForEachNamedParam(t *testing.T) {
+	type leafType struct {
+		Module
+		W *Param
+		B *Param
+	}
+
+	type traverserType struct {
+		Module
+		traversableType
+		P []*Param
+	}
+
+	type modelType struct {
+		Module
+		Emb    *Param
+		Layers []*leafType
+		Named  map[string]*Param
+		Custom *traverserType
+		other  *Param
+	}
+
+	newParam := func() *Param {
+		return NewParam(mat.Scalar[float32](1))
+	}
+
+	m := &modelType{
+		Emb: newParam(),
+		Layers: []*leafType{
+			{W: newParam(), B: newParam()},
+			{W: newParam(), B: newParam()},
+		},
+		Named: map[string]*Param{"foo": newParam()},
+		Custom: &traverserType{
+			traversableType: traversableType{
+				fn: func(callback func(param *Param)) {},
+			},
+			P: []*Param{newParam(), newParam()},
+		},
+		other: newParam(),
+	}
+
+	expected := map[string]*Param{
+		"Emb":        m.Emb,
+		"Layers.0.W": m.Layers[0].W,
+		"Layers.0.B": m.Layers[0].B,
+		"Layers.1.W": m.Layers[1].W,
+		"Layers.1.B": m.Layers[1].B,
+		"Named.foo":  m.Named["foo"],
+		"Custom.P.0": m.Custom.P[0],
+		"Custom.P.1": m.Custom.P[1],
+	}
+
+	actual := make(map[string]*Param)
+	ForEachNamedParam(m, func(name string, p *Param) {
+		_, exists := actual[name]
+		assert.Falsef(t, exists, "duplicate name %q", name)
+		actual[name] = p
+	})
+	assert.Equal(t, expected, actual)
+}
@@ -13,7 +13,10 @@ import (
 	"github.com/nlpodyssey/spago/nn"
 )
 
-var _ nn.Model = &Model{}
+var (
+	_ nn.Model      = &Model{}
+	_ nn.ModeSetter = &Model{}
+)
 
 // Model contains the serializable parameters.
 type Model struct {
@@ -23,6 +26,7 @@ type Model struct {
 	Mean     *nn.Buffer
 	StdDev   *nn.Buffer
 	Momentum *nn.Buffer
+	mode     nn.Mode
 }
 
 const epsilon = 1e-5
@@ -48,14 +52,31 @@ func New[T float.DType](size int) *Model {
 	return NewWithMomentum[T](size, defaultMomentum)
 }
 
+// SetMode sets the running mode of the model.
+func (m *Model) SetMode(mode nn.Mode) {
+	m.mode = mode
+}
+
+// Mode returns the running mode of the model.
+func (m *Model) Mode() nn.Mode {
+	return m.mode
+}
+
 // Forward performs the forward step for each input node and returns the result.
+// It normalizes the input with the running statistics, unless the model is
+// in nn.TrainMode, in which case it behaves like ForwardT.
 func (m *Model) Forward(xs ...mat.Tensor) []mat.Tensor {
+	if m.mode == nn.TrainMode {
+		return m.ForwardT(xs...)
+	}
 	meanVector := ag.StopGrad(m.Mean)
 	devVector := ag.StopGrad(m.StdDev)
 	return m.process(xs, devVector, meanVector)
 }
 
 // ForwardT performs the forward step for each input node and returns the result.
+// It normalizes the input with the statistics of the batch, updating the
+// running ones, regardless of the mode of the model.
 func (m *Model) ForwardT(xs ...mat.Tensor) []mat.Tensor {
 	meanVector := m.mean(xs)
 	devVector := m.stdDev(meanVector, xs)
@@ -145,6 +145,27 @@ func testModelInference[T float.DType](t *testing.T) {
 	assert.InDeltaSlice(t, []T{1.0, 4.0, 2.0}, y[0].Value().Data(), 1e-3)
 }
 
+func TestModel_Mode(t *testing.T) {
+	model := newTestModel[float64]()
+	x := []mat.Tensor{
+		mat.NewDense[float64](mat.WithBacking([]float64{0.4, 0.8, -0.7, -0.5})),
+		mat.NewDense[float64](mat.WithBacking([]float64{-0.4, -0.6, -0.2, -0.9})),
+	}
+
+	nn.Train(model)
+	y := model.Forward(x...)
+	expected := newTestModel[float64]().ForwardT(x...)
+	for i := range y {
+		assert.InDeltaSlice(t, expected[i].Value().Data(), y[i].Value().Data(), 1e-12)
+	}
+	assert.NotEqual(t, []float64{0, 0, 0, 0}, model.Mean.Value().Data())
+
+	nn.Eval(model)
+	mean := model.Mean.Value().(mat.Matrix).Clone()
+	model.Forward(x...)
+	assert.Equal(t, mean.Data(), model.Mean.Value().Data())
+}
+
 func TestModel_Forward(t *testing.T) {
 	t.Run("float32", testModelForward[float32])
 	t.Run("float64", testModelForward[float64])
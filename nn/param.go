@@ -8,7 +8,8 @@ import "github.com/nlpodyssey/spago/mat"
 
 type Param struct {
 	mat.Matrix
-	State interface{} // support structure for the optimization algorithm
+	State  interface{} // support structure for the optimization algorithm
+	frozen bool        // see IsFrozen
 }
 
 // NewParam returns a new param.
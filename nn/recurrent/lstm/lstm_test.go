@@ -12,6 +12,9 @@ import (
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/optimizers"
+	"github.com/nlpodyssey/spago/optimizers/sgd"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -425,3 +428,83 @@ func TestModel_Init(t *testing.T) {
 	t.Run("float32", testModelInit[float32])
 	t.Run("float64", testModelInit[float64])
 }
+
+func TestModel_TrainWithArena(t *testing.T) {
+	// A single time step keeps the training deterministic: on longer
+	// sequences, the gradients are accumulated concurrently in varying order.
+	train := func(arena *mat.Arena) []float32 {
+		model, optimizer, xs := newTrainingSetup(arena, 1)
+		for i := 0; i < 5; i++ {
+			if err := trainStep(model, optimizer, xs); err != nil {
+				t.Fatal(err)
+			}
+			if arena != nil {
+				arena.Release()
+			}
+		}
+		var params []float32
+		nn.ForEachParam(model, func(param *nn.Param) {
+			params = append(params, mat.Data[float32](param)...)
+		})
+		return params
+	}
+
+	arena := mat.NewArena()
+	assert.Equal(t, train(nil), train(nil), "baseline")
+	assert.Equal(t, train(nil), train(arena))
+	stats := arena.Stats()
+	assert.Greater(t, stats.Reuses, 0)
+	assert.Equal(t, stats.Allocs-stats.Reuses, stats.Pooled)
+}
+
+func BenchmarkModel_TrainStep(b *testing.B) {
+	b.Run("no-arena", func(b *testing.B) {
+		benchmarkTrainStep(b, nil)
+	})
+	b.Run("arena", func(b *testing.B) {
+		benchmarkTrainStep(b, mat.NewArena())
+	})
+}
+
+func benchmarkTrainStep(b *testing.B, arena *mat.Arena) {
+	model, optimizer, xs := newTrainingSetup(arena, 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := trainStep(model, optimizer, xs); err != nil {
+			b.Fatal(err)
+		}
+		if arena != nil {
+			arena.Release()
+		}
+	}
+	if arena != nil {
+		b.ReportMetric(arena.Stats().ReuseRatio(), "reuse")
+	}
+}
+
+// newTrainingSetup returns a model, an optimizer with momentum and a sequence
+// of n inputs created with the given arena, if not nil.
+func newTrainingSetup(arena *mat.Arena, n int) (*Model, *optimizers.Optimizer, []mat.Tensor) {
+	rndGen := rand.NewLockedRand(42)
+	model := New[float32](32, 64).Init(rndGen)
+	optimizer := optimizers.New(nn.Parameters(model), sgd.New[float32](sgd.NewConfig(0.01, 0.9, false)))
+	xs := make([]mat.Tensor, n)
+	for i := range xs {
+		data := make([]float32, 32)
+		for j := range data {
+			data[j] = rndGen.Float32()*2 - 1
+		}
+		xs[i] = mat.NewDense[float32](mat.WithShape(32), mat.WithBacking(data), mat.WithArena(arena))
+	}
+	return model, optimizer, xs
+}
+
+func trainStep(model *Model, optimizer *optimizers.Optimizer, xs []mat.Tensor) error {
+	ys := model.Forward(xs...)
+	loss := ag.ReduceSum(ag.Square(ys[len(ys)-1]))
+	if err := ag.Backward(loss); err != nil {
+		return err
+	}
+	return optimizer.Optimize()
+}
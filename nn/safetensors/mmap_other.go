@@ -0,0 +1,15 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package safetensors
+
+import "os"
+
+// mmapFile falls back to reading the whole named file.
+func mmapFile(filename string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(filename)
+	return data, nil, err
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package safetensors
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+// Save writes the state of the model m to w, that is, all its parameters
+// and buffers (e.g. the running statistics of batch normalization), as
+// returned by nn.StateDict: each tensor is named after its path within the
+// model (e.g. "Layers.0.W"), and the parameters shared by several
+// sub-models are stored once, under their first name.
+func Save(w io.Writer, m nn.Model, metadata map[string]string) error {
+	return Write(w, nn.StateDict(m), metadata)
+}
+
+// SaveFile writes the state of the model m to the named file.
+// See Save.
+func SaveFile(filename string, m nn.Model, metadata map[string]string) (err error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := f.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	return Save(f, m, metadata)
+}
+
+// Load sets the parameters and buffers of the model m to the values of the
+// tensors of f with the same name, with nn.LoadStateDict in non-strict mode,
+// converting their dtype where needed.
+//
+// Parameters and buffers without a corresponding tensor, tensors without a
+// corresponding parameter or buffer, and tensors with a different shape are
+// not an error: they are listed in the returned nn.LoadReport, leaving it to
+// the caller to decide whether the model can be used. The tensors are
+// converted to matrices as by File.Tensor, except that a one-dimensional
+// tensor of n elements matches both a n×1 and a 1×n parameter.
+func Load(f *File, m nn.Model) (nn.LoadReport, error) {
+	shapes := make(map[string][]int)
+	for _, p := range nn.NamedParameters(m) {
+		shapes[p.Name] = p.Param.Shape()
+	}
+	for _, b := range nn.NamedBuffers(m) {
+		shapes[b.Name] = b.Buffer.Shape()
+	}
+
+	// Only the tensors of the model are decoded, so that loading part of a
+	// large file does not copy all of it.
+	state := make(map[string]mat.Matrix)
+	var unexpected []string
+	for _, name := range f.Keys() {
+		shape, ok := shapes[name]
+		if !ok {
+			unexpected = append(unexpected, name)
+			continue
+		}
+		value, err := f.Tensor(name)
+		if err != nil {
+			return nn.LoadReport{}, fmt.Errorf("safetensors: tensor %q: %w", name, err)
+		}
+		if info := f.tensors[name]; len(info.Shape) == 1 && shape[0] == 1 {
+			value = value.T()
+		}
+		state[name] = value
+	}
+
+	report, err := nn.LoadStateDict(m, state, false)
+	report.Unexpected = unexpected
+	return report, err
+}
+
+// LoadFile loads the parameters and buffers of the model m from the named
+// file, memory-mapping it where supported. See Load.
+func LoadFile(filename string, m nn.Model) (_ nn.LoadReport, err error) {
+	f, err := MapFile(filename)
+	if err != nil {
+		return nn.LoadReport{}, err
+	}
+	defer func() {
+		if e := f.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	return Load(f, m)
+}
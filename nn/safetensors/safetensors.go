@@ -0,0 +1,387 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package safetensors implements reading and writing of the safetensors
+// format (https://github.com/huggingface/safetensors), allowing models to
+// exchange their parameters and buffers with other frameworks.
+//
+// A safetensors file starts with an 8-byte little-endian unsigned integer,
+// holding the size of a JSON header which describes each named tensor (its
+// dtype, shape and byte offsets). The raw little-endian tensor data follows.
+package safetensors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Supported data types.
+const (
+	F16  = "F16"
+	BF16 = "BF16"
+	F32  = "F32"
+	F64  = "F64"
+)
+
+// metadataKey is the special header entry holding free-form metadata.
+const metadataKey = "__metadata__"
+
+// maxHeaderSize is an upper limit for the header size, preventing huge
+// allocations when reading corrupted or malicious files.
+const maxHeaderSize = 100 << 20
+
+// TensorInfo describes a tensor stored in a safetensors file.
+type TensorInfo struct {
+	// DType is the data type of the tensor elements (e.g. "F32").
+	DType string `json:"dtype"`
+	// Shape is the shape of the tensor.
+	Shape []int `json:"shape"`
+	// DataOffsets are the begin and end offsets of the tensor data,
+	// relative to the beginning of the data section.
+	DataOffsets [2]int `json:"data_offsets"`
+}
+
+// File is a safetensors file, opened for reading.
+type File struct {
+	// Metadata contains the free-form string-to-string metadata of the file.
+	Metadata map[string]string
+	tensors  map[string]TensorInfo
+	data     []byte
+	closer   func() error
+}
+
+// Read reads a whole safetensors file from r.
+func Read(r io.Reader) (*File, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parse(buf, nil)
+}
+
+// ReadFile reads the safetensors file with the given name.
+func ReadFile(filename string) (*File, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return parse(buf, nil)
+}
+
+// MapFile opens the safetensors file with the given name, memory-mapping
+// its content where supported, so that tensor data is only paged in when
+// accessed. On platforms without memory-mapping support, the whole file is
+// read instead.
+//
+// The returned File must be closed after use.
+func MapFile(filename string) (*File, error) {
+	buf, unmap, err := mmapFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parse(buf, unmap)
+	if err != nil && unmap != nil {
+		_ = unmap()
+	}
+	return f, err
+}
+
+func parse(buf []byte, closer func() error) (*File, error) {
+	if len(buf) < 8 {
+		return nil, errors.New("safetensors: file too short")
+	}
+	n := binary.LittleEndian.Uint64(buf[:8])
+	if n > maxHeaderSize || n > uint64(len(buf)-8) {
+		return nil, fmt.Errorf("safetensors: invalid header size %d", n)
+	}
+
+	var header map[string]json.RawMessage
+	if err := json.Unmarshal(buf[8:8+n], &header); err != nil {
+		return nil, fmt.Errorf("safetensors: invalid header: %w", err)
+	}
+
+	f := &File{
+		tensors: make(map[string]TensorInfo, len(header)),
+		data:    buf[8+n:],
+		closer:  closer,
+	}
+	for name, raw := range header {
+		if name == metadataKey {
+			if err := json.Unmarshal(raw, &f.Metadata); err != nil {
+				return nil, fmt.Errorf("safetensors: invalid metadata: %w", err)
+			}
+			continue
+		}
+		var info TensorInfo
+		if err := json.Unmarshal(raw, &info); err != nil {
+			return nil, fmt.Errorf("safetensors: invalid tensor %q: %w", name, err)
+		}
+		if err := f.validate(info); err != nil {
+			return nil, fmt.Errorf("safetensors: invalid tensor %q: %w", name, err)
+		}
+		f.tensors[name] = info
+	}
+	return f, nil
+}
+
+func (f *File) validate(info TensorInfo) error {
+	size, err := dtypeSize(info.DType)
+	if err != nil {
+		return err
+	}
+	elements := 1
+	for _, dim := range info.Shape {
+		if dim < 0 {
+			return fmt.Errorf("negative dimension in shape %v", info.Shape)
+		}
+		if dim != 0 && elements > math.MaxInt/dim {
+			return fmt.Errorf("shape %v too large", info.Shape)
+		}
+		elements *= dim
+	}
+	if elements > math.MaxInt/size {
+		return fmt.Errorf("shape %v too large", info.Shape)
+	}
+	begin, end := info.DataOffsets[0], info.DataOffsets[1]
+	if begin < 0 || end < begin || end > len(f.data) {
+		return fmt.Errorf("data offsets %v out of range", info.DataOffsets)
+	}
+	if end-begin != elements*size {
+		return fmt.Errorf("data size %d does not match shape %v", end-begin, info.Shape)
+	}
+	return nil
+}
+
+// Close releases the resources associated with the file.
+// Tensors previously returned by the File remain valid.
+func (f *File) Close() error {
+	f.data = nil
+	if f.closer == nil {
+		return nil
+	}
+	closer := f.closer
+	f.closer = nil
+	return closer()
+}
+
+// Keys returns the sorted names of the tensors in the file.
+func (f *File) Keys() []string {
+	keys := make([]string, 0, len(f.tensors))
+	for k := range f.tensors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Info returns the description of the tensor with the given name.
+func (f *File) Info(name string) (TensorInfo, bool) {
+	info, ok := f.tensors[name]
+	return info, ok
+}
+
+// Tensor returns a new matrix with the content of the named tensor.
+//
+// F64 tensors are decoded as float64 matrices; any other supported
+// dtype is decoded as a float32 matrix. Since matrices are
+// two-dimensional, tensors with one dimension become column vectors,
+// and tensors with more than two dimensions are flattened over the
+// trailing dimensions.
+func (f *File) Tensor(name string) (mat.Matrix, error) {
+	info, ok := f.tensors[name]
+	if !ok {
+		return nil, fmt.Errorf("safetensors: tensor %q not found", name)
+	}
+	rows, cols := matrixShape(info.Shape)
+	data, err := f.decode(info)
+	if err != nil {
+		return nil, err
+	}
+	switch data.BitSize() {
+	case 32:
+		return mat.NewDense[float32](mat.WithShape(rows, cols), mat.WithBacking(data.F32())), nil
+	default:
+		return mat.NewDense[float64](mat.WithShape(rows, cols), mat.WithBacking(data.F64())), nil
+	}
+}
+
+// decode returns a copy of the tensor data, converted from its dtype.
+func (f *File) decode(info TensorInfo) (float.Slice, error) {
+	if f.data == nil && info.DataOffsets[1] > 0 {
+		return nil, errors.New("safetensors: file is closed")
+	}
+	raw := f.data[info.DataOffsets[0]:info.DataOffsets[1]]
+	switch info.DType {
+	case F64:
+		out := make([]float64, len(raw)/8)
+		for i := range out {
+			out[i] = math.Float64frombits(binary.LittleEndian.Uint64(raw[i*8:]))
+		}
+		return float.Make(out...), nil
+	case F32:
+		out := make([]float32, len(raw)/4)
+		for i := range out {
+			out[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+		}
+		return float.Make(out...), nil
+	case BF16:
+		out := make([]float32, len(raw)/2)
+		for i := range out {
+			out[i] = math.Float32frombits(uint32(binary.LittleEndian.Uint16(raw[i*2:])) << 16)
+		}
+		return float.Make(out...), nil
+	case F16:
+		out := make([]float32, len(raw)/2)
+		for i := range out {
+			out[i] = halfToFloat32(binary.LittleEndian.Uint16(raw[i*2:]))
+		}
+		return float.Make(out...), nil
+	default:
+		return nil, fmt.Errorf("safetensors: unsupported dtype %q", info.DType)
+	}
+}
+
+// Write writes the given named tensors to w in safetensors format.
+// The metadata is optional and can be nil.
+//
+// Matrices of float32 and float64 values are stored as F32 and F64
+// tensors respectively. Column vectors are stored as one-dimensional
+// tensors, as expected e.g. for the biases of PyTorch models; any other
+// matrix keeps its two-dimensional shape.
+func Write(w io.Writer, tensors map[string]mat.Matrix, metadata map[string]string) error {
+	names := make([]string, 0, len(tensors))
+	for name := range tensors {
+		if name == metadataKey {
+			return fmt.Errorf("safetensors: reserved tensor name %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	header := make(map[string]any, len(tensors)+1)
+	if len(metadata) > 0 {
+		header[metadataKey] = metadata
+	}
+	offset := 0
+	for _, name := range names {
+		m := tensors[name]
+		dtype, size := F64, 8
+		if m.Data().BitSize() == 32 {
+			dtype, size = F32, 4
+		}
+		end := offset + m.Size()*size
+		header[name] = TensorInfo{
+			DType:       dtype,
+			Shape:       tensorShape(m),
+			DataOffsets: [2]int{offset, end},
+		}
+		offset = end
+	}
+
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("safetensors: %w", err)
+	}
+	// The data section is aligned to 8 bytes by padding the header with spaces.
+	if pad := len(rawHeader) % 8; pad != 0 {
+		rawHeader = append(rawHeader, bytes.Repeat([]byte{' '}, 8-pad)...)
+	}
+
+	bw := newBufferedWriter(w)
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(rawHeader)))
+	bw.write(size[:])
+	bw.write(rawHeader)
+	for _, name := range names {
+		bw.writeTensor(tensors[name])
+	}
+	return bw.flush()
+}
+
+// WriteFile writes the given named tensors to the named file.
+func WriteFile(filename string, tensors map[string]mat.Matrix, metadata map[string]string) (err error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := f.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	return Write(f, tensors, metadata)
+}
+
+func dtypeSize(dtype string) (int, error) {
+	switch dtype {
+	case F16, BF16:
+		return 2, nil
+	case F32:
+		return 4, nil
+	case F64:
+		return 8, nil
+	default:
+		return 0, fmt.Errorf("unsupported dtype %q", dtype)
+	}
+}
+
+// tensorShape returns the shape of the tensor storing the matrix, which is
+// one-dimensional for column vectors.
+func tensorShape(m mat.Matrix) []int {
+	shape := m.Shape()
+	if len(shape) == 2 && shape[1] == 1 {
+		return []int{shape[0]}
+	}
+	return append([]int(nil), shape...)
+}
+
+// matrixShape maps a tensor shape to the rows and columns of a matrix.
+func matrixShape(shape []int) (rows, cols int) {
+	switch len(shape) {
+	case 0:
+		return 1, 1
+	case 1:
+		return shape[0], 1
+	default:
+		cols = 1
+		for _, dim := range shape[1:] {
+			cols *= dim
+		}
+		return shape[0], cols
+	}
+}
+
+// halfToFloat32 converts an IEEE 754 half-precision value to float32.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h) & 0x3ff
+
+	switch {
+	case exp == 0x1f: // Inf or NaN
+		return math.Float32frombits(sign | 0x7f800000 | frac<<13)
+	case exp == 0 && frac == 0: // signed zero
+		return math.Float32frombits(sign)
+	case exp == 0: // subnormal: normalize it
+		exp = 127 - 15 + 1
+		for frac&0x400 == 0 {
+			frac <<= 1
+			exp--
+		}
+		frac &= 0x3ff
+		return math.Float32frombits(sign | exp<<23 | frac<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | frac<<13)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package safetensors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type layer struct {
+	nn.Module
+	W *nn.Param
+	B *nn.Param
+}
+
+type model struct {
+	nn.Module
+	Layers []*layer
+	Tied   *nn.Param
+	Mean   *nn.Buffer
+}
+
+func newModel[T float.DType](offset T) *model {
+	newLayer := func(o T) *layer {
+		return &layer{
+			W: nn.NewParam(mat.NewDense[T](mat.WithShape(2, 3), mat.WithBacking([]T{o + 1, o + 2, o + 3, o + 4, o + 5, o + 6}))),
+			B: nn.NewParam(mat.NewDense[T](mat.WithShape(2), mat.WithBacking([]T{o + 7, o + 8}))),
+		}
+	}
+	m := &model{
+		Layers: []*layer{newLayer(offset), newLayer(offset + 10)},
+		Mean:   nn.Buf(mat.NewDense[T](mat.WithShape(1, 2), mat.WithBacking([]T{offset + 21, offset + 22}))),
+	}
+	m.Tied = m.Layers[0].W
+	return m
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Run("float32", testSaveLoad[float32])
+	t.Run("float64", testSaveLoad[float64])
+}
+
+func testSaveLoad[T float.DType](t *testing.T) {
+	src := newModel[T](0)
+	var buf bytes.Buffer
+	require.NoError(t, Save(&buf, src, map[string]string{"format": "pt"}))
+
+	f, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"format": "pt"}, f.Metadata)
+	assert.Equal(t, []string{"Layers.0.B", "Layers.0.W", "Layers.1.B", "Layers.1.W", "Mean"}, f.Keys())
+
+	dst := newModel[T](100)
+	report, err := Load(f, dst)
+	require.NoError(t, err)
+	assert.True(t, report.Complete())
+
+	for i := range src.Layers {
+		mat.AssertMatrixEquals(t, src.Layers[i].W, dst.Layers[i].W)
+		mat.AssertMatrixEquals(t, src.Layers[i].B, dst.Layers[i].B)
+	}
+	mat.AssertMatrixEquals(t, src.Mean.Value().(mat.Matrix), dst.Mean.Value().(mat.Matrix))
+	assert.Same(t, dst.Layers[0].W, dst.Tied)
+	assert.True(t, dst.Layers[0].W.RequiresGrad())
+}
+
+func TestLoad_DTypeConversion(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Save(&buf, newModel[float64](0), nil))
+	f, err := Read(&buf)
+	require.NoError(t, err)
+
+	dst := newModel[float32](100)
+	report, err := Load(f, dst)
+	require.NoError(t, err)
+	assert.True(t, report.Complete())
+	assert.Equal(t, []float32{11, 12, 13, 14, 15, 16}, dst.Layers[1].W.Data().F32())
+}
+
+func TestLoad_Report(t *testing.T) {
+	tensors := map[string]mat.Matrix{
+		"Layers.0.W": mat.NewDense[float32](mat.WithShape(3, 2)),
+		"Layers.0.B": mat.NewDense[float32](mat.WithShape(1, 2), mat.WithBacking([]float32{-1, -2})),
+		"Layers.1.W": mat.NewDense[float32](mat.WithShape(2, 3)),
+		"Mean":       mat.NewDense[float32](mat.WithShape(2), mat.WithBacking([]float32{-1, -2})),
+		"Extra":      mat.Scalar[float32](1),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, tensors, nil))
+	f, err := Read(&buf)
+	require.NoError(t, err)
+
+	m := newModel[float32](0)
+	report, err := Load(f, m)
+	require.NoError(t, err)
+	assert.False(t, report.Complete())
+	assert.Equal(t, nn.LoadReport{
+		Missing:    []string{"Layers.1.B"},
+		Unexpected: []string{"Extra"},
+		Mismatched: []string{"Layers.0.B", "Layers.0.W"},
+	}, report)
+
+	assert.Equal(t, []float32{1, 2, 3, 4, 5, 6}, m.Layers[0].W.Data().F32())
+	assert.Equal(t, []float32{7, 8}, m.Layers[0].B.Data().F32())
+	// a one-dimensional tensor matches a 1×n buffer
+	assert.Equal(t, []float32{-1, -2}, m.Mean.Value().Data().F32())
+}
+
+func TestLoadFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.safetensors")
+	require.NoError(t, SaveFile(filename, newModel[float32](0), nil))
+
+	dst := newModel[float32](100)
+	report, err := LoadFile(filename, dst)
+	require.NoError(t, err)
+	assert.True(t, report.Complete())
+	assert.Equal(t, []float32{17, 18}, dst.Layers[1].B.Data().F32())
+}
+
+func TestFile_Tensor(t *testing.T) {
+	// Hand-crafted file with one-dimensional half-precision tensors.
+	header := []byte(`{"h":{"dtype":"F16","shape":[3],"data_offsets":[0,6]},` +
+		`"b":{"dtype":"BF16","shape":[2],"data_offsets":[6,10]}}`)
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(header)))
+	buf.Write(header)
+	_ = binary.Write(&buf, binary.LittleEndian, []uint16{0x3c00, 0xc000, 0x3555, 0x3f80, 0xc0a0})
+
+	f, err := Read(&buf)
+	require.NoError(t, err)
+
+	h, err := f.Tensor("h")
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 1}, h.Shape())
+	assert.InDeltaSlice(t, []float32{1, -2, 0.33325}, h.Data().F32(), 1.0e-5)
+
+	b, err := f.Tensor("b")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{1, -5}, b.Data().F32())
+
+	_, err = f.Tensor("missing")
+	assert.Error(t, err)
+}
+
+func TestRead_Invalid(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte{1, 2, 3}))
+	assert.Error(t, err)
+
+	header := []byte(`{"x":{"dtype":"F32","shape":[2],"data_offsets":[0,4]}}`)
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(header)))
+	buf.Write(header)
+	buf.Write([]byte{0, 0, 0, 0})
+	_, err = Read(&buf)
+	assert.Error(t, err)
+
+	// 2^62 * 4 elements of 4 bytes would wrap around to 0 bytes
+	header = []byte(`{"x":{"dtype":"F32","shape":[4611686018427387904,4],"data_offsets":[0,0]}}`)
+	buf.Reset()
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(header)))
+	buf.Write(header)
+	_, err = Read(&buf)
+	assert.ErrorContains(t, err, "too large")
+}
+
+func TestWrite_Shapes(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, map[string]mat.Matrix{
+		"w":   mat.NewDense[float32](mat.WithShape(2, 3)),
+		"b":   mat.NewDense[float32](mat.WithShape(2, 1)),
+		"row": mat.NewDense[float32](mat.WithShape(1, 3)),
+	}, nil))
+	f, err := Read(&buf)
+	require.NoError(t, err)
+
+	for name, shape := range map[string][]int{"w": {2, 3}, "b": {2}, "row": {1, 3}} {
+		info, ok := f.Info(name)
+		require.True(t, ok)
+		assert.Equal(t, shape, info.Shape, name)
+	}
+	b, err := f.Tensor("b")
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, b.Shape())
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package safetensors
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// bufferedWriter writes to a buffered io.Writer, retaining the first error.
+type bufferedWriter struct {
+	w   *bufio.Writer
+	buf [8]byte
+	err error
+}
+
+func newBufferedWriter(w io.Writer) *bufferedWriter {
+	return &bufferedWriter{w: bufio.NewWriter(w)}
+}
+
+func (bw *bufferedWriter) write(p []byte) {
+	if bw.err != nil {
+		return
+	}
+	_, bw.err = bw.w.Write(p)
+}
+
+// writeTensor writes the little-endian data of the matrix.
+func (bw *bufferedWriter) writeTensor(m mat.Matrix) {
+	if m.Data().BitSize() == 32 {
+		for _, v := range mat.Data[float32](m) {
+			binary.LittleEndian.PutUint32(bw.buf[:4], math.Float32bits(v))
+			bw.write(bw.buf[:4])
+		}
+		return
+	}
+	for _, v := range mat.Data[float64](m) {
+		binary.LittleEndian.PutUint64(bw.buf[:8], math.Float64bits(v))
+		bw.write(bw.buf[:8])
+	}
+}
+
+func (bw *bufferedWriter) flush() error {
+	if bw.err != nil {
+		return bw.err
+	}
+	return bw.w.Flush()
+}
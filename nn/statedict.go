@@ -0,0 +1,150 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// NamedParam is a parameter together with its path within a model.
+type NamedParam struct {
+	Name  string
+	Param *Param
+}
+
+// NamedBuffer is a buffer together with its path within a model.
+type NamedBuffer struct {
+	Name   string
+	Buffer *Buffer
+}
+
+// NamedParameters returns all the parameters of the model, with their dotted
+// path as described by ForEachNamedParam, in traversal order.
+// A parameter shared by several sub-models is returned once, under the
+// first path it is reached with.
+func NamedParameters(m Model) []NamedParam {
+	var params []NamedParam
+	seen := make(map[*Param]bool)
+	ForEachNamedParam(m, func(name string, p *Param) {
+		if !seen[p] {
+			seen[p] = true
+			params = append(params, NamedParam{Name: name, Param: p})
+		}
+	})
+	return params
+}
+
+// NamedBuffers returns all the buffers of the model, with their dotted path,
+// in traversal order. A shared buffer is returned once, under the first path
+// it is reached with.
+func NamedBuffers(m Model) []NamedBuffer {
+	var buffers []NamedBuffer
+	seen := make(map[*Buffer]bool)
+	ForEachNamedBuffer(m, func(name string, b *Buffer) {
+		if !seen[b] {
+			seen[b] = true
+			buffers = append(buffers, NamedBuffer{Name: name, Buffer: b})
+		}
+	})
+	return buffers
+}
+
+// ForEachNamedBuffer iterates all the buffers of a model, also exploring the
+// sub-models recursively, calling fn with each buffer and its path.
+func ForEachNamedBuffer(m Model, fn func(name string, buffer *Buffer)) {
+	paramsTraversal{
+		buffersFunc:      fn,
+		exploreSubModels: true,
+		bypassTraversers: true,
+	}.walk(m, "")
+}
+
+// StateDict returns a copy of the values of all the parameters and buffers
+// of the model, keyed by their path (see NamedParameters and NamedBuffers).
+func StateDict(m Model) map[string]mat.Matrix {
+	state := make(map[string]mat.Matrix)
+	for _, p := range NamedParameters(m) {
+		state[p.Name] = p.Param.Matrix.Clone()
+	}
+	for _, b := range NamedBuffers(m) {
+		state[b.Name] = b.Buffer.Value().(mat.Matrix).Clone()
+	}
+	return state
+}
+
+// LoadReport describes how the entries of a state dict matched the
+// parameters and buffers of a model.
+type LoadReport struct {
+	// Missing lists the model parameters and buffers not found in the state.
+	Missing []string
+	// Unexpected lists the state entries not matching any of the model.
+	Unexpected []string
+	// Mismatched lists the entries whose shape differs from the one of the
+	// corresponding model parameter or buffer.
+	Mismatched []string
+}
+
+// Complete reports whether every parameter and buffer had a matching entry
+// and every entry was used.
+func (r LoadReport) Complete() bool {
+	return len(r.Missing) == 0 && len(r.Unexpected) == 0 && len(r.Mismatched) == 0
+}
+
+// LoadStateDict copies the values of the state into the parameters and
+// buffers of the model with the same name, converting their data type
+// where needed.
+//
+// In strict mode the state must match the model exactly: otherwise an error
+// is returned, and the model is left unchanged. In non-strict mode all the
+// matching entries are loaded, and the other ones are only listed in the
+// returned LoadReport.
+func LoadStateDict(m Model, state map[string]mat.Matrix, strict bool) (LoadReport, error) {
+	targets := stateTargets(m)
+	report := LoadReport{}
+	for name, target := range targets {
+		value, ok := state[name]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, name)
+		case !mat.SameDims(target, value):
+			report.Mismatched = append(report.Mismatched, name)
+		}
+	}
+	for name := range state {
+		if _, ok := targets[name]; !ok {
+			report.Unexpected = append(report.Unexpected, name)
+		}
+	}
+	sort.Strings(report.Missing)
+	sort.Strings(report.Unexpected)
+	sort.Strings(report.Mismatched)
+
+	if strict && !report.Complete() {
+		return report, fmt.Errorf("nn: state dict does not match the model: missing %v, unexpected %v, mismatched %v",
+			report.Missing, report.Unexpected, report.Mismatched)
+	}
+	for name, target := range targets {
+		if value, ok := state[name]; ok && mat.SameDims(target, value) {
+			target.SetData(value.Data())
+		}
+	}
+	return report, nil
+}
+
+// stateTargets returns the values of the parameters and buffers of the
+// model, keyed by their path.
+func stateTargets(m Model) map[string]mat.Matrix {
+	targets := make(map[string]mat.Matrix)
+	for _, p := range NamedParameters(m) {
+		targets[p.Name] = p.Param.Matrix
+	}
+	for _, b := range NamedBuffers(m) {
+		targets[b.Name] = b.Buffer.Value().(mat.Matrix)
+	}
+	return targets
+}
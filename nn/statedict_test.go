@@ -0,0 +1,120 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stateLeaf struct {
+	Module
+	W    *Param
+	Mean *Buffer
+}
+
+type stateEncoder struct {
+	Module
+	Layers []*stateLeaf
+}
+
+type stateRoot struct {
+	Module
+	Encoder *stateEncoder
+	Heads   map[string]*stateLeaf
+	Shared  *Param
+}
+
+func newStateRoot() *stateRoot {
+	leaf := func(v float64) *stateLeaf {
+		return &stateLeaf{
+			W:    NewParam(mat.NewDense[float32](mat.WithShape(2, 2), mat.WithBacking([]float32{1, 2, 3, float32(v)}))),
+			Mean: Buf(mat.NewDense[float32](mat.WithBacking([]float32{float32(v), 0}))),
+		}
+	}
+	m := &stateRoot{
+		Encoder: &stateEncoder{Layers: []*stateLeaf{leaf(1), leaf(2)}},
+		Heads:   map[string]*stateLeaf{"b": leaf(4), "a": leaf(5)},
+	}
+	m.Shared = m.Encoder.Layers[0].W
+	return m
+}
+
+func TestNamedParameters(t *testing.T) {
+	m := newStateRoot()
+	var names []string
+	for _, p := range NamedParameters(m) {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"Encoder.Layers.0.W", "Encoder.Layers.1.W", "Heads.a.W", "Heads.b.W"}, names)
+	assert.Same(t, m.Heads["a"].W, NamedParameters(m)[2].Param)
+
+	names = nil
+	for _, b := range NamedBuffers(m) {
+		names = append(names, b.Name)
+	}
+	assert.Equal(t, []string{"Encoder.Layers.0.Mean", "Encoder.Layers.1.Mean", "Heads.a.Mean", "Heads.b.Mean"}, names)
+}
+
+func TestStateDict(t *testing.T) {
+	src := newStateRoot()
+	state := StateDict(src)
+	assert.Len(t, state, 8)
+	assert.Equal(t, []float32{1, 2, 3, 5}, state["Heads.a.W"].Data().F32())
+	assert.Equal(t, []float32{4, 0}, state["Heads.b.Mean"].Data().F32())
+
+	// The state is a copy.
+	src.Heads["a"].W.SetData(mat.NewDense[float32](mat.WithShape(4)).Data())
+	assert.Equal(t, []float32{1, 2, 3, 5}, state["Heads.a.W"].Data().F32())
+
+	dst := newStateRoot()
+	for _, l := range append(dst.Encoder.Layers, dst.Heads["a"], dst.Heads["b"]) {
+		l.W.Zeros()
+		l.Mean.Value().(mat.Matrix).Zeros()
+	}
+	report, err := LoadStateDict(dst, state, true)
+	require.NoError(t, err)
+	assert.True(t, report.Complete())
+	assert.Equal(t, []float32{1, 2, 3, 5}, dst.Heads["a"].W.Data().F32())
+	assert.Equal(t, []float32{2, 0}, dst.Encoder.Layers[1].Mean.Value().Data().F32())
+	assert.Same(t, dst.Shared, dst.Encoder.Layers[0].W)
+}
+
+func TestLoadStateDict_DataType(t *testing.T) {
+	state := map[string]mat.Matrix{"W": mat.NewDense[float64](mat.WithShape(1, 2), mat.WithBacking([]float64{0.5, 1.5}))}
+	m := &stateLeaf{W: NewParam(mat.NewDense[float32](mat.WithShape(1, 2)))}
+	report, err := LoadStateDict(m, state, true)
+	require.NoError(t, err)
+	assert.True(t, report.Complete())
+	assert.Equal(t, []float32{0.5, 1.5}, m.W.Data().F32())
+}
+
+func TestLoadStateDict_Strict(t *testing.T) {
+	state := StateDict(newStateRoot())
+	delete(state, "Heads.a.W")
+	state["Heads.c.W"] = mat.NewDense[float32](mat.WithShape(2, 2))
+	state["Heads.b.W"] = mat.NewDense[float32](mat.WithShape(3, 2))
+
+	m := newStateRoot()
+	m.Encoder.Layers[1].W.Zeros()
+
+	report, err := LoadStateDict(m, state, true)
+	assert.Error(t, err)
+	assert.Equal(t, LoadReport{
+		Missing:    []string{"Heads.a.W"},
+		Unexpected: []string{"Heads.c.W"},
+		Mismatched: []string{"Heads.b.W"},
+	}, report)
+	assert.Equal(t, []float32{0, 0, 0, 0}, m.Encoder.Layers[1].W.Data().F32(), "strict mode must not load anything")
+
+	report, err = LoadStateDict(m, state, false)
+	assert.NoError(t, err)
+	assert.False(t, report.Complete())
+	assert.Equal(t, []float32{1, 2, 3, 2}, m.Encoder.Layers[1].W.Data().F32())
+	assert.Equal(t, []float32{1, 2, 3, 4}, m.Heads["b"].W.Data().F32())
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import "github.com/nlpodyssey/spago/mat/rand"
+
+// StreamSetter is implemented by the models drawing random numbers, such as
+// dropout, to make them reproducible regardless of the scheduling of the
+// goroutines (see rand.Stream).
+type StreamSetter interface {
+	// SetStream sets the stream the model draws from, until it is set again.
+	SetStream(s *rand.Stream)
+}
+
+// SetStreams sets to the model and to all its sub-models which implement
+// StreamSetter the sub-stream of s identified by their dotted path (as
+// reported by ForEachNamedParam, e.g. "Layers.0") and by the given step,
+// that is s.Split(path).SplitN(step). A sub-model shared by several models
+// gets the stream of the first path it is reached with.
+//
+// Calling it with a new step (e.g. at every training step) makes the models
+// draw new numbers, which only depend on the stream, the paths and the step.
+func SetStreams(m Model, s *rand.Stream, step uint64) {
+	seen := make(map[StreamSetter]bool)
+	set := func(name string, model Model) {
+		if v, ok := model.(StreamSetter); ok && !seen[v] {
+			seen[v] = true
+			v.SetStream(s.Split(name).SplitN(step))
+		}
+	}
+	set("", m)
+	paramsTraversal{
+		modelsFunc:       set,
+		exploreSubModels: true,
+		bypassTraversers: true,
+	}.walk(m, "")
+}
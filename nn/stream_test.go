@@ -0,0 +1,46 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/stretchr/testify/assert"
+)
+
+type streamLeaf struct {
+	Module
+	stream *rand.Stream
+}
+
+func (m *streamLeaf) SetStream(s *rand.Stream) { m.stream = s }
+
+func TestSetStreams(t *testing.T) {
+	type root struct {
+		Module
+		Layers []Model
+		Named  map[string]*streamLeaf
+		Tied   *streamLeaf
+	}
+
+	m := &root{
+		Layers: []Model{&streamLeaf{}, &modeLeaf{}},
+		Named:  map[string]*streamLeaf{"a": {}},
+	}
+	m.Tied = m.Layers[0].(*streamLeaf)
+
+	s := rand.NewStream(42)
+	SetStreams(m, s, 3)
+	assert.Equal(t, s.Split("Layers.0").SplitN(3), m.Tied.stream)
+	assert.Equal(t, s.Split("Named.a").SplitN(3), m.Named["a"].stream)
+
+	SetStreams(m, s, 4)
+	assert.Equal(t, s.Split("Layers.0").SplitN(4), m.Tied.stream)
+
+	leaf := &streamLeaf{}
+	SetStreams(leaf, s, 0)
+	assert.Equal(t, s.Split("").SplitN(0), leaf.stream)
+}
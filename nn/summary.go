@@ -0,0 +1,325 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// ModelSummary describes the structure of a model. See Summary.
+type ModelSummary struct {
+	// Layers lists the model and all its sub-models, in traversal order.
+	Layers []LayerSummary
+	// Trainable is the total number of elements of the parameters requiring
+	// gradients. Shared parameters are counted once.
+	Trainable int
+	// Frozen is the total number of elements of the parameters not
+	// requiring gradients. Shared parameters are counted once.
+	Frozen int
+	// Buffers is the total number of elements of the buffers.
+	Buffers int
+	// Memory is the size in bytes of the parameters and buffers, for each
+	// data type (e.g. "float32").
+	Memory map[string]int
+}
+
+// LayerSummary describes a model, without its sub-models.
+type LayerSummary struct {
+	// Name is the path of the model (e.g. "Layers.0"), empty for the root.
+	Name string
+	// Type is the Go type of the model.
+	Type string
+	// Depth is the number of models containing this one.
+	Depth int
+	// Params lists the parameters directly owned by the model.
+	Params []TensorSummary
+	// Buffers lists the buffers directly owned by the model.
+	Buffers []TensorSummary
+	// Trainable is the number of elements of the parameters requiring gradients.
+	Trainable int
+	// Frozen is the number of elements of the parameters not requiring gradients.
+	Frozen int
+	// BufferSize is the number of elements of the buffers.
+	BufferSize int
+	// OutputShapes are the shapes of the outputs recorded by SummaryWithInputs,
+	// if available.
+	OutputShapes [][]int
+}
+
+// TensorSummary describes a parameter or a buffer.
+type TensorSummary struct {
+	Name         string
+	Shape        []int
+	DType        string
+	RequiresGrad bool
+}
+
+// Summary describes the model m and all its sub-models, as visited by Apply,
+// with their parameters and buffers. The String method of the result
+// renders it as a hierarchical table.
+func Summary(m Model) ModelSummary {
+	s, _ := summarize(m)
+	return s
+}
+
+// SummaryWithInputs is like Summary, but it also runs the forward step of
+// the model with the given inputs, recording the shapes of the outputs of
+// the model itself and of its sub-models held by fields, slices or maps of
+// interface type, such as the elements of a ModuleList[StandardModel].
+// The sub-models held by fields of concrete type get no output shapes.
+//
+// The forward step is run on a copy of the model, sharing its parameters
+// and buffers, so the model itself is left untouched, and summaries of
+// different models can run concurrently.
+func SummaryWithInputs(m StandardModel, xs ...mat.Tensor) ModelSummary {
+	rec := &shapeRecorder{shapes: make(map[Model][][]int)}
+	rec.record(m, rec.instrument(m).Forward(xs...))
+
+	s, models := summarize(m)
+	for i, model := range models {
+		s.Layers[i].OutputShapes = rec.shapes[model]
+	}
+	return s
+}
+
+// summarize returns the summary of m, together with the models described
+// by each of its layers.
+func summarize(m Model) (ModelSummary, []Model) {
+	s := ModelSummary{Memory: make(map[string]int)}
+	var models []Model
+	var ancestors []string
+	visit := func(name string, model Model) {
+		for len(ancestors) > 0 && !isSubPath(name, ancestors[len(ancestors)-1]) {
+			ancestors = ancestors[:len(ancestors)-1]
+		}
+		s.Layers = append(s.Layers, summarizeLayer(name, len(ancestors), model))
+		models = append(models, model)
+		ancestors = append(ancestors, name)
+	}
+	visit("", m)
+	paramsTraversal{
+		modelsFunc:       visit,
+		exploreSubModels: true,
+		bypassTraversers: true,
+	}.walk(m, "")
+	s.addTotals(m)
+	return s, models
+}
+
+func isSubPath(name, parent string) bool {
+	return parent == "" || strings.HasPrefix(name, parent+".")
+}
+
+func summarizeLayer(name string, depth int, m Model) LayerSummary {
+	l := LayerSummary{Name: name, Type: fmt.Sprintf("%T", m), Depth: depth}
+	paramsTraversal{
+		paramsFunc: func(name string, p *Param) {
+			t := summarizeTensor(name, p.Matrix)
+			t.RequiresGrad = p.RequiresGrad()
+			l.Params = append(l.Params, t)
+			if t.RequiresGrad {
+				l.Trainable += p.Size()
+			} else {
+				l.Frozen += p.Size()
+			}
+		},
+		buffersFunc: func(name string, b *Buffer) {
+			l.Buffers = append(l.Buffers, summarizeTensor(name, b.Value()))
+			l.BufferSize += b.Value().Size()
+		},
+		bypassTraversers: true,
+	}.walk(m, "")
+	return l
+}
+
+func summarizeTensor(name string, t mat.Tensor) TensorSummary {
+	dtype, _ := dataType(t)
+	return TensorSummary{
+		Name:  name,
+		Shape: append([]int(nil), t.Shape()...),
+		DType: dtype,
+	}
+}
+
+// addTotals counts each parameter and buffer once, even if shared.
+func (s *ModelSummary) addTotals(m Model) {
+	for _, p := range NamedParameters(m) {
+		if p.Param.RequiresGrad() {
+			s.Trainable += p.Param.Size()
+		} else {
+			s.Frozen += p.Param.Size()
+		}
+		s.addMemory(p.Param.Matrix)
+	}
+	for _, b := range NamedBuffers(m) {
+		s.Buffers += b.Buffer.Value().Size()
+		s.addMemory(b.Buffer.Value())
+	}
+}
+
+func (s *ModelSummary) addMemory(t mat.Tensor) {
+	dtype, size := dataType(t)
+	s.Memory[dtype] += size * t.Size()
+}
+
+// dataType returns the name of the data type of the tensor, and the size
+// in bytes of its elements.
+func dataType(t mat.Tensor) (string, int) {
+	switch t.(type) {
+	case *mat.Dense[float32]:
+		return "float32", 4
+	case *mat.Dense[float64]:
+		return "float64", 8
+	default:
+		return fmt.Sprintf("%T", t), 0
+	}
+}
+
+// String renders the summary as a table, with the sub-models indented
+// below their parent, followed by the totals.
+func (s ModelSummary) String() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Layer\tType\tParams\tTrainable\tFrozen\tBuffers\tOutput")
+	for _, l := range s.Layers {
+		fmt.Fprintf(w, "%s%s\t%s\t%s\t%d\t%d\t%d\t%s\n", strings.Repeat("  ", l.Depth), shortName(l.Name), l.Type,
+			formatTensors(l.Params), l.Trainable, l.Frozen, l.BufferSize, formatShapes(l.OutputShapes))
+	}
+	_ = w.Flush()
+
+	fmt.Fprintf(&sb, "Trainable params: %d\nFrozen params: %d\nBuffers: %d\n", s.Trainable, s.Frozen, s.Buffers)
+	dtypes := make([]string, 0, len(s.Memory))
+	for dtype := range s.Memory {
+		dtypes = append(dtypes, dtype)
+	}
+	sort.Strings(dtypes)
+	for _, dtype := range dtypes {
+		fmt.Fprintf(&sb, "Memory (%s): %d bytes\n", dtype, s.Memory[dtype])
+	}
+	return sb.String()
+}
+
+// shortName returns the last element of the path of a layer, together with
+// the name of its container if the last element is an index or a key
+// (e.g. "Layers.0" for "Encoder.Layers.0").
+func shortName(name string) string {
+	if name == "" {
+		return "(root)"
+	}
+	parts := strings.Split(name, ".")
+	if len(parts) == 1 {
+		return name
+	}
+	last := parts[len(parts)-1]
+	if strings.Trim(last, "0123456789") == "" {
+		return parts[len(parts)-2] + "." + last
+	}
+	return last
+}
+
+func formatTensors(ts []TensorSummary) string {
+	parts := make([]string, len(ts))
+	for i, t := range ts {
+		parts[i] = fmt.Sprintf("%s%s", t.Name, formatShape(t.Shape))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatShape(shape []int) string {
+	dims := make([]string, len(shape))
+	for i, d := range shape {
+		dims[i] = fmt.Sprint(d)
+	}
+	return "[" + strings.Join(dims, "×") + "]"
+}
+
+// formatShapes formats the shapes of the outputs, collapsing them if they
+// are all equal (e.g. "3 × [4×1]").
+func formatShapes(shapes [][]int) string {
+	if len(shapes) == 0 {
+		return ""
+	}
+	first := formatShape(shapes[0])
+	for _, s := range shapes[1:] {
+		if formatShape(s) != first {
+			parts := make([]string, len(shapes))
+			for i, s := range shapes {
+				parts[i] = formatShape(s)
+			}
+			return strings.Join(parts, " ")
+		}
+	}
+	if len(shapes) == 1 {
+		return first
+	}
+	return fmt.Sprintf("%d × %s", len(shapes), first)
+}
+
+// shapeRecorder collects the shapes of the outputs of the models.
+type shapeRecorder struct {
+	mu     sync.Mutex
+	shapes map[Model][][]int
+}
+
+func (r *shapeRecorder) record(m Model, ys []mat.Tensor) {
+	if reflect.ValueOf(m).Kind() != reflect.Ptr {
+		return // only pointers can be reliably used as keys
+	}
+	shapes := make([][]int, len(ys))
+	for i, y := range ys {
+		shapes[i] = append([]int(nil), y.Value().Shape()...)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shapes[m] = shapes
+}
+
+// instrument returns a copy of m sharing its parameters and buffers, whose
+// sub-models held by interfaces are wrapped by a shapeProbe.
+func (r *shapeRecorder) instrument(m StandardModel) StandardModel {
+	c := cloner{memo: make(map[cloneKey]reflect.Value), share: true, wrap: r.wrap}
+	return c.clone(reflect.ValueOf(m)).Interface().(StandardModel)
+}
+
+// wrap is the wrap function of the cloner used by instrument.
+func (r *shapeRecorder) wrap(iface reflect.Type, src, dst reflect.Value) reflect.Value {
+	original, ok := src.Interface().(Model)
+	if !ok || src.Kind() != reflect.Ptr {
+		return dst
+	}
+	model, ok := dst.Interface().(StandardModel)
+	if !ok {
+		return dst
+	}
+	probe := &shapeProbe{model: model, original: original, rec: r}
+	if !reflect.TypeOf(probe).AssignableTo(iface) {
+		return dst
+	}
+	return reflect.ValueOf(probe)
+}
+
+// shapeProbe is a StandardModel recording the shapes of the outputs of the
+// copy of a model, on behalf of the original one.
+type shapeProbe struct {
+	Module
+	model    StandardModel
+	original Model
+	rec      *shapeRecorder
+}
+
+// Forward runs the forward step of the wrapped model, recording the shapes
+// of its outputs.
+func (p *shapeProbe) Forward(xs ...mat.Tensor) []mat.Tensor {
+	ys := p.model.Forward(xs...)
+	p.rec.record(p.original, ys)
+	return ys
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+)
+
+type summaryLayer struct {
+	Module
+	W *Param
+}
+
+func (m *summaryLayer) Forward(xs ...mat.Tensor) []mat.Tensor {
+	ys := make([]mat.Tensor, len(xs))
+	for i, x := range xs {
+		ys[i] = ag.Mul(m.W, x)
+	}
+	return ys
+}
+
+type summaryModel struct {
+	Module
+	Layers ModuleList[StandardModel]
+	Scale  *Param
+	Stats  *Buffer
+}
+
+func (m *summaryModel) Forward(xs ...mat.Tensor) []mat.Tensor {
+	return m.Layers.Forward(xs...)
+}
+
+func newSummaryModel() *summaryModel {
+	return &summaryModel{
+		Layers: []StandardModel{
+			&summaryLayer{W: NewParam(mat.NewDense[float32](mat.WithShape(8, 4)))},
+			&summaryLayer{W: NewParam(mat.NewDense[float32](mat.WithShape(2, 8)))},
+		},
+		Scale: NewParam(mat.NewDense[float64](mat.WithShape(3))).WithGrad(false),
+		Stats: Buf(mat.NewDense[float32](mat.WithShape(5))),
+	}
+}
+
+func TestSummary(t *testing.T) {
+	s := Summary(newSummaryModel())
+
+	assert.Equal(t, 48, s.Trainable)
+	assert.Equal(t, 3, s.Frozen)
+	assert.Equal(t, 5, s.Buffers)
+	assert.Equal(t, map[string]int{"float32": (48 + 5) * 4, "float64": 3 * 8}, s.Memory)
+
+	assert.Len(t, s.Layers, 3)
+	root := s.Layers[0]
+	assert.Equal(t, "", root.Name)
+	assert.Equal(t, "*nn.summaryModel", root.Type)
+	assert.Equal(t, 0, root.Depth)
+	assert.Equal(t, []TensorSummary{{Name: "Scale", Shape: []int{3, 1}, DType: "float64"}}, root.Params)
+	assert.Equal(t, []TensorSummary{{Name: "Stats", Shape: []int{5, 1}, DType: "float32"}}, root.Buffers)
+	assert.Equal(t, 3, root.Frozen)
+	assert.Equal(t, 5, root.BufferSize)
+
+	layer := s.Layers[2]
+	assert.Equal(t, "Layers.1", layer.Name)
+	assert.Equal(t, 1, layer.Depth)
+	assert.Equal(t, 16, layer.Trainable)
+	assert.Nil(t, layer.OutputShapes)
+
+	out := s.String()
+	assert.Contains(t, out, "(root)")
+	assert.Contains(t, out, "  Layers.1  ")
+	assert.Contains(t, out, "W[2×8]")
+	assert.Contains(t, out, "Memory (float64): 24 bytes")
+}
+
+func TestSummaryWithInputs(t *testing.T) {
+	m := newSummaryModel()
+	x := mat.NewDense[float32](mat.WithShape(4))
+	s := SummaryWithInputs(m, x, x)
+
+	assert.Equal(t, [][]int{{2, 1}, {2, 1}}, s.Layers[0].OutputShapes)
+	assert.Equal(t, [][]int{{8, 1}, {8, 1}}, s.Layers[1].OutputShapes)
+	assert.Equal(t, [][]int{{2, 1}, {2, 1}}, s.Layers[2].OutputShapes)
+	assert.True(t, strings.Contains(s.String(), "2 × [8×1]"))
+	assert.IsType(t, &summaryLayer{}, m.Layers[0], "the model must be left untouched")
+}
+
+type summaryHead struct {
+	Module
+	Body StandardModel
+	Out  *summaryLayer
+}
+
+func (m *summaryHead) Forward(xs ...mat.Tensor) []mat.Tensor {
+	return m.Out.Forward(m.Body.Forward(xs...)...)
+}
+
+func TestSummaryWithInputs_InterfaceFields(t *testing.T) {
+	m := &summaryHead{
+		Body: newSummaryModel(),
+		Out:  &summaryLayer{W: NewParam(mat.NewDense[float32](mat.WithShape(3, 2)))},
+	}
+	s := SummaryWithInputs(m, mat.NewDense[float32](mat.WithShape(4)))
+
+	names := make([]string, len(s.Layers))
+	for i, l := range s.Layers {
+		names[i] = l.Name
+	}
+	assert.Equal(t, []string{"", "Body", "Body.Layers.0", "Body.Layers.1", "Out"}, names)
+	assert.Equal(t, [][]int{{3, 1}}, s.Layers[0].OutputShapes)
+	assert.Equal(t, [][]int{{2, 1}}, s.Layers[1].OutputShapes)
+	assert.Equal(t, [][]int{{8, 1}}, s.Layers[2].OutputShapes)
+	assert.Equal(t, [][]int{{2, 1}}, s.Layers[3].OutputShapes)
+	assert.Nil(t, s.Layers[4].OutputShapes, "held by a field of concrete type")
+}
+
+func TestSummaryWithInputs_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 1; i <= 8; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			xs := make([]mat.Tensor, n)
+			for j := range xs {
+				xs[j] = mat.NewDense[float32](mat.WithShape(4))
+			}
+			s := SummaryWithInputs(newSummaryModel(), xs...)
+			assert.Len(t, s.Layers[1].OutputShapes, n)
+			assert.Len(t, s.Layers[2].OutputShapes, n)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSummary_Shared(t *testing.T) {
+	m := newSummaryModel()
+	m.Layers = append(m.Layers, m.Layers[0])
+	s := Summary(m)
+	assert.Len(t, s.Layers, 4)
+	assert.Equal(t, 48, s.Trainable)
+}
@@ -7,6 +7,7 @@ package nn
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 )
 
@@ -18,22 +19,31 @@ type ParamsTraverser interface {
 }
 
 // paramsTraversal allows the traversal of Model parameters.
-// The given paramsFunc is invoked for each parameter of the Model.
+// The given paramsFunc is invoked for each parameter of the Model, together
+// with its dotted path (e.g. "Layers.0.W"); the optional buffersFunc and
+// modelsFunc are invoked likewise for each Buffer and each nested Model.
 // If exploreSubModels is true, every nested Model and its parameters are
 // also visited.
+// If bypassTraversers is true, custom ParamsTraverser implementations are
+// ignored and the exported fields are always explored via reflection, so
+// that every parameter is reached with a meaningful path.
 type paramsTraversal struct {
-	paramsFunc       func(param *Param)
-	modelsFunc       func(model Model)
+	paramsFunc       func(name string, param *Param)
+	buffersFunc      func(name string, buffer *Buffer)
+	modelsFunc       func(name string, model Model)
 	exploreSubModels bool
+	bypassTraversers bool
 }
 
 // walk iterates through all the parameters of m.
-func (pt paramsTraversal) walk(m any) {
-	if m, ok := m.(ParamsTraverser); ok {
-		m.TraverseParams(pt.paramsFunc)
+// The prefix is prepended to the name of each field.
+func (pt paramsTraversal) walk(m any, prefix string) {
+	if m, ok := m.(ParamsTraverser); ok && !pt.bypassTraversers {
+		m.TraverseParams(pt.unnamedParamsFunc(prefix))
 		return
 	}
 	forEachField(m, func(field any, name string) {
+		name = joinPath(prefix, name)
 		v := reflect.ValueOf(field)
 		switch v.Kind() {
 		case reflect.Struct, reflect.Ptr, reflect.Interface:
@@ -56,22 +66,25 @@ func (pt paramsTraversal) walkStructOrPtr(item any, name string) bool {
 		// skip
 	case *Param:
 		if pt.paramsFunc != nil {
-			pt.paramsFunc(itemT)
+			pt.paramsFunc(name, itemT)
+		}
+	case *Buffer:
+		if pt.buffersFunc != nil {
+			pt.buffersFunc(name, itemT)
 		}
 	case ParamsTraverser:
+		if m, ok := item.(Model); ok && pt.bypassTraversers {
+			pt.walkModel(m, name)
+			break
+		}
 		if pt.paramsFunc != nil {
-			itemT.TraverseParams(pt.paramsFunc)
+			itemT.TraverseParams(pt.unnamedParamsFunc(name))
 		}
 		if m, ok := item.(Model); ok && pt.modelsFunc != nil {
-			pt.modelsFunc(m)
+			pt.modelsFunc(name, m)
 		}
 	case Model:
-		if pt.exploreSubModels {
-			if pt.modelsFunc != nil {
-				pt.modelsFunc(itemT)
-			}
-			pt.walk(item)
-		}
+		pt.walkModel(itemT, name)
 	case *sync.Map:
 		pt.walkSyncMap(itemT, name)
 	default:
@@ -80,6 +93,28 @@ func (pt paramsTraversal) walkStructOrPtr(item any, name string) bool {
 	return true
 }
 
+func (pt paramsTraversal) walkModel(m Model, name string) {
+	if !pt.exploreSubModels {
+		return
+	}
+	if pt.modelsFunc != nil {
+		pt.modelsFunc(name, m)
+	}
+	pt.walk(m, name)
+}
+
+// unnamedParamsFunc adapts paramsFunc to the callback expected by a
+// ParamsTraverser. Since the custom traversal does not provide any name,
+// all the parameters are reported with the given one.
+func (pt paramsTraversal) unnamedParamsFunc(name string) func(param *Param) {
+	if pt.paramsFunc == nil {
+		return nil
+	}
+	return func(param *Param) {
+		pt.paramsFunc(name, param)
+	}
+}
+
 func (pt paramsTraversal) walkSyncMap(i *sync.Map, name string) {
 	i.Range(func(key, value any) bool {
 		switch k := key.(type) {
@@ -110,7 +145,7 @@ func (pt paramsTraversal) walkSlice(v reflect.Value, name string) {
 		p := v.Index(i)
 		switch p.Kind() {
 		case reflect.Struct, reflect.Ptr, reflect.Interface:
-			if !pt.walkStructOrPtr(p.Interface(), name) {
+			if !pt.walkStructOrPtr(p.Interface(), fmt.Sprintf("%s.%d", name, i)) {
 				return
 			}
 		default:
@@ -120,22 +155,16 @@ func (pt paramsTraversal) walkSlice(v reflect.Value, name string) {
 }
 
 func (pt paramsTraversal) walkMap(v reflect.Value, name string) {
-	mapRange := v.MapRange()
-	for mapRange.Next() {
-		key := ""
-		switch k := mapRange.Key().Interface().(type) {
-		case string:
-			key = k
-		case int:
-			key = fmt.Sprintf("%d", k)
-		default:
-			return // skip map if the key is not a string or an int
-		}
-
-		name := fmt.Sprintf("%s.%s", name, key)
-		switch mapRange.Value().Kind() {
+	keys, ok := sortedMapKeys(v)
+	if !ok {
+		return // skip map if the key is not a string or an int
+	}
+	for _, key := range keys {
+		value := v.MapIndex(key)
+		name := fmt.Sprintf("%s.%v", name, key.Interface())
+		switch value.Kind() {
 		case reflect.Struct, reflect.Ptr, reflect.Interface:
-			if !pt.walkStructOrPtr(mapRange.Value().Interface(), name) {
+			if !pt.walkStructOrPtr(value.Interface(), name) {
 				return
 			}
 		default:
@@ -144,6 +173,22 @@ func (pt paramsTraversal) walkMap(v reflect.Value, name string) {
 	}
 }
 
+// sortedMapKeys returns the keys of the map v in ascending order, so that
+// the traversal is deterministic. It reports false if the keys are neither
+// strings nor ints.
+func sortedMapKeys(v reflect.Value) ([]reflect.Value, bool) {
+	keys := v.MapKeys()
+	switch v.Type().Key().Kind() {
+	case reflect.String:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	case reflect.Int:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+	default:
+		return nil, false
+	}
+	return keys, true
+}
+
 // forEachField calls the paramsFunc for each field of the struct i.
 func forEachField(i any, callback func(field any, name string)) {
 	v := reflect.ValueOf(i)
@@ -166,3 +211,21 @@ func forEachField(i any, callback func(field any, name string)) {
 		}
 	}
 }
+
+// ignoreName adapts a callback that does not care about the parameters' names.
+func ignoreName(fn func(param *Param)) func(name string, param *Param) {
+	if fn == nil {
+		return nil
+	}
+	return func(_ string, param *Param) {
+		fn(param)
+	}
+}
+
+// joinPath joins a parent path and a child name with a dot.
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
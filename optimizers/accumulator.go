@@ -0,0 +1,125 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimizers
+
+import (
+	"context"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/optimizers/gradclipper"
+)
+
+// GradAccumulator wraps an Optimizer to accumulate the gradients of several
+// micro-batches before each optimization, obtaining the effective batch
+// size of all of them with the memory needed by a single one.
+//
+// After the backward pass of each micro-batch, call Step: the gradients are
+// summed up into the parameters, and every MicroSteps calls they are
+// averaged (scaled by 1/MicroSteps), clipped, if a clipper is set, and used
+// to optimize the parameters; finally, all the gradients of the model are
+// zeroed with nn.ZeroGrad.
+//
+// The parameters to scale and clip are the ones of the optimizer, so the
+// sparse gradients of the embeddings (see embedding.Model) are handled
+// efficiently when the optimizer is created with nn.Parameters.
+type GradAccumulator struct {
+	optimizer  *Optimizer
+	model      nn.Model
+	microSteps int
+	clipper    gradclipper.GradClipper
+	count      int
+}
+
+// NewGradAccumulator returns a new GradAccumulator optimizing, every
+// microSteps calls to Step, the parameters of the optimizer, which must
+// belong to the model m.
+// It panics if microSteps is not positive.
+func NewGradAccumulator(o *Optimizer, m nn.Model, microSteps int) *GradAccumulator {
+	if microSteps <= 0 {
+		panic("optimizers: the number of micro-steps must be > 0")
+	}
+	return &GradAccumulator{
+		optimizer:  o,
+		model:      m,
+		microSteps: microSteps,
+	}
+}
+
+// WithClipper sets the clipper of the accumulated gradients, which is
+// applied after the averaging.
+func (a *GradAccumulator) WithClipper(c gradclipper.GradClipper) *GradAccumulator {
+	a.clipper = c
+	return a
+}
+
+// MicroSteps returns the number of micro-steps of each optimization.
+func (a *GradAccumulator) MicroSteps() int {
+	return a.microSteps
+}
+
+// Pending returns the number of micro-steps accumulated since the last
+// optimization.
+func (a *GradAccumulator) Pending() int {
+	return a.count
+}
+
+// Step marks the end of a micro-step, to be called after the backward pass
+// of each micro-batch. It returns whether the parameters have been
+// optimized, that is, whether the micro-step completed an accumulation.
+func (a *GradAccumulator) Step() (bool, error) {
+	a.count++
+	if a.count < a.microSteps {
+		return false, nil
+	}
+	return true, a.optimize()
+}
+
+// Flush optimizes the parameters with the gradients accumulated since the
+// last optimization, if any, averaging them on the number of pending
+// micro-steps. It is useful at the end of an epoch, whose micro-batches
+// are not a multiple of MicroSteps. It returns whether the parameters have
+// been optimized.
+func (a *GradAccumulator) Flush() (bool, error) {
+	if a.count == 0 {
+		return false, nil
+	}
+	return true, a.optimize()
+}
+
+// optimize averages and clips the accumulated gradients, optimizes the
+// parameters and zeroes the gradients. The pending micro-steps are reset
+// even on errors, so that the next accumulation starts afresh.
+func (a *GradAccumulator) optimize() error {
+	defer func() {
+		nn.ZeroGrad(a.model)
+		a.count = 0
+	}()
+
+	if a.count > 1 {
+		a.scaleGrads(1 / float64(a.count))
+	}
+	if a.clipper != nil {
+		a.clipper.ClipGrads(a.optimizer.parameters)
+	}
+	return a.optimizer.Optimize()
+}
+
+// scaleGrads multiplies the gradients of the parameters of the optimizer
+// by the given factor, once per parameter, even if shared (e.g. by
+// embedding.Shared). Parameters without gradients, or frozen, are skipped.
+func (a *GradAccumulator) scaleGrads(factor float64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seen := make(map[*nn.Param]bool)
+	for param := range a.optimizer.parameters(ctx) {
+		if seen[param] || !param.HasGrad() || nn.IsFrozen(param) {
+			continue
+		}
+		seen[param] = true
+		param.Grad().(mat.Matrix).ProdScalarInPlace(factor)
+	}
+}
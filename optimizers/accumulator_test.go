@@ -0,0 +1,142 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimizers_test
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/embedding"
+	"github.com/nlpodyssey/spago/optimizers"
+	"github.com/nlpodyssey/spago/optimizers/gradclipper"
+	"github.com/nlpodyssey/spago/optimizers/sgd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accumulatorTestModel struct {
+	nn.Module
+	W *nn.Param
+}
+
+func newAccumulatorTestModel() *accumulatorTestModel {
+	return &accumulatorTestModel{
+		W: nn.NewParam(mat.NewDense[float64](mat.WithBacking([]float64{1, 2, 3}))),
+	}
+}
+
+func accGrad(p *nn.Param, values ...float64) {
+	p.AccGrad(mat.NewDense[float64](mat.WithBacking(values)))
+}
+
+func TestGradAccumulator_Step(t *testing.T) {
+	m := newAccumulatorTestModel()
+	opt := optimizers.New(nn.Parameters(m), sgd.New[float64](sgd.NewConfig(0.1, 0, false)))
+	acc := optimizers.NewGradAccumulator(opt, m, 3)
+
+	micro := [][]float64{{1, 2, 3}, {3, 0, -3}, {2, 1, 0}}
+	for i, g := range micro {
+		accGrad(m.W, g...)
+		stepped, err := acc.Step()
+		require.NoError(t, err)
+		if i < 2 {
+			assert.False(t, stepped)
+			assert.Equal(t, i+1, acc.Pending())
+			assert.Equal(t, []float64{1, 2, 3}, m.W.Data().F64())
+		} else {
+			assert.True(t, stepped)
+		}
+	}
+
+	// the average of the gradients is (2, 1, 0)
+	assert.InDeltaSlice(t, []float64{0.8, 1.9, 3}, m.W.Data().F64(), 1.0e-12)
+	assert.False(t, m.W.HasGrad())
+	assert.Equal(t, 0, acc.Pending())
+	assert.Equal(t, 1, opt.Steps())
+}
+
+func TestGradAccumulator_Flush(t *testing.T) {
+	m := newAccumulatorTestModel()
+	opt := optimizers.New(nn.Parameters(m), sgd.New[float64](sgd.NewConfig(0.1, 0, false)))
+	acc := optimizers.NewGradAccumulator(opt, m, 4)
+
+	stepped, err := acc.Flush()
+	require.NoError(t, err)
+	assert.False(t, stepped)
+
+	accGrad(m.W, 1, 2, 3)
+	_, _ = acc.Step()
+	accGrad(m.W, 3, 4, 5)
+	_, _ = acc.Step()
+
+	stepped, err = acc.Flush()
+	require.NoError(t, err)
+	assert.True(t, stepped)
+	// the average is on the pending micro-steps: (2, 3, 4)
+	assert.InDeltaSlice(t, []float64{0.8, 1.7, 2.6}, m.W.Data().F64(), 1.0e-12)
+	assert.Equal(t, 0, acc.Pending())
+}
+
+func TestGradAccumulator_WithClipper(t *testing.T) {
+	m := newAccumulatorTestModel()
+	opt := optimizers.New(nn.Parameters(m), sgd.New[float64](sgd.NewConfig(1, 0, false)))
+	acc := optimizers.NewGradAccumulator(opt, m, 2).
+		WithClipper(&gradclipper.ValueClipper{Value: 0.5})
+
+	accGrad(m.W, 2, 0.4, -2)
+	_, _ = acc.Step()
+	accGrad(m.W, 0, 0.2, 0)
+	stepped, err := acc.Step()
+	require.NoError(t, err)
+	assert.True(t, stepped)
+	// clipping is applied to the average (1, 0.3, -1), not to the sum
+	assert.InDeltaSlice(t, []float64{0.5, 1.7, 3.5}, m.W.Data().F64(), 1.0e-12)
+}
+
+func TestGradAccumulator_Embeddings(t *testing.T) {
+	type model struct {
+		nn.Module
+		Emb *embedding.Model
+	}
+	m := &model{Emb: embedding.New[float64](4, 2)}
+	opt := optimizers.New(nn.Parameters(m), sgd.New[float64](sgd.NewConfig(1, 0, false)))
+	acc := optimizers.NewGradAccumulator(opt, m, 2)
+
+	microBatches := [][]int{{0, 2}, {2}}
+	for _, batch := range microBatches {
+		for _, e := range m.Emb.MustEncode(batch) {
+			e.AccGrad(mat.NewDense[float64](mat.WithBacking([]float64{1, 2})))
+		}
+		_, err := acc.Step()
+		require.NoError(t, err)
+	}
+
+	assert.InDeltaSlice(t, []float64{-0.5, -1}, m.Emb.Weights[0].Data().F64(), 1.0e-12)
+	assert.InDeltaSlice(t, []float64{0, 0}, m.Emb.Weights[1].Data().F64(), 1.0e-12)
+	assert.InDeltaSlice(t, []float64{-1, -2}, m.Emb.Weights[2].Data().F64(), 1.0e-12)
+	assert.Equal(t, 0, m.Emb.CountEmbedWithGrad())
+
+	// the next accumulation only involves the embeddings used since then
+	for _, e := range m.Emb.MustEncode([]int{3}) {
+		e.AccGrad(mat.NewDense[float64](mat.WithBacking([]float64{2, 2})))
+	}
+	assert.Equal(t, 1, m.Emb.CountEmbedWithGrad())
+	stepped, err := acc.Step()
+	require.NoError(t, err)
+	assert.False(t, stepped)
+	stepped, err = acc.Flush()
+	require.NoError(t, err)
+	assert.True(t, stepped)
+	assert.InDeltaSlice(t, []float64{-2, -2}, m.Emb.Weights[3].Data().F64(), 1.0e-12)
+	assert.InDeltaSlice(t, []float64{-0.5, -1}, m.Emb.Weights[0].Data().F64(), 1.0e-12)
+	assert.Equal(t, 0, m.Emb.CountEmbedWithGrad())
+}
+
+func TestNewGradAccumulator_Panics(t *testing.T) {
+	m := newAccumulatorTestModel()
+	opt := optimizers.New(nn.Parameters(m), sgd.New[float64](sgd.NewConfig(0.1, 0, false)))
+	assert.Panics(t, func() { optimizers.NewGradAccumulator(opt, m, 0) })
+}
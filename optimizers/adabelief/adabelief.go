@@ -0,0 +1,128 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adabelief
+
+import (
+	"encoding/gob"
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+// Config provides configuration settings for an AdaBelief optimizer.
+type Config struct {
+	StepSize float64
+	Beta1    float64
+	Beta2    float64
+	Epsilon  float64
+}
+
+// NewConfig returns a new AdaBelief Config.
+// It panics if beta1 or beta2 are not in the range [0.0, 1.0).
+func NewConfig(stepSize, beta1, beta2, epsilon float64) Config {
+	if !(beta1 >= 0.0 && beta1 < 1.0) {
+		panic("adabelief: `beta1` must be in the range [0.0, 1.0)")
+	}
+	if !(beta2 >= 0.0 && beta2 < 1.0) {
+		panic("adabelief: `beta2` must be in the range [0.0, 1.0)")
+	}
+	return Config{
+		StepSize: stepSize,
+		Beta1:    beta1,
+		Beta2:    beta2,
+		Epsilon:  epsilon,
+	}
+}
+
+// NewDefaultConfig returns a new Config with generically reasonable default values.
+func NewDefaultConfig() Config {
+	return Config{
+		StepSize: 0.001,
+		Beta1:    0.9,
+		Beta2:    0.999,
+		Epsilon:  1.0e-16,
+	}
+}
+
+// AdaBelief is a variant of Adam which adapts the step size according to
+// the "belief" in the gradients, that is the variance of the gradients
+// around their exponential moving average, instead of their second moment.
+// References:
+//
+//	AdaBelief Optimizer: Adapting Stepsizes by the Belief in Observed Gradients
+//	https://arxiv.org/abs/2010.07468
+type AdaBelief[T float.DType] struct {
+	Config
+}
+
+// New returns a new AdaBelief optimizer, initialized according to the given configuration.
+func New[T float.DType](c Config) *AdaBelief[T] {
+	return &AdaBelief[T]{Config: c}
+}
+
+// LearningRate returns the step size.
+func (o *AdaBelief[_]) LearningRate() float64 {
+	return o.StepSize
+}
+
+// SetLearningRate sets the step size.
+func (o *AdaBelief[_]) SetLearningRate(lr float64) {
+	o.StepSize = lr
+}
+
+type State struct {
+	M        mat.Matrix // first moment vector
+	S        mat.Matrix // second moment of the gradient deviation
+	TimeStep int        // number of updates of the parameter
+}
+
+func init() {
+	gob.Register(&State{})
+}
+
+func (o *AdaBelief[T]) newState(shape ...int) *State {
+	return &State{
+		M: mat.NewDense[T](mat.WithShape(shape...)),
+		S: mat.NewDense[T](mat.WithShape(shape...)),
+	}
+}
+
+// m = m*beta1 + grads*(1.0-beta1)
+// s = s*beta2 + (grads-m)^2*(1.0-beta2) + eps
+// d = (m / (sqrt(s / (1-beta2^t)) + eps)) * lr / (1-beta1^t)
+func (o *AdaBelief[T]) calculateParamUpdate(grads mat.Matrix, state *State) mat.Matrix {
+	state.TimeStep++
+	ts := float64(state.TimeStep)
+
+	state.M.ProdScalarInPlace(o.Beta1)
+	state.M.AddInPlace(grads.ProdScalar(1.0 - o.Beta1))
+
+	diff := grads.Sub(state.M)
+	state.S.ProdScalarInPlace(o.Beta2)
+	state.S.AddInPlace(diff.ProdInPlace(diff).ProdScalarInPlace(1.0 - o.Beta2))
+	state.S.AddScalarInPlace(o.Epsilon)
+
+	denom := state.S.ProdScalar(1.0 / (1.0 - math.Pow(o.Beta2, ts))).Sqrt().AddScalarInPlace(o.Epsilon)
+	return state.M.Div(denom).ProdScalarInPlace(o.StepSize / (1.0 - math.Pow(o.Beta1, ts)))
+}
+
+func (o *AdaBelief[T]) OptimizeParams(param *nn.Param) error {
+	if param.State == nil {
+		param.State = o.newState(param.Value().Shape()...)
+	}
+
+	state, ok := param.State.(*State)
+	if !ok {
+		return fmt.Errorf("unsupported state type: %T, expected %T", param.State, &State{})
+	}
+
+	param.SubInPlace(o.calculateParamUpdate(param.Grad().(mat.Matrix), state))
+	param.ZeroGrad()
+
+	return nil
+}
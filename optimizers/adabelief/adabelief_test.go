@@ -0,0 +1,117 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adabelief
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+// The expected values follow the update of adabelief-pytorch 0.2, the
+// implementation of the authors of the paper, evaluated in float64 without
+// weight decay and rectification.
+func Test_Update(t *testing.T) {
+	t.Run("float32", testUpdate[float32])
+	t.Run("float64", testUpdate[float64])
+}
+
+func testUpdate[T float.DType](t *testing.T) {
+	updater := New[T](NewConfig(
+		0.001,   // step size
+		0.9,     // beta1
+		0.999,   // beta2
+		1.0e-16, // epsilon
+	))
+
+	params := mat.NewDense[T](mat.WithBacking([]T{0.4, 0.4, 0.5, 1.0, 0.8}))
+	grads := mat.NewDense[T](mat.WithBacking([]T{0.9, 0.7, 0.4, 0.8, 0.1}))
+
+	supp := updater.newState(params.Shape()...)
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp))
+
+	assert.InDeltaSlice(t, []T{0.398888888889, 0.398888888889, 0.498888888889, 0.998888888889, 0.798888888889}, params.Data(), 1.0e-6)
+
+}
+
+func Test_Update2(t *testing.T) {
+	t.Run("float32", testUpdate2[float32])
+	t.Run("float64", testUpdate2[float64])
+}
+
+func testUpdate2[T float.DType](t *testing.T) {
+	updater := New[T](NewConfig(
+		0.001,   // step size
+		0.9,     // beta1
+		0.999,   // beta2
+		1.0e-16, // epsilon
+	))
+
+	params := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		1.4, 1.3, 0,
+		-0.8, 0.16, 0.65,
+		0.7, -0.4, 0.2,
+	}))
+
+	grads := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.5, 0.3, -0.1,
+		-0.6, -0.4, -1.0,
+		0.5, -0.6, 0.1,
+	}))
+
+	supp := updater.newState(params.Shape()...)
+
+	// === First iteration
+
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp))
+
+	assert.InDeltaSlice(t, []T{
+		0.05, 0.03, -0.01,
+		-0.06, -0.04, -0.1,
+		0.05, -0.06, 0.01,
+	}, supp.M.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		0.0002025, 7.29000000001e-05, 8.1000000001e-06,
+		0.0002916, 0.0001296, 0.00081,
+		0.0002025, 0.0002916, 8.1000000001e-06,
+	}, supp.S.Data(), 1.0e-8)
+
+	assert.InDeltaSlice(t, []T{
+		1.39888888889, 1.29888888889, 0.0011111111111,
+		-0.798888888889, 0.161111111111, 0.651111111111,
+		0.698888888889, -0.398888888889, 0.198888888889,
+	}, params.Data(), 1.0e-6)
+
+	// === Second iteration
+
+	grads2 := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.7, 0.44, -0.66,
+		-0.56, 0.4, 1.4,
+		0.44, 1.44, 2.44,
+	}))
+
+	params.SubInPlace(updater.calculateParamUpdate(grads2, supp))
+
+	assert.InDeltaSlice(t, []T{
+		0.115, 0.071, -0.075,
+		-0.11, 0.004, 0.05,
+		0.089, 0.09, 0.253,
+	}, supp.M.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		0.0005445225, 0.0002089881, 0.0003503169,
+		0.0004938084, 0.0002862864, 0.00263169,
+		0.0003254985, 0.0021138084, 0.0047910609,
+	}, supp.S.Data(), 1.0e-8)
+
+	assert.InDeltaSlice(t, []T{
+		1.39772919673, 1.29773317491, 0.00205404992962,
+		-0.797724048972, 0.16105548068, 0.650881757578,
+		0.697728059734, -0.399349529789, 0.198028772007,
+	}, params.Data(), 1.0e-6)
+}
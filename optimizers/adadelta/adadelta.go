@@ -0,0 +1,117 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adadelta
+
+import (
+	"encoding/gob"
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+// Config provides configuration settings for an AdaDelta optimizer.
+type Config struct {
+	LR      float64
+	Rho     float64
+	Epsilon float64
+}
+
+// NewConfig returns a new AdaDelta Config.
+// It panics if rho is not in the range [0.0, 1.0).
+func NewConfig(lr, rho, epsilon float64) Config {
+	if !(rho >= 0.0 && rho < 1.0) {
+		panic("adadelta: `rho` must be in the range [0.0, 1.0)")
+	}
+	return Config{
+		LR:      lr,
+		Rho:     rho,
+		Epsilon: epsilon,
+	}
+}
+
+// NewDefaultConfig returns a new Config with generically reasonable default values.
+func NewDefaultConfig() Config {
+	return Config{
+		LR:      1.0,
+		Rho:     0.9,
+		Epsilon: 1.0e-6,
+	}
+}
+
+// AdaDelta adapts the learning rate of each parameter using a moving window
+// of its gradient updates, instead of accumulating all its past gradients
+// as AdaGrad does.
+// References:
+//
+//	ADADELTA: An Adaptive Learning Rate Method
+//	https://arxiv.org/abs/1212.5701
+type AdaDelta[T float.DType] struct {
+	Config
+}
+
+// New returns a new AdaDelta optimizer, initialized according to the given configuration.
+func New[T float.DType](c Config) *AdaDelta[T] {
+	return &AdaDelta[T]{Config: c}
+}
+
+// LearningRate returns the learning rate.
+func (o *AdaDelta[_]) LearningRate() float64 {
+	return o.LR
+}
+
+// SetLearningRate sets the learning rate.
+func (o *AdaDelta[_]) SetLearningRate(lr float64) {
+	o.LR = lr
+}
+
+type State struct {
+	V mat.Matrix // running average of the squared gradients
+	U mat.Matrix // running average of the squared updates
+}
+
+func init() {
+	gob.Register(&State{})
+}
+
+func (o *AdaDelta[T]) newState(shape ...int) *State {
+	return &State{
+		V: mat.NewDense[T](mat.WithShape(shape...)),
+		U: mat.NewDense[T](mat.WithShape(shape...)),
+	}
+}
+
+// v = v*rho + (grads*grads)*(1.0-rho)
+// delta = sqrt(u + eps) / sqrt(v + eps) * grads
+// u = u*rho + (delta*delta)*(1.0-rho)
+// d = delta * lr
+func (o *AdaDelta[T]) calculateParamUpdate(grads mat.Matrix, state *State) mat.Matrix {
+	state.V.ProdScalarInPlace(o.Rho)
+	state.V.AddInPlace(grads.Prod(grads).ProdScalarInPlace(1.0 - o.Rho))
+
+	std := state.V.AddScalar(o.Epsilon).Sqrt()
+	delta := state.U.AddScalar(o.Epsilon).Sqrt().DivInPlace(std).ProdInPlace(grads)
+
+	state.U.ProdScalarInPlace(o.Rho)
+	state.U.AddInPlace(delta.Prod(delta).ProdScalarInPlace(1.0 - o.Rho))
+	return delta.ProdScalarInPlace(o.LR)
+}
+
+func (o *AdaDelta[T]) OptimizeParams(param *nn.Param) error {
+	if param.State == nil {
+		param.State = o.newState(param.Value().Shape()...)
+	}
+
+	state, ok := param.State.(*State)
+	if !ok {
+		return fmt.Errorf("unsupported state type: %T, expected %T", param.State, &State{})
+	}
+
+	param.SubInPlace(o.calculateParamUpdate(param.Grad().(mat.Matrix), state))
+	param.ZeroGrad()
+
+	return nil
+}
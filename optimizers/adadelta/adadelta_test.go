@@ -0,0 +1,114 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adadelta
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+// The expected values are those of the update rule of torch.optim.Adadelta
+// (PyTorch), evaluated in float64.
+func Test_Update(t *testing.T) {
+	t.Run("float32", testUpdate[float32])
+	t.Run("float64", testUpdate[float64])
+}
+
+func testUpdate[T float.DType](t *testing.T) {
+	updater := New[T](NewConfig(
+		1.0,    // learning rate
+		0.9,    // rho
+		1.0e-6, // epsilon
+	))
+
+	params := mat.NewDense[T](mat.WithBacking([]T{0.4, 0.4, 0.5, 1.0, 0.8}))
+	grads := mat.NewDense[T](mat.WithBacking([]T{0.9, 0.7, 0.4, 0.8, 0.1}))
+
+	supp := updater.newState(params.Shape()...)
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp))
+
+	assert.InDeltaSlice(t, []T{0.39683774186, 0.396837754607, 0.496837821156, 0.996837747045, 0.796839302294}, params.Data(), 1.0e-6)
+
+}
+
+func Test_Update2(t *testing.T) {
+	t.Run("float32", testUpdate2[float32])
+	t.Run("float64", testUpdate2[float64])
+}
+
+func testUpdate2[T float.DType](t *testing.T) {
+	updater := New[T](NewConfig(
+		1.0,    // learning rate
+		0.9,    // rho
+		1.0e-6, // epsilon
+	))
+
+	params := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		1.4, 1.3, 0,
+		-0.8, 0.16, 0.65,
+		0.7, -0.4, 0.2,
+	}))
+
+	grads := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.5, 0.3, -0.1,
+		-0.6, -0.4, -1.0,
+		0.5, -0.6, 0.1,
+	}))
+
+	supp := updater.newState(params.Shape()...)
+
+	// === First iteration
+
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp))
+
+	assert.InDeltaSlice(t, []T{
+		0.025, 0.009, 0.001,
+		0.036, 0.016, 0.1,
+		0.025, 0.036, 0.001,
+	}, supp.V.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		9.999600016e-07, 9.99888901233e-07, 9.99000999001e-07,
+		9.99972222994e-07, 9.99937503906e-07, 9.999900001e-07,
+		9.999600016e-07, 9.99972222994e-07, 9.99000999001e-07,
+	}, supp.U.Data(), 1.0e-12)
+
+	assert.InDeltaSlice(t, []T{
+		1.39683778558, 1.29683789801, 0.00316069770621,
+		-0.796837766259, 0.163162178844, 0.653162261849,
+		0.696837785583, -0.396837766259, 0.196839302294,
+	}, params.Data(), 1.0e-6)
+
+	// === Second iteration
+
+	grads2 := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.7, 0.44, -0.66,
+		-0.56, 0.4, 1.4,
+		0.44, 1.44, 2.44,
+	}))
+
+	params.SubInPlace(updater.calculateParamUpdate(grads2, supp))
+
+	assert.InDeltaSlice(t, []T{
+		0.0715, 0.02746, 0.04446,
+		0.06376, 0.0304, 0.286,
+		0.04186, 0.23976, 0.59626,
+	}, supp.V.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		2.27054679159e-06, 2.30982132235e-06, 2.8575922402e-06,
+		1.88363474432e-06, 1.95250781596e-06, 2.27060872527e-06,
+		1.82491146163e-06, 2.62967349278e-06, 2.89508124121e-06,
+	}, supp.U.Data(), 1.0e-12)
+
+	assert.InDeltaSlice(t, []T{
+		1.3931356473, 1.29308300612, 0.00758618224589,
+		-0.793701431227, 0.159917854473, 0.649460076379,
+		0.693796490694, -0.400996730664, 0.192371662713,
+	}, params.Data(), 1.0e-6)
+}
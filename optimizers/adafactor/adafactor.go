@@ -0,0 +1,390 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adafactor
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/embedding"
+	"github.com/nlpodyssey/spago/optimizers"
+)
+
+// Config provides configuration settings for an Adafactor optimizer.
+type Config struct {
+	// LR is the external learning rate, used only if RelativeStep is false.
+	LR float64
+	// Eps1 is the regularization constant added to the squared gradients.
+	Eps1 float64
+	// Eps2 is the lower bound of the scale of the parameters.
+	Eps2 float64
+	// ClipThreshold is the threshold of the root mean square of the final
+	// update.
+	ClipThreshold float64
+	// DecayRate is the exponent of the decay of the second moment
+	// coefficient: beta2_t = 1 - t^DecayRate.
+	DecayRate float64
+	// Beta1 is the coefficient of the first moment; with 0, no first moment
+	// is kept, which is the main source of memory savings.
+	Beta1 float64
+	// WeightDecay is the decoupled weight decay coefficient.
+	WeightDecay float64
+	// ScaleParameter scales the learning rate by the root mean square of
+	// the parameter.
+	ScaleParameter bool
+	// RelativeStep computes a time-dependent learning rate, instead of LR.
+	RelativeStep bool
+	// WarmupInit makes the relative step grow linearly at the beginning.
+	WarmupInit bool
+}
+
+// NewConfig returns a new Adafactor Config.
+// It panics if beta1 is not in the range [0.0, 1.0), if decayRate is
+// positive, if both lr and relativeStep are set, or if warmupInit is set
+// without relativeStep.
+func NewConfig(
+	lr, eps1, eps2, clipThreshold, decayRate, beta1, weightDecay float64,
+	scaleParameter, relativeStep, warmupInit bool,
+) Config {
+	if !(beta1 >= 0.0 && beta1 < 1.0) {
+		panic("adafactor: `beta1` must be in the range [0.0, 1.0)")
+	}
+	if decayRate > 0 {
+		panic("adafactor: `decayRate` must be <= 0")
+	}
+	if lr != 0 && relativeStep {
+		panic("adafactor: cannot combine a manual `lr` with `relativeStep`")
+	}
+	if warmupInit && !relativeStep {
+		panic("adafactor: `warmupInit` requires `relativeStep`")
+	}
+	return Config{
+		LR:             lr,
+		Eps1:           eps1,
+		Eps2:           eps2,
+		ClipThreshold:  clipThreshold,
+		DecayRate:      decayRate,
+		Beta1:          beta1,
+		WeightDecay:    weightDecay,
+		ScaleParameter: scaleParameter,
+		RelativeStep:   relativeStep,
+		WarmupInit:     warmupInit,
+	}
+}
+
+// NewDefaultConfig returns a new Config with generically reasonable default
+// values, using the relative step and no first moment.
+func NewDefaultConfig() Config {
+	return Config{
+		Eps1:           1.0e-30,
+		Eps2:           1.0e-3,
+		ClipThreshold:  1.0,
+		DecayRate:      -0.8,
+		ScaleParameter: true,
+		RelativeStep:   true,
+	}
+}
+
+// Adafactor is an adaptive optimizer with sublinear memory cost: for each
+// matrix of parameters, the second moments are not stored in full, but
+// factored as the outer product of their running row and column means. This
+// makes it especially fit for large projection matrices. Vectors (i.e.
+// matrices with a single row or column) keep their full second moment,
+// unless they are the embeddings of an embedding.Model registered with
+// FactorEmbeddings.
+// References:
+//
+//	Adafactor: Adaptive Learning Rates with Sublinear Memory Cost
+//	https://arxiv.org/abs/1804.04235
+type Adafactor[T float.DType] struct {
+	Config
+
+	mu      sync.Mutex
+	tables  []*table             // in order of registration
+	tableOf map[*nn.Param]*table // the table of each registered embedding
+}
+
+var _ optimizers.StatefulStrategy = &Adafactor[float32]{}
+
+// table is the second moment shared by the embeddings of an embedding.Model,
+// seen as the rows of a matrix: it holds the running column means of their
+// squared gradients, while the running mean of each row is kept in the
+// State of the embedding.
+type table struct {
+	mu      sync.Mutex
+	C       mat.Matrix // running column means of the squared gradients
+	Updates int        // number of updates of the embeddings
+}
+
+// New returns a new Adafactor optimizer, initialized according to the given configuration.
+func New[T float.DType](c Config) *Adafactor[T] {
+	return &Adafactor[T]{Config: c}
+}
+
+// LearningRate returns the external learning rate, which is used only if
+// RelativeStep is false.
+func (o *Adafactor[_]) LearningRate() float64 {
+	return o.LR
+}
+
+// SetLearningRate sets the external learning rate, which is used only if
+// RelativeStep is false.
+func (o *Adafactor[_]) SetLearningRate(lr float64) {
+	o.LR = lr
+}
+
+type State struct {
+	Step int        // number of updates of the parameter
+	R    mat.Matrix // running row means of the squared gradients (factored, or 1×1 for the embeddings)
+	C    mat.Matrix // running column means of the squared gradients (factored)
+	V    mat.Matrix // running squared gradients (not factored)
+	M    mat.Matrix // first moment vector (Beta1 > 0)
+}
+
+func init() {
+	gob.Register(&State{})
+}
+
+// factored reports whether the second moments of a parameter of the given
+// shape are factored.
+func factored(shape []int) bool {
+	return len(shape) == 2 && shape[0] > 1 && shape[1] > 1
+}
+
+// FactorEmbeddings makes the embeddings of m share a factored second moment,
+// as the rows of a matrix: each embedding keeps the running mean of its
+// squared gradients, a scalar, while the running means of the columns are
+// kept once for the whole model, so the memory cost is linear in Size+Dim
+// instead of Size*Dim. Since the embeddings are updated separately, and
+// only when they have gradients, the column means are updated by each
+// embedding in turn: when several embeddings are optimized concurrently,
+// the result depends slightly on the order of their updates.
+//
+// The column means are saved with the state of the optimizer (see
+// optimizers.Optimizer.State), provided that the models are registered in
+// the same order before the state is restored.
+//
+// It returns the optimizer for convenience.
+func (o *Adafactor[T]) FactorEmbeddings(m *embedding.Model) *Adafactor[T] {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	t := &table{C: mat.NewDense[T](mat.WithShape(m.Dim, 1))}
+	if o.tableOf == nil {
+		o.tableOf = make(map[*nn.Param]*table)
+	}
+	for _, w := range m.Weights {
+		o.tableOf[w] = t
+	}
+	o.tables = append(o.tables, t)
+	return o
+}
+
+// tableFor returns the table of the embedding, or nil if the parameter is
+// not an embedding registered with FactorEmbeddings.
+func (o *Adafactor[T]) tableFor(param *nn.Param) *table {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.tableOf[param]
+}
+
+// tableState is the state of a table, encoded by MarshalState.
+type tableState struct {
+	C       []float64
+	Updates int
+}
+
+// MarshalState returns an encoding of the column means of the embeddings
+// registered with FactorEmbeddings.
+func (o *Adafactor[T]) MarshalState() ([]byte, error) {
+	o.mu.Lock()
+	states := make([]tableState, len(o.tables))
+	for i, t := range o.tables {
+		t.mu.Lock()
+		states[i] = tableState{C: t.C.Data().F64(), Updates: t.Updates}
+		t.mu.Unlock()
+	}
+	o.mu.Unlock()
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(states); err != nil {
+		return nil, fmt.Errorf("adafactor: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalState restores a state encoded by MarshalState. The embeddings
+// must have been registered with FactorEmbeddings in the same order.
+func (o *Adafactor[T]) UnmarshalState(data []byte) error {
+	var states []tableState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&states); err != nil {
+		return fmt.Errorf("adafactor: %w", err)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(states) != len(o.tables) {
+		return fmt.Errorf("adafactor: state of %d embedding models, expected %d", len(states), len(o.tables))
+	}
+	for i, t := range o.tables {
+		if len(states[i].C) != t.C.Size() {
+			return fmt.Errorf("adafactor: embedding model %d: state of size %d, expected %d", i, len(states[i].C), t.C.Size())
+		}
+	}
+	for i, t := range o.tables {
+		t.mu.Lock()
+		t.C.SetData(float.Make(states[i].C...))
+		t.Updates = states[i].Updates
+		t.mu.Unlock()
+	}
+	return nil
+}
+
+func (o *Adafactor[T]) newState(shape ...int) *State {
+	s := &State{}
+	if factored(shape) {
+		s.R = mat.NewDense[T](mat.WithShape(shape[0], 1))
+		s.C = mat.NewDense[T](mat.WithShape(1, shape[1]))
+	} else {
+		s.V = mat.NewDense[T](mat.WithShape(shape...))
+	}
+	if o.Beta1 > 0 {
+		s.M = mat.NewDense[T](mat.WithShape(shape...))
+	}
+	return s
+}
+
+// newEmbeddingState returns the state of an embedding registered with
+// FactorEmbeddings, whose column means are kept by its table.
+func (o *Adafactor[T]) newEmbeddingState(shape ...int) *State {
+	s := &State{R: mat.NewDense[T](mat.WithShape(1, 1))}
+	if o.Beta1 > 0 {
+		s.M = mat.NewDense[T](mat.WithShape(shape...))
+	}
+	return s
+}
+
+// stepSize returns the learning rate of the current step of a parameter.
+func (o *Adafactor[T]) stepSize(step int, weights mat.Matrix) float64 {
+	lr := o.LR
+	if o.RelativeStep {
+		minStep := 1.0e-2
+		if o.WarmupInit {
+			minStep = 1.0e-6 * float64(step)
+		}
+		lr = math.Min(minStep, 1.0/math.Sqrt(float64(step)))
+	}
+	if o.ScaleParameter {
+		lr *= math.Max(o.Eps2, rms(weights))
+	}
+	return lr
+}
+
+// g2 = grads*grads + eps1
+// beta2 = 1 - t^decayRate
+// if factored:
+//   - r = r*beta2 + rowMean(g2)*(1.0-beta2)
+//   - c = c*beta2 + colMean(g2)*(1.0-beta2)
+//   - u = grads / sqrt(outer(r/mean(r), c))
+//
+// otherwise:
+//   - v = v*beta2 + g2*(1.0-beta2)
+//   - u = grads / sqrt(v)
+//
+// if an embedding, with the shared column means c:
+//   - r = r*beta2 + mean(g2)*(1.0-beta2)
+//   - c = c*beta2' + g2*(1.0-beta2'), with beta2' of the updates of c
+//   - u = grads / sqrt(r*c/mean(c))
+//
+// u = u / max(1, rms(u)/clipThreshold) * lr
+// if beta1 > 0: m = m*beta1 + u*(1.0-beta1); u = m
+// d = u + weights*weightDecay*lr
+//
+// The table is nil unless the parameter is an embedding.
+func (o *Adafactor[T]) calculateParamUpdate(grads mat.Matrix, state *State, weights mat.Matrix, t *table) mat.Matrix {
+	state.Step++
+	lr := o.stepSize(state.Step, weights)
+	beta2 := 1.0 - math.Pow(float64(state.Step), o.DecayRate)
+
+	sq := grads.Prod(grads).AddScalarInPlace(o.Eps1)
+	var update mat.Matrix
+	if t != nil && state.V == nil && state.C == nil {
+		rowMean := sq.Sum().Item().F64() / float64(sq.Size())
+		state.R.ProdScalarInPlace(beta2)
+		state.R.AddScalarInPlace(rowMean * (1.0 - beta2))
+
+		t.mu.Lock()
+		t.Updates++
+		beta2c := 1.0 - math.Pow(float64(t.Updates), o.DecayRate)
+		t.C.ProdScalarInPlace(beta2c)
+		t.C.AddInPlace(sq.ProdScalar(1.0 - beta2c))
+		c := t.C.Clone()
+		t.mu.Unlock()
+
+		scale := state.R.Item().F64() * float64(c.Size()) / c.Sum().Item().F64()
+		update = grads.Div(c.ProdScalarInPlace(scale).Sqrt())
+	} else if state.V == nil {
+		shape := grads.Shape()
+		rows, cols := shape[0], shape[1]
+		rowMean := sq.Mul(ones[T](cols, 1)).ProdScalarInPlace(1.0 / float64(cols))
+		colMean := ones[T](1, rows).Mul(sq).ProdScalarInPlace(1.0 / float64(rows))
+		state.R.ProdScalarInPlace(beta2)
+		state.R.AddInPlace(rowMean.ProdScalarInPlace(1.0 - beta2))
+		state.C.ProdScalarInPlace(beta2)
+		state.C.AddInPlace(colMean.ProdScalarInPlace(1.0 - beta2))
+
+		r := state.R.ProdScalar(float64(rows) / state.R.Sum().Item().F64()).Sqrt()
+		update = grads.Div(r.Mul(state.C.Sqrt()))
+	} else {
+		state.V.ProdScalarInPlace(beta2)
+		state.V.AddInPlace(sq.ProdScalarInPlace(1.0 - beta2))
+		update = grads.Div(state.V.Sqrt())
+	}
+
+	update.ProdScalarInPlace(lr / math.Max(1.0, rms(update)/o.ClipThreshold))
+
+	if state.M != nil {
+		state.M.ProdScalarInPlace(o.Beta1)
+		state.M.AddInPlace(update.ProdScalar(1.0 - o.Beta1))
+		update = state.M.Clone()
+	}
+	if o.WeightDecay != 0 {
+		update.AddInPlace(weights.ProdScalar(o.WeightDecay * lr))
+	}
+	return update
+}
+
+// rms returns the root mean square of the values of the matrix.
+func rms(m mat.Matrix) float64 {
+	return math.Sqrt(m.Prod(m).Sum().Item().F64() / float64(m.Size()))
+}
+
+func ones[T float.DType](rows, cols int) mat.Matrix {
+	return mat.NewDense[T](mat.WithShape(rows, cols), mat.WithBacking(mat.CreateInitializedSlice[T](rows*cols, 1)))
+}
+
+func (o *Adafactor[T]) OptimizeParams(param *nn.Param) error {
+	t := o.tableFor(param)
+	if param.State == nil {
+		if t != nil {
+			param.State = o.newEmbeddingState(param.Value().Shape()...)
+		} else {
+			param.State = o.newState(param.Value().Shape()...)
+		}
+	}
+
+	state, ok := param.State.(*State)
+	if !ok {
+		return fmt.Errorf("unsupported state type: %T, expected %T", param.State, &State{})
+	}
+
+	param.SubInPlace(o.calculateParamUpdate(param.Grad().(mat.Matrix), state, param.Value().(mat.Matrix), t))
+	param.ZeroGrad()
+
+	return nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adafactor
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The expected values of these tests follow the Adafactor of Hugging Face
+// Transformers (transformers.optimization.Adafactor), evaluated in float64.
+// Its defaults are the ones of NewDefaultConfig.
+
+func Test_Update(t *testing.T) {
+	t.Run("float32", testUpdate[float32])
+	t.Run("float64", testUpdate[float64])
+}
+
+func testUpdate[T float.DType](t *testing.T) {
+	updater := New[T](NewDefaultConfig())
+
+	params := mat.NewDense[T](mat.WithBacking([]T{0.4, 0.4, 0.5, 1.0, 0.8}))
+	grads := mat.NewDense[T](mat.WithBacking([]T{0.9, 0.7, 0.4, 0.8, 0.1}))
+
+	supp := updater.newState(params.Shape()...)
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp, params, nil))
+
+	assert.InDeltaSlice(t, []T{0.81, 0.49, 0.16, 0.64, 0.01}, supp.V.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{0.393351691945, 0.393351691945, 0.493351691945, 0.993351691945, 0.793351691945}, params.Data(), 1.0e-6)
+}
+
+func Test_UpdateFactored(t *testing.T) {
+	t.Run("float32", testUpdateFactored[float32])
+	t.Run("float64", testUpdateFactored[float64])
+}
+
+func testUpdateFactored[T float.DType](t *testing.T) {
+	updater := New[T](NewDefaultConfig())
+
+	params := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		1.4, 1.3, 0,
+		-0.8, 0.16, 0.65,
+		0.7, -0.4, 0.2,
+	}))
+
+	grads := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.5, 0.3, -0.1,
+		-0.6, -0.4, -1.0,
+		0.5, -0.6, 0.1,
+	}))
+
+	supp := updater.newState(params.Shape()...)
+
+	// === First iteration
+
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp, params, nil))
+
+	assert.InDeltaSlice(t, []T{0.116666666667, 0.506666666667, 0.206666666667}, supp.R.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{0.286666666667, 0.203333333333, 0.34}, supp.C.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		1.38912149938, 1.29224994831, 0.00199778236933,
+		-0.793735844717, 0.164958559901, 0.659586507234,
+		0.691826515975, -0.388354107625, 0.198498980433,
+	}, params.Data(), 1.0e-6)
+
+	// === Second iteration
+
+	grads2 := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.7, 0.44, -0.66,
+		-0.56, 0.4, 1.4,
+		0.44, 1.44, 2.44,
+	}))
+
+	params.SubInPlace(updater.calculateParamUpdate(grads2, supp, params, nil))
+
+	assert.InDeltaSlice(t, []T{0.263929795777, 0.681575136188, 1.66183774278}, supp.R.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{0.312933569051, 0.551235775117, 1.74317333057}, supp.C.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		1.37521579436, 1.28566420153, 0.00755291840304,
+		-0.786813227137, 0.161232930225, 0.652253769706,
+		0.688343162469, -0.396943545549, 0.190314511757,
+	}, params.Data(), 1.0e-6)
+}
+
+func Test_UpdateExternalLR(t *testing.T) {
+	t.Run("float32", testUpdateExternalLR[float32])
+	t.Run("float64", testUpdateExternalLR[float64])
+}
+
+func testUpdateExternalLR[T float.DType](t *testing.T) {
+	updater := New[T](NewConfig(
+		0.01,    // learning rate
+		1.0e-30, // eps1
+		1.0e-3,  // eps2
+		1.0,     // clip threshold
+		-0.8,    // decay rate
+		0.9,     // beta1
+		0.1,     // weight decay
+		false,   // scale parameter
+		false,   // relative step
+		false,   // warmup init
+	))
+
+	params := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		1.4, 1.3, 0,
+		-0.8, 0.16, 0.65,
+		0.7, -0.4, 0.2,
+	}))
+
+	grads := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.5, 0.3, -0.1,
+		-0.6, -0.4, -1.0,
+		0.5, -0.6, 0.1,
+	}))
+
+	supp := updater.newState(params.Shape()...)
+
+	// === First iteration
+
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp, params, nil))
+
+	assert.InDeltaSlice(t, []T{
+		0.00140076852756, 0.000997934263113, -0.000257244152152,
+		-0.000806603031144, -0.000638488234353, -0.00123440519012,
+		0.00105245746503, -0.00149958161417, 0.000193278563117,
+	}, supp.M.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		1.39719923147, 1.29770206574, 0.000257244152152,
+		-0.798393396969, 0.160478488234, 0.65058440519,
+		0.698247542535, -0.398100418386, 0.199606721437,
+	}, params.Data(), 1.0e-6)
+
+	// === Second iteration
+
+	grads2 := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.7, 0.44, -0.66,
+		-0.56, 0.4, 1.4,
+		0.44, 1.44, 2.44,
+	}))
+
+	params.SubInPlace(updater.calculateParamUpdate(grads2, supp, params, nil))
+
+	assert.InDeltaSlice(t, []T{
+		0.00306247904705, 0.00175146796839, -0.000951308768851,
+		-0.00162291881938, -9.19028296286e-05, -0.000160848595816,
+		0.00139855613797, -0.000236674432155, 0.00123442712895,
+	}, supp.M.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		1.39273955319, 1.2946528957, 0.00120829567685,
+		-0.795972084753, 0.160409912576, 0.650094669381,
+		0.696150738854, -0.397465643535, 0.198172687587,
+	}, params.Data(), 1.0e-6)
+}
+
+func Test_NewState(t *testing.T) {
+	updater := New[float32](NewDefaultConfig())
+
+	factored := updater.newState(1000, 64)
+	assert.Equal(t, []int{1000, 1}, factored.R.Shape())
+	assert.Equal(t, []int{1, 64}, factored.C.Shape())
+	assert.Nil(t, factored.V)
+	assert.Nil(t, factored.M)
+
+	vector := updater.newState(1, 64)
+	assert.Nil(t, vector.R)
+	assert.Nil(t, vector.C)
+	assert.Equal(t, []int{1, 64}, vector.V.Shape())
+}
+
+func Test_UpdateEmbeddings(t *testing.T) {
+	t.Run("float32", testUpdateEmbeddings[float32])
+	t.Run("float64", testUpdateEmbeddings[float64])
+}
+
+// The embeddings are the rows of a matrix, factored with a running mean per
+// row and running column means shared by the rows, updated row by row.
+func testUpdateEmbeddings[T float.DType](t *testing.T) {
+	emb := embedding.New[T](3, 5)
+	emb.Weights[0].SetData(float.Make[T](0.4, 0.4, 0.5, 1.0, 0.8))
+	emb.Weights[1].SetData(float.Make[T](0.1, -0.2, 0.3, 0.2, 0.5))
+	updater := New[T](NewDefaultConfig()).FactorEmbeddings(emb)
+
+	optimize := func(p *nn.Param, grads ...T) {
+		p.AccGrad(mat.NewDense[T](mat.WithBacking(grads)))
+		assert.NoError(t, updater.OptimizeParams(p))
+	}
+
+	// the first update matches the one of a vector (see Test_Update)
+	optimize(emb.Weights[0], 0.9, 0.7, 0.4, 0.8, 0.1)
+	assert.InDeltaSlice(t, []T{0.393351691945, 0.393351691945, 0.493351691945, 0.993351691945, 0.793351691945}, emb.Weights[0].Data(), 1.0e-6)
+
+	optimize(emb.Weights[1], 0.2, 0.5, -0.3, 0.1, 0.6)
+	assert.InDeltaSlice(t, []T{0.098811637242, -0.203035976347, 0.303123179500, 0.199316798684, 0.495293660940}, emb.Weights[1].Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{0.15}, emb.Weights[1].State.(*State).R.Data(), 1.0e-6)
+
+	optimize(emb.Weights[0], 0.3, -0.1, 0.2, 0.4, 0.5)
+	assert.InDeltaSlice(t, []T{0.389772565878, 0.394659441316, 0.489279454741, 0.988342505774, 0.787064257077}, emb.Weights[0].Data(), 1.0e-6)
+
+	state := emb.Weights[0].State.(*State)
+	assert.Equal(t, []int{1, 1}, state.R.Shape())
+	assert.Nil(t, state.C)
+	assert.Nil(t, state.V)
+	assert.Nil(t, emb.Weights[2].State)
+	assert.InDeltaSlice(t, []T{0.252416739894, 0.210078010306, 0.086660959270, 0.229094821354, 0.227207490896}, updater.tables[0].C.Data(), 1.0e-6)
+}
+
+func Test_MarshalState(t *testing.T) {
+	emb := embedding.New[float32](3, 2)
+	updater := New[float32](NewDefaultConfig()).FactorEmbeddings(emb)
+	emb.Weights[1].AccGrad(mat.NewDense[float32](mat.WithBacking([]float32{0.5, 0.1})))
+	assert.NoError(t, updater.OptimizeParams(emb.Weights[1]))
+
+	data, err := updater.MarshalState()
+	require.NoError(t, err)
+
+	resumed := New[float32](NewDefaultConfig()).FactorEmbeddings(embedding.New[float32](3, 2))
+	require.NoError(t, resumed.UnmarshalState(data))
+	assert.Equal(t, updater.tables[0].C.Data(), resumed.tables[0].C.Data())
+	assert.Equal(t, 1, resumed.tables[0].Updates)
+
+	other, err := New[float32](NewDefaultConfig()).FactorEmbeddings(embedding.New[float32](3, 4)).MarshalState()
+	require.NoError(t, err)
+	assert.Error(t, resumed.UnmarshalState(other))
+	other, err = New[float32](NewDefaultConfig()).MarshalState()
+	require.NoError(t, err)
+	assert.Error(t, resumed.UnmarshalState(other))
+	assert.Equal(t, updater.tables[0].C.Data(), resumed.tables[0].C.Data())
+}
@@ -59,6 +59,16 @@ func New[T float.DType](c Config) *AdaGrad[T] {
 	}
 }
 
+// LearningRate returns the learning rate.
+func (o *AdaGrad[_]) LearningRate() float64 {
+	return o.LR
+}
+
+// SetLearningRate sets the learning rate.
+func (o *AdaGrad[_]) SetLearningRate(lr float64) {
+	o.LR = lr
+}
+
 func (o *AdaGrad[T]) newState(shape ...int) *State {
 	return &State{
 		M: mat.NewDense[T](mat.WithShape(shape...)),
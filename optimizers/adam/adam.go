@@ -121,6 +121,17 @@ func (o *Adam) updateAlpha() {
 	o.Alpha = o.StepSize * math.Sqrt(1.0-math.Pow(o.Beta2, ts)) / (1.0 - math.Pow(o.Beta1, ts))
 }
 
+// LearningRate returns the step size.
+func (o *Adam) LearningRate() float64 {
+	return o.StepSize
+}
+
+// SetLearningRate sets the step size, updating the 'alpha' coefficient.
+func (o *Adam) SetLearningRate(lr float64) {
+	o.StepSize = lr
+	o.updateAlpha()
+}
+
 // v = v*beta1 + grads*(1.0-beta1)
 // m = m*beta2 + (grads*grads)*(1.0-beta2)
 // d = (v / (sqrt(m) + eps)) * alpha
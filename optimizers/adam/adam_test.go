@@ -238,3 +238,11 @@ func testAdamWUpdate2[T float.DType](t *testing.T) {
 		0.69796675, -0.3994073, 0.19821806,
 	}, params.Data(), 1.0e-5)
 }
+
+func TestAdam_SetLearningRate(t *testing.T) {
+	o := New(NewDefaultConfig())
+	alpha := o.Alpha
+	o.SetLearningRate(0.01)
+	assert.Equal(t, 0.01, o.LearningRate())
+	assert.InDelta(t, alpha*10, o.Alpha, 1e-12)
+}
@@ -12,6 +12,11 @@ import (
 	"github.com/nlpodyssey/spago/nn"
 )
 
+var (
+	_ GradClipper = &ValueClipper{}
+	_ GradClipper = &NormClipper{}
+)
+
 // GradClipper performs gradient clipping on a set of parameters.
 type GradClipper interface {
 	// ClipGrads clips the gradients in place.
@@ -60,9 +65,16 @@ func (c *NormClipper) calculateTotalNorm(gs []mat.Tensor) float64 {
 	return totalNorm
 }
 
-// ClipGradients clips the gradients, multiplying each parameter by the MaxNorm, divided by n-norm of the overall gradients.
-// NormType is the n-norm. Can be “Double.POSITIVE_INFINITY“ for infinity norm (default 2.0)
+// ClipGradients clips the gradients, like ClipGrads.
+//
+// Deprecated: use ClipGrads, which satisfies the GradClipper interface.
 func (c *NormClipper) ClipGradients(parameters nn.ParamChannelFunc) {
+	c.ClipGrads(parameters)
+}
+
+// ClipGrads clips the gradients, multiplying each parameter by the MaxNorm, divided by n-norm of the overall gradients.
+// NormType is the n-norm. Can be “Double.POSITIVE_INFINITY“ for infinity norm (default 2.0)
+func (c *NormClipper) ClipGrads(parameters nn.ParamChannelFunc) {
 	grads := collectGradients(parameters)
 	c.validateNormType()
 	totalNorm := c.calculateTotalNorm(grads)
@@ -76,12 +88,19 @@ func (c *NormClipper) ClipGradients(parameters nn.ParamChannelFunc) {
 }
 
 // collectGradients collects all the gradients from the parameters channel and returns them as a slice.
+// Parameters without gradients, or frozen (see nn.Freeze), are skipped. A parameter streamed more than once,
+// because it is shared (e.g. by embedding.Shared), is collected once.
 func collectGradients(parameters nn.ParamChannelFunc) []mat.Tensor {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	var allGrads []mat.Tensor
+	seen := make(map[*nn.Param]bool)
 	for param := range parameters(ctx) {
+		if seen[param] || !param.HasGrad() || nn.IsFrozen(param) {
+			continue
+		}
+		seen[param] = true
 		allGrads = append(allGrads, param.Grad())
 	}
 	return allGrads
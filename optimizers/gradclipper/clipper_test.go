@@ -11,6 +11,7 @@ import (
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/embedding"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -84,3 +85,36 @@ func buildTestGrads[T float.DType]() []*nn.Param {
 
 	return []*nn.Param{p1, p2}
 }
+
+type frozenTestModel struct {
+	nn.Module
+	P *nn.Param
+}
+
+func TestClipValue_SkipsFrozenParams(t *testing.T) {
+	params := buildTestGrads[float32]()
+	nn.Freeze(&frozenTestModel{P: params[1]})
+	assert.False(t, params[1].HasGrad(), "Freeze clears the gradients")
+	params[1].AccGrad(mat.NewDense[float32](mat.WithBacking([]float32{0.9, 0.7, 0.4, 0.8, 0.1})))
+
+	(&ValueClipper{Value: 0.7}).ClipGrads(nn.StreamParams(params))
+	assert.InDeltaSlice(t, []float32{0.9, 0.7, 0.4, 0.8, 0.1}, params[1].Grad().Data(), 1.0e-05)
+	assert.InDeltaSlice(t, []float32{0.5, 0.6, -0.7, -0.6}, mat.Data[float32](params[0].Grad())[:4], 1.0e-05)
+}
+
+func TestClipNorm_SharedParams(t *testing.T) {
+	type model struct {
+		nn.Module
+		Encoder *embedding.Model
+		Decoder embedding.Shared
+	}
+	emb := embedding.New[float32](3, 2)
+	m := &model{Encoder: emb, Decoder: embedding.Shared{Model: emb}}
+	for _, e := range emb.MustEncode([]int{1}) {
+		e.AccGrad(mat.NewDense[float32](mat.WithBacking([]float32{3, 4})))
+	}
+
+	// the shared embedding is counted once in the total norm, and clipped once
+	(&NormClipper{MaxNorm: 1, NormType: 2}).ClipGrads(nn.Parameters(m))
+	assert.InDeltaSlice(t, []float32{0.6, 0.8}, emb.Weights[1].Grad().Data(), 1.0e-06)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimizers
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/optimizers/schedule"
+)
+
+// ParamGroup is a group of parameters optimized with their own strategy,
+// e.g. to give the embeddings a different learning rate, or to exclude the
+// biases from the weight decay.
+type ParamGroup struct {
+	// Patterns select parameters of the model by path, with the syntax of
+	// nn.Freeze (e.g. "**.B").
+	Patterns []string
+	// Params lists parameters explicitly, besides the ones selected by the
+	// patterns.
+	Params []*nn.Param
+	// Strategy is the optimization strategy of the group. A nil strategy
+	// excludes the parameters from the optimization.
+	Strategy OptimizationStrategy
+	// Schedule is the optional learning rate schedule of the group (see
+	// Optimizer.WithSchedule).
+	Schedule schedule.Schedule
+}
+
+// NewWithGroups returns a new optimizer of the parameters of the model m.
+// Each parameter is optimized with the strategy of the first group
+// selecting it, or with the default strategy if no group does, unless the
+// default strategy is nil.
+// It panics if a group has a schedule, but a strategy not implementing
+// LearningRateStrategy.
+func NewWithGroups(m nn.Model, strategy OptimizationStrategy, groups ...ParamGroup) *Optimizer {
+	o := &Optimizer{
+		parameters: nn.Parameters(m),
+		strategy:   strategy,
+		groups:     groups,
+		groupOf:    make(map[*nn.Param]int),
+	}
+	assign := func(p *nn.Param, i int) {
+		if _, ok := o.groupOf[p]; !ok {
+			o.groupOf[p] = i
+		}
+	}
+	for i, g := range groups {
+		if _, ok := g.Strategy.(LearningRateStrategy); !ok && g.Schedule != nil {
+			panic(fmt.Sprintf("optimizers: strategy %T of group %d does not support learning rate schedules", g.Strategy, i))
+		}
+		for _, p := range g.Params {
+			assign(p, i)
+		}
+		if len(g.Patterns) > 0 {
+			for _, p := range nn.SelectParameters(m, g.Patterns...) {
+				assign(p.Param, i)
+			}
+		}
+	}
+	return o
+}
+
+// strategyFor returns the strategy optimizing the parameter.
+func (o *Optimizer) strategyFor(p *nn.Param) OptimizationStrategy {
+	if i, ok := o.groupOf[p]; ok {
+		return o.groups[i].Strategy
+	}
+	return o.strategy
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimizers_test
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/optimizers"
+	"github.com/nlpodyssey/spago/optimizers/adam"
+	"github.com/nlpodyssey/spago/optimizers/schedule"
+	"github.com/nlpodyssey/spago/optimizers/sgd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithGroups(t *testing.T) {
+	m := newStateTestModel()
+	before := nn.StateDict(m)
+
+	opt := optimizers.NewWithGroups(m, sgd.New[float32](sgd.NewConfig(0.1, 0, false)),
+		optimizers.ParamGroup{
+			Patterns: []string{"Layers.1"},
+			Strategy: sgd.New[float32](sgd.NewConfig(0.5, 0, false)),
+		},
+		optimizers.ParamGroup{
+			Patterns: []string{"**.B"},
+			Strategy: sgd.New[float32](sgd.NewConfig(1, 0, false)),
+		},
+		optimizers.ParamGroup{
+			Params: []*nn.Param{m.Layers[1].W}, // already selected by the first group
+		},
+	)
+	for _, p := range nn.NamedParameters(m) {
+		p.Param.AccGrad(p.Param.NewMatrix(mat.WithShape(p.Param.Shape()...)).AddScalar(1))
+	}
+	require.NoError(t, opt.Optimize())
+
+	after := nn.StateDict(m)
+	for name, delta := range map[string]float32{
+		"Layers.0.W": 0.1,
+		"Layers.0.B": 1,
+		"Layers.1.W": 0.5,
+		"Layers.1.B": 0.5,
+	} {
+		expected := before[name].SubScalar(float64(delta))
+		assert.InDeltaSlice(t, expected.Data().F32(), after[name].Data().F32(), 1e-6, name)
+	}
+}
+
+func TestNewWithGroups_Excluded(t *testing.T) {
+	m := newStateTestModel()
+	before := nn.StateDict(m)
+
+	opt := optimizers.NewWithGroups(m, nil, optimizers.ParamGroup{
+		Params:   []*nn.Param{m.Layers[0].W},
+		Strategy: sgd.New[float32](sgd.NewConfig(0.1, 0, false)),
+	})
+	for _, p := range nn.NamedParameters(m) {
+		p.Param.AccGrad(p.Param.NewMatrix(mat.WithShape(p.Param.Shape()...)).AddScalar(1))
+	}
+	require.NoError(t, opt.Optimize())
+
+	after := nn.StateDict(m)
+	assert.NotEqual(t, before["Layers.0.W"], after["Layers.0.W"])
+	for _, name := range []string{"Layers.0.B", "Layers.1.W", "Layers.1.B"} {
+		assert.Equal(t, before[name], after[name], name)
+	}
+	assert.True(t, m.Layers[1].W.HasGrad())
+}
+
+func TestNewWithGroups_ScheduleAndState(t *testing.T) {
+	newOptimizer := func(m *stateTestModel) (*optimizers.Optimizer, *sgd.SGD[float32], *adam.Adam) {
+		embeddings := sgd.New[float32](sgd.NewConfig(1, 0.9, false))
+		a := adam.New(adam.NewDefaultConfig())
+		opt := optimizers.NewWithGroups(m, a, optimizers.ParamGroup{
+			Patterns: []string{"Layers.0"},
+			Strategy: embeddings,
+			Schedule: schedule.StepDecay(1, 1, 0.5),
+		}).WithSchedule(schedule.Constant(0.01), optimizers.PerStep)
+		return opt, embeddings, a
+	}
+
+	m := newStateTestModel()
+	opt, group, a := newOptimizer(m)
+	for i := 0; i < 3; i++ {
+		stateTestStep(t, m, opt, stepper{a, a.IncExample}, i)
+	}
+	assert.Equal(t, 0.25, group.LearningRate())
+	assert.Equal(t, 0.01, a.LearningRate())
+
+	state, err := opt.State(m)
+	require.NoError(t, err)
+	require.Len(t, state.Groups, 1)
+	assert.Equal(t, 0.25, state.Groups[0]["Alpha"])
+	assert.Equal(t, []float64{0.01, 0.25}, state.LearningRates)
+
+	resumed := nn.Clone(m)
+	opt2, group2, a2 := newOptimizer(resumed)
+	require.NoError(t, opt2.SetState(resumed, state))
+	assert.Equal(t, 0.25, group2.Alpha)
+	for i := 3; i < 5; i++ {
+		stateTestStep(t, m, opt, stepper{a, a.IncExample}, i)
+		stateTestStep(t, resumed, opt2, stepper{a2, a2.IncExample}, i)
+	}
+	assert.Equal(t, nn.StateDict(m), nn.StateDict(resumed))
+
+	opt3 := optimizers.New(nn.Parameters(m), a2)
+	assert.EqualError(t, opt3.SetState(m, state), "optimizers: unexpected number of parameter groups 1, expected 0")
+}
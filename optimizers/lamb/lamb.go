@@ -102,6 +102,17 @@ func (o *Lamb[T]) updateAlpha() {
 	o.Alpha = o.StepSize * math.Sqrt(1.0-math.Pow(o.Beta2, ts)) / (1.0 - math.Pow(o.Beta1, ts))
 }
 
+// LearningRate returns the step size.
+func (o *Lamb[_]) LearningRate() float64 {
+	return o.StepSize
+}
+
+// SetLearningRate sets the step size, updating the 'alpha' coefficient.
+func (o *Lamb[_]) SetLearningRate(lr float64) {
+	o.StepSize = lr
+	o.updateAlpha()
+}
+
 // CalcDelta returns the difference between the current params and where the method wants it to be.
 func (o *Lamb[T]) CalcDelta(state *State, cur mat.Matrix, grads mat.Matrix) mat.Matrix {
 	return o.calculateParamUpdate(grads, state, cur.Value().(mat.Matrix))
@@ -0,0 +1,419 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lbfgs implements the L-BFGS quasi-Newton optimizer.
+//
+// Unlike the optimization strategies of the other packages, which update
+// each parameter independently, L-BFGS works on all the parameters at once,
+// and needs to evaluate the loss and the gradients several times per step.
+// For this reason it does not implement optimizers.OptimizationStrategy, but
+// takes a Closure re-evaluating the model.
+//
+// L-BFGS is a full-batch method, suitable for small, smooth and possibly
+// convex problems, such as logistic regression or the fine-tuning of the
+// transition scores of a CRF, where it usually converges much faster than
+// first-order methods.
+package lbfgs
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+// Closure re-evaluates the model: it computes the loss, typically on the
+// whole training set, and back-propagates it (e.g. with ag.Backward),
+// returning the loss. The gradients of the parameters are zeroed by the
+// optimizer before each call.
+type Closure func() (mat.Tensor, error)
+
+// LineSearch is the line search algorithm used to choose the step length.
+type LineSearch int
+
+const (
+	// NoLineSearch uses the fixed step length LR.
+	NoLineSearch LineSearch = iota
+	// StrongWolfe uses a line search satisfying the strong Wolfe conditions.
+	StrongWolfe
+)
+
+// Config provides configuration settings for an L-BFGS optimizer.
+type Config struct {
+	// LR is the step length, or the initial step length of the line search.
+	LR float64
+	// MaxIter is the maximal number of iterations per step.
+	MaxIter int
+	// MaxEval is the maximal number of evaluations of the closure per step.
+	MaxEval int
+	// ToleranceGrad is the termination tolerance on the first order
+	// optimality, that is, on the largest absolute value of the gradients.
+	ToleranceGrad float64
+	// ToleranceChange is the termination tolerance on the changes of the
+	// loss and of the parameters.
+	ToleranceChange float64
+	// HistorySize is the number of updates used to approximate the inverse
+	// Hessian.
+	HistorySize int
+	// LineSearch is the line search algorithm.
+	LineSearch LineSearch
+}
+
+// NewConfig returns a new L-BFGS Config.
+// It panics if maxIter, maxEval or historySize are not positive.
+func NewConfig(
+	lr float64,
+	maxIter, maxEval int,
+	toleranceGrad, toleranceChange float64,
+	historySize int,
+	lineSearch LineSearch,
+) Config {
+	if maxIter <= 0 {
+		panic("lbfgs: `maxIter` must be > 0")
+	}
+	if maxEval <= 0 {
+		panic("lbfgs: `maxEval` must be > 0")
+	}
+	if historySize <= 0 {
+		panic("lbfgs: `historySize` must be > 0")
+	}
+	return Config{
+		LR:              lr,
+		MaxIter:         maxIter,
+		MaxEval:         maxEval,
+		ToleranceGrad:   toleranceGrad,
+		ToleranceChange: toleranceChange,
+		HistorySize:     historySize,
+		LineSearch:      lineSearch,
+	}
+}
+
+// NewDefaultConfig returns a new Config with generically reasonable default
+// values, using the strong Wolfe line search.
+func NewDefaultConfig() Config {
+	return Config{
+		LR:              1.0,
+		MaxIter:         20,
+		MaxEval:         25,
+		ToleranceGrad:   1.0e-7,
+		ToleranceChange: 1.0e-9,
+		HistorySize:     100,
+		LineSearch:      StrongWolfe,
+	}
+}
+
+// LBFGS is the limited-memory Broyden–Fletcher–Goldfarb–Shanno optimizer.
+// Each call to Step performs up to MaxIter iterations, keeping the history
+// of the updates across the steps.
+// References:
+//
+//	On the limited memory BFGS method for large scale optimization
+//	https://doi.org/10.1007/BF01589116
+//
+//	Numerical Optimization (Nocedal and Wright), Algorithms 3.5, 3.6 and 7.4
+type LBFGS struct {
+	Config
+	// params are the parameters to optimize.
+	params []*nn.Param
+	// active are the parameters optimized by the last step, which are the
+	// not frozen ones.
+	active []*nn.Param
+
+	direction []float64
+	stepLen   float64
+	dirs      [][]float64 // y, the differences of the gradients
+	steps     [][]float64 // s, the differences of the parameters
+	ro        []float64   // 1 / (y·s)
+	hDiag     float64     // the scaling of the initial inverse Hessian
+	prevGrad  []float64
+	prevLoss  float64
+
+	iterations int
+	evals      int
+}
+
+// New returns a new L-BFGS optimizer of the given parameters, initialized
+// according to the given configuration.
+func New(parameters nn.ParamChannelFunc, c Config) *LBFGS {
+	o := &LBFGS{Config: c}
+	for p := range parameters(context.Background()) {
+		o.params = append(o.params, p)
+	}
+	return o
+}
+
+// Iterations returns the total number of iterations performed.
+func (o *LBFGS) Iterations() int {
+	return o.iterations
+}
+
+// Evaluations returns the total number of evaluations of the closure.
+func (o *LBFGS) Evaluations() int {
+	return o.evals
+}
+
+// Reset discards the history of the updates, restarting from the steepest
+// descent direction at the next step.
+func (o *LBFGS) Reset() {
+	o.direction, o.prevGrad = nil, nil
+	o.dirs, o.steps, o.ro = nil, nil, nil
+}
+
+// Step performs an optimization step, made of up to MaxIter iterations,
+// each one evaluating the closure once or, with a line search, a few
+// times. It returns the loss evaluated before the step.
+// Frozen parameters (see nn.Freeze) are not optimized; if they change
+// between two steps, the history is reset.
+func (o *LBFGS) Step(closure Closure) (float64, error) {
+	o.updateActive()
+
+	origLoss, flatGrad, err := o.evaluate(closure)
+	if err != nil {
+		return 0, err
+	}
+	loss := origLoss
+	currentEvals := 1
+	o.evals++
+
+	if maxAbs(flatGrad) <= o.ToleranceGrad {
+		return origLoss, nil
+	}
+
+	for n := 1; n <= o.MaxIter; n++ {
+		o.iterations++
+
+		if o.direction == nil {
+			// first iteration since the start or a reset: steepest descent
+			o.direction = scale(flatGrad, -1)
+			o.dirs, o.steps, o.ro = nil, nil, nil
+			o.hDiag = 1
+			o.stepLen = math.Min(1, 1/sumAbs(flatGrad)) * o.LR
+		} else {
+			o.updateHistory(flatGrad)
+			o.direction = o.twoLoop(flatGrad)
+			o.stepLen = o.LR
+		}
+		o.prevGrad = clone(flatGrad)
+		o.prevLoss = loss
+
+		gtd := dot(flatGrad, o.direction)
+		if gtd > -o.ToleranceChange {
+			break
+		}
+
+		lsEvals := 0
+		if o.LineSearch == StrongWolfe {
+			x := o.paramsData()
+			eval := func(t float64) (float64, []float64, error) {
+				return o.directionalEvaluate(closure, x, t, o.direction)
+			}
+			loss, flatGrad, o.stepLen, lsEvals, err = strongWolfe(eval, o.stepLen, o.direction, loss, flatGrad, gtd, o.ToleranceChange)
+			if err != nil {
+				return 0, err
+			}
+			o.addToParams(o.stepLen, o.direction)
+		} else {
+			o.addToParams(o.stepLen, o.direction)
+			if n != o.MaxIter {
+				loss, flatGrad, err = o.evaluate(closure)
+				if err != nil {
+					return 0, err
+				}
+				lsEvals = 1
+			}
+		}
+		currentEvals += lsEvals
+		o.evals += lsEvals
+
+		if n == o.MaxIter || currentEvals >= o.MaxEval {
+			break
+		}
+		if maxAbs(flatGrad) <= o.ToleranceGrad {
+			break
+		}
+		if maxAbs(o.direction)*math.Abs(o.stepLen) <= o.ToleranceChange {
+			break
+		}
+		if math.Abs(loss-o.prevLoss) < o.ToleranceChange {
+			break
+		}
+	}
+	return origLoss, nil
+}
+
+// updateActive collects the parameters which are not frozen, resetting the
+// history if they changed since the last step.
+func (o *LBFGS) updateActive() {
+	var active []*nn.Param
+	for _, p := range o.params {
+		if !nn.IsFrozen(p) {
+			active = append(active, p)
+		}
+	}
+	changed := len(active) != len(o.active)
+	for i := 0; !changed && i < len(active); i++ {
+		changed = active[i] != o.active[i]
+	}
+	if changed {
+		o.Reset()
+	}
+	o.active = active
+}
+
+// updateHistory adds the last update to the history, if it satisfies the
+// curvature condition, discarding the oldest one when the history is full.
+func (o *LBFGS) updateHistory(flatGrad []float64) {
+	y := sub(flatGrad, o.prevGrad)
+	s := scale(o.direction, o.stepLen)
+	ys := dot(y, s)
+	if ys <= 1e-10 {
+		return
+	}
+	if len(o.dirs) == o.HistorySize {
+		o.dirs, o.steps, o.ro = o.dirs[1:], o.steps[1:], o.ro[1:]
+	}
+	o.dirs = append(o.dirs, y)
+	o.steps = append(o.steps, s)
+	o.ro = append(o.ro, 1/ys)
+	o.hDiag = ys / dot(y, y)
+}
+
+// twoLoop computes the descent direction -H·g with the two-loop recursion,
+// where H is the approximation of the inverse Hessian.
+func (o *LBFGS) twoLoop(flatGrad []float64) []float64 {
+	al := make([]float64, len(o.dirs))
+	q := scale(flatGrad, -1)
+	for i := len(o.dirs) - 1; i >= 0; i-- {
+		al[i] = dot(o.steps[i], q) * o.ro[i]
+		axpy(-al[i], o.dirs[i], q)
+	}
+	r := scale(q, o.hDiag)
+	for i := range o.dirs {
+		be := dot(o.dirs[i], r) * o.ro[i]
+		axpy(al[i]-be, o.steps[i], r)
+	}
+	return r
+}
+
+// evaluate zeroes the gradients of the parameters, calls the closure and
+// returns the loss and the flattened gradients.
+func (o *LBFGS) evaluate(closure Closure) (float64, []float64, error) {
+	for _, p := range o.active {
+		p.ZeroGrad()
+	}
+	loss, err := closure()
+	if err != nil {
+		return 0, nil, err
+	}
+	if loss == nil {
+		return 0, nil, fmt.Errorf("lbfgs: the closure returned a nil loss")
+	}
+	return loss.Item().F64(), o.gatherGrads(), nil
+}
+
+// directionalEvaluate evaluates the closure at x + t*d, then restores the
+// parameters to x.
+func (o *LBFGS) directionalEvaluate(closure Closure, x [][]float64, t float64, d []float64) (float64, []float64, error) {
+	o.addToParams(t, d)
+	defer o.setParamsData(x)
+	return o.evaluate(closure)
+}
+
+// gatherGrads returns the gradients of the parameters, flattened in a
+// single vector. Missing gradients count as zeros.
+func (o *LBFGS) gatherGrads() []float64 {
+	var flat []float64
+	for _, p := range o.active {
+		if p.HasGrad() {
+			flat = append(flat, p.Grad().Data().F64()...)
+		} else {
+			flat = append(flat, make([]float64, p.Size())...)
+		}
+	}
+	return flat
+}
+
+// addToParams adds t*d to the parameters, where d is a flattened vector.
+func (o *LBFGS) addToParams(t float64, d []float64) {
+	offset := 0
+	for _, p := range o.active {
+		data := p.Data().F64()
+		values := make([]float64, len(data))
+		for i, v := range data {
+			values[i] = v + t*d[offset+i]
+		}
+		p.SetData(float.Make(values...))
+		offset += len(data)
+	}
+}
+
+// paramsData returns a copy of the values of the parameters.
+func (o *LBFGS) paramsData() [][]float64 {
+	x := make([][]float64, len(o.active))
+	for i, p := range o.active {
+		x[i] = clone(p.Data().F64())
+	}
+	return x
+}
+
+// setParamsData sets the values of the parameters, copied with paramsData.
+func (o *LBFGS) setParamsData(x [][]float64) {
+	for i, p := range o.active {
+		p.SetData(float.Make(x[i]...))
+	}
+}
+
+func dot(a, b []float64) float64 {
+	var s float64
+	for i, v := range a {
+		s += v * b[i]
+	}
+	return s
+}
+
+// axpy computes y += a*x in place.
+func axpy(a float64, x, y []float64) {
+	for i, v := range x {
+		y[i] += a * v
+	}
+}
+
+func scale(x []float64, a float64) []float64 {
+	out := make([]float64, len(x))
+	for i, v := range x {
+		out[i] = a * v
+	}
+	return out
+}
+
+func sub(a, b []float64) []float64 {
+	out := make([]float64, len(a))
+	for i, v := range a {
+		out[i] = v - b[i]
+	}
+	return out
+}
+
+func clone(x []float64) []float64 {
+	return append([]float64(nil), x...)
+}
+
+func maxAbs(x []float64) float64 {
+	var m float64
+	for _, v := range x {
+		m = math.Max(m, math.Abs(v))
+	}
+	return m
+}
+
+func sumAbs(x []float64) float64 {
+	var s float64
+	for _, v := range x {
+		s += math.Abs(v)
+	}
+	return s
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lbfgs
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rosenbrock returns a closure computing the Rosenbrock function
+// f(x, y) = (1-x)^2 + 100(y-x^2)^2 of the parameter, whose minimum is at
+// (1, 1), and accumulating its gradients.
+func rosenbrock[T float.DType](p *nn.Param) Closure {
+	return func() (mat.Tensor, error) {
+		v := p.Data().F64()
+		x, y := v[0], v[1]
+		f := (1-x)*(1-x) + 100*(y-x*x)*(y-x*x)
+		dx := -2*(1-x) - 400*x*(y-x*x)
+		dy := 200 * (y - x*x)
+		p.AccGrad(mat.NewDense[T](mat.WithShape(1, 2), mat.WithBacking([]T{T(dx), T(dy)})))
+		return mat.Scalar(T(f)), nil
+	}
+}
+
+func TestLBFGS_Rosenbrock(t *testing.T) {
+	t.Run("float32", testRosenbrock[float32])
+	t.Run("float64", testRosenbrock[float64])
+}
+
+func testRosenbrock[T float.DType](t *testing.T) {
+	p := nn.NewParam(mat.NewDense[T](mat.WithShape(1, 2), mat.WithBacking([]T{-1.5, 2})))
+	o := New(nn.StreamParams([]*nn.Param{p}), NewDefaultConfig())
+
+	closure := rosenbrock[T](p)
+	first, err := o.Step(closure)
+	require.NoError(t, err)
+	assert.InDelta(t, 12.5, first, 1.0e-5)
+
+	for i := 0; i < 10; i++ {
+		_, err = o.Step(closure)
+		require.NoError(t, err)
+	}
+	assert.InDeltaSlice(t, []T{1, 1}, p.Data(), 1.0e-4)
+	assert.Greater(t, o.Iterations(), 0)
+	assert.GreaterOrEqual(t, o.Evaluations(), o.Iterations())
+}
+
+func TestLBFGS_NoLineSearch(t *testing.T) {
+	// f(x) = sum((x - c)^2 * w), a convex quadratic
+	c := []float64{1, -2, 3}
+	w := []float64{1, 4, 0.5}
+	p := nn.NewParam(mat.NewDense[float64](mat.WithShape(1, 3)))
+	closure := func() (mat.Tensor, error) {
+		v := p.Data().F64()
+		var f float64
+		g := make([]float64, len(v))
+		for i := range v {
+			f += (v[i] - c[i]) * (v[i] - c[i]) * w[i]
+			g[i] = 2 * (v[i] - c[i]) * w[i]
+		}
+		p.AccGrad(mat.NewDense[float64](mat.WithShape(1, 3), mat.WithBacking(g)))
+		return mat.Scalar(f), nil
+	}
+
+	config := NewConfig(1.0, 20, 25, 1.0e-9, 1.0e-12, 10, NoLineSearch)
+	o := New(nn.StreamParams([]*nn.Param{p}), config)
+	_, err := o.Step(closure)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, c, p.Data(), 1.0e-6)
+}
+
+func TestLBFGS_LogisticRegression(t *testing.T) {
+	xs := [][]float64{{0.5, 1.0}, {1.5, -0.5}, {-1.0, 0.3}, {2.0, 1.0}, {-0.5, -1.5}, {0.2, 0.8}}
+	ys := []bool{true, true, false, true, false, false}
+
+	w := nn.NewParam(mat.NewDense[float64](mat.WithShape(1, 2)))
+	b := nn.NewParam(mat.NewDense[float64](mat.WithShape(1, 1)))
+	one := mat.Scalar(1.0)
+
+	closure := func() (mat.Tensor, error) {
+		var loss mat.Tensor
+		for i, x := range xs {
+			z := ag.Add(ag.Mul(w, mat.NewDense[float64](mat.WithShape(2, 1), mat.WithBacking(x))), b)
+			p := ag.Sigmoid(z)
+			var l mat.Tensor
+			if ys[i] {
+				l = ag.Log(p)
+			} else {
+				l = ag.Log(ag.Sub(one, p))
+			}
+			if loss == nil {
+				loss = l
+			} else {
+				loss = ag.Add(loss, l)
+			}
+		}
+		loss = ag.Neg(loss)
+		return loss, ag.Backward(loss)
+	}
+
+	o := New(nn.StreamParams([]*nn.Param{w, b}), NewDefaultConfig())
+	initial, err := o.Step(closure)
+	require.NoError(t, err)
+	assert.InDelta(t, 6*math.Ln2, initial, 1.0e-9)
+
+	for i := 0; i < 5; i++ {
+		_, err = o.Step(closure)
+		require.NoError(t, err)
+	}
+	final, err := o.Step(closure)
+	require.NoError(t, err)
+	assert.Less(t, final, initial)
+	assert.InDeltaSlice(t, []float64{0, 0}, w.Grad().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []float64{0}, b.Grad().Data(), 1.0e-6)
+}
+
+type frozenModel struct {
+	nn.Module
+	P *nn.Param
+}
+
+func TestLBFGS_FrozenParams(t *testing.T) {
+	p := nn.NewParam(mat.NewDense[float64](mat.WithShape(1, 2), mat.WithBacking([]float64{-1.5, 2})))
+	frozen := nn.NewParam(mat.NewDense[float64](mat.WithShape(1, 2), mat.WithBacking([]float64{3, 4})))
+	nn.Freeze(&frozenModel{P: frozen})
+	closure := rosenbrock[float64](p)
+
+	o := New(nn.StreamParams([]*nn.Param{frozen, p}), NewDefaultConfig())
+	_, err := o.Step(closure)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{3, 4}, frozen.Data().F64())
+	assert.NotEqual(t, []float64{-1.5, 2}, p.Data().F64())
+}
+
+func TestLBFGS_ClosureError(t *testing.T) {
+	p := nn.NewParam(mat.NewDense[float64](mat.WithShape(1, 2), mat.WithBacking([]float64{-1.5, 2})))
+	expected := errors.New("test error")
+	calls := 0
+	closure := func() (mat.Tensor, error) {
+		calls++
+		if calls == 3 {
+			return nil, expected
+		}
+		return rosenbrock[float64](p)()
+	}
+
+	o := New(nn.StreamParams([]*nn.Param{p}), NewDefaultConfig())
+	_, err := o.Step(closure)
+	assert.ErrorIs(t, err, expected)
+}
+
+func TestCubicInterpolate(t *testing.T) {
+	// f(x) = (x-1)^3 - 3(x-1) has a local minimum at x = 2
+	f := func(x float64) float64 { return (x-1)*(x-1)*(x-1) - 3*(x-1) }
+	df := func(x float64) float64 { return 3*(x-1)*(x-1) - 3 }
+	assert.InDelta(t, 2.0, cubicInterpolate(1.5, f(1.5), df(1.5), 3, f(3), df(3), 1.5, 3), 1.0e-12)
+	// the minimizer is clamped into the bounds
+	assert.InDelta(t, 2.5, cubicInterpolate(1.5, f(1.5), df(1.5), 3, f(3), df(3), 2.5, 3), 1.0e-12)
+}
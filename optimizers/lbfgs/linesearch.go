@@ -0,0 +1,185 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lbfgs
+
+import "math"
+
+const (
+	// wolfeC1 is the constant of the sufficient decrease condition.
+	wolfeC1 = 1.0e-4
+	// wolfeC2 is the constant of the curvature condition.
+	wolfeC2 = 0.9
+	// maxLineSearch is the maximal number of evaluations of a line search.
+	maxLineSearch = 25
+)
+
+// objective evaluates the loss and the gradients at step length t along the
+// search direction.
+type objective func(t float64) (float64, []float64, error)
+
+// strongWolfe searches a step length t along the direction d satisfying the
+// strong Wolfe conditions, starting from the loss f, the gradients g and
+// the directional derivative gtd at t = 0. It returns the loss and the
+// gradients at the chosen step length, the step length itself, and the
+// number of evaluations.
+//
+// It first brackets an interval containing acceptable step lengths, then
+// zooms into it with safeguarded cubic interpolation (Nocedal and Wright,
+// Algorithms 3.5 and 3.6).
+func strongWolfe(
+	eval objective,
+	t float64,
+	d []float64,
+	f float64,
+	g []float64,
+	gtd float64,
+	toleranceChange float64,
+) (float64, []float64, float64, int, error) {
+	dNorm := maxAbs(d)
+	fNew, gNew, err := eval(t)
+	if err != nil {
+		return 0, nil, 0, 0, err
+	}
+	evals := 1
+	gtdNew := dot(gNew, d)
+
+	tPrev, fPrev, gPrev, gtdPrev := 0.0, f, g, gtd
+	done := false
+	iter := 0
+
+	var (
+		bracket    []float64
+		bracketF   []float64
+		bracketG   [][]float64
+		bracketGtd []float64
+	)
+
+	for iter < maxLineSearch {
+		if fNew > f+wolfeC1*t*gtd || (iter > 1 && fNew >= fPrev) {
+			bracket = []float64{tPrev, t}
+			bracketF = []float64{fPrev, fNew}
+			bracketG = [][]float64{gPrev, gNew}
+			bracketGtd = []float64{gtdPrev, gtdNew}
+			break
+		}
+		if math.Abs(gtdNew) <= -wolfeC2*gtd {
+			bracket = []float64{t}
+			bracketF = []float64{fNew}
+			bracketG = [][]float64{gNew}
+			done = true
+			break
+		}
+		if gtdNew >= 0 {
+			bracket = []float64{tPrev, t}
+			bracketF = []float64{fPrev, fNew}
+			bracketG = [][]float64{gPrev, gNew}
+			bracketGtd = []float64{gtdPrev, gtdNew}
+			break
+		}
+
+		// extrapolate
+		minStep := t + 0.01*(t-tPrev)
+		maxStep := t * 10
+		tmp := t
+		t = cubicInterpolate(tPrev, fPrev, gtdPrev, t, fNew, gtdNew, minStep, maxStep)
+
+		tPrev, fPrev, gPrev, gtdPrev = tmp, fNew, gNew, gtdNew
+		fNew, gNew, err = eval(t)
+		if err != nil {
+			return 0, nil, 0, 0, err
+		}
+		evals++
+		gtdNew = dot(gNew, d)
+		iter++
+	}
+
+	if iter == maxLineSearch {
+		bracket = []float64{0, t}
+		bracketF = []float64{f, fNew}
+		bracketG = [][]float64{g, gNew}
+		bracketGtd = []float64{gtd, gtdNew}
+	}
+
+	// zoom
+	insufficientProgress := false
+	low, high := 0, len(bracket)-1
+	if bracketF[0] > bracketF[high] {
+		low, high = 1, 0
+	}
+	for !done && iter < maxLineSearch {
+		if math.Abs(bracket[1]-bracket[0])*dNorm < toleranceChange {
+			break
+		}
+
+		t = cubicInterpolate(bracket[0], bracketF[0], bracketGtd[0], bracket[1], bracketF[1], bracketGtd[1],
+			math.Min(bracket[0], bracket[1]), math.Max(bracket[0], bracket[1]))
+
+		// Guard against steps too close to the boundaries of the bracket,
+		// moving t away from them after an insufficient progress.
+		lo, hi := math.Min(bracket[0], bracket[1]), math.Max(bracket[0], bracket[1])
+		eps := 0.1 * (hi - lo)
+		if math.Min(hi-t, t-lo) < eps {
+			if insufficientProgress || t >= hi || t <= lo {
+				if math.Abs(t-hi) < math.Abs(t-lo) {
+					t = hi - eps
+				} else {
+					t = lo + eps
+				}
+				insufficientProgress = false
+			} else {
+				insufficientProgress = true
+			}
+		} else {
+			insufficientProgress = false
+		}
+
+		fNew, gNew, err = eval(t)
+		if err != nil {
+			return 0, nil, 0, 0, err
+		}
+		evals++
+		gtdNew = dot(gNew, d)
+		iter++
+
+		if fNew > f+wolfeC1*t*gtd || fNew >= bracketF[low] {
+			// the sufficient decrease condition is not satisfied
+			bracket[high], bracketF[high], bracketG[high], bracketGtd[high] = t, fNew, gNew, gtdNew
+			low, high = 0, 1
+			if bracketF[0] > bracketF[1] {
+				low, high = 1, 0
+			}
+		} else {
+			if math.Abs(gtdNew) <= -wolfeC2*gtd {
+				done = true
+			} else if gtdNew*(bracket[high]-bracket[low]) >= 0 {
+				bracket[high], bracketF[high], bracketG[high], bracketGtd[high] =
+					bracket[low], bracketF[low], bracketG[low], bracketGtd[low]
+			}
+			bracket[low], bracketF[low], bracketG[low], bracketGtd[low] = t, fNew, gNew, gtdNew
+		}
+	}
+
+	return bracketF[low], bracketG[low], bracket[low], evals, nil
+}
+
+// cubicInterpolate returns the minimizer, within the bounds [minBound,
+// maxBound], of the cubic interpolating the values f1 and f2, and the
+// derivatives g1 and g2, at x1 and x2. If the cubic has no minimizer, it
+// returns the middle of the bounds.
+func cubicInterpolate(x1, f1, g1, x2, f2, g2, minBound, maxBound float64) float64 {
+	d1 := g1 + g2 - 3*(f1-f2)/(x1-x2)
+	d2Square := d1*d1 - g1*g2
+	if d2Square < 0 {
+		return (minBound + maxBound) / 2
+	}
+	d2 := math.Sqrt(d2Square)
+	var minPos float64
+	if x1 <= x2 {
+		minPos = x2 - (x2-x1)*((g2+d2-d1)/(g2-g1+2*d2))
+	} else {
+		minPos = x1 - (x1-x2)*((g1+d2-d1)/(g1-g2+2*d2))
+	}
+	return math.Min(math.Max(minPos, minBound), maxBound)
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lion
+
+import (
+	"encoding/gob"
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+// Config provides configuration settings for a Lion optimizer.
+type Config struct {
+	LR          float64
+	Beta1       float64
+	Beta2       float64
+	WeightDecay float64 // decoupled weight decay, as in AdamW
+}
+
+// NewConfig returns a new Lion Config.
+// It panics if beta1 or beta2 are not in the range [0.0, 1.0).
+func NewConfig(lr, beta1, beta2, weightDecay float64) Config {
+	if !(beta1 >= 0.0 && beta1 < 1.0) {
+		panic("lion: `beta1` must be in the range [0.0, 1.0)")
+	}
+	if !(beta2 >= 0.0 && beta2 < 1.0) {
+		panic("lion: `beta2` must be in the range [0.0, 1.0)")
+	}
+	return Config{
+		LR:          lr,
+		Beta1:       beta1,
+		Beta2:       beta2,
+		WeightDecay: weightDecay,
+	}
+}
+
+// NewDefaultConfig returns a new Config with generically reasonable default values.
+// The learning rate of Lion is typically 3-10x smaller than the one of AdamW.
+func NewDefaultConfig() Config {
+	return Config{
+		LR:    1.0e-4,
+		Beta1: 0.9,
+		Beta2: 0.99,
+	}
+}
+
+// Lion (EvoLved Sign Momentum) updates the parameters with the sign of an
+// interpolation of the gradients and of their momentum, keeping a single
+// moment per parameter.
+// References:
+//
+//	Symbolic Discovery of Optimization Algorithms
+//	https://arxiv.org/abs/2302.06675
+type Lion[T float.DType] struct {
+	Config
+}
+
+// New returns a new Lion optimizer, initialized according to the given configuration.
+func New[T float.DType](c Config) *Lion[T] {
+	return &Lion[T]{Config: c}
+}
+
+// LearningRate returns the learning rate.
+func (o *Lion[_]) LearningRate() float64 {
+	return o.LR
+}
+
+// SetLearningRate sets the learning rate.
+func (o *Lion[_]) SetLearningRate(lr float64) {
+	o.LR = lr
+}
+
+type State struct {
+	M mat.Matrix // momentum
+}
+
+func init() {
+	gob.Register(&State{})
+}
+
+func (o *Lion[T]) newState(shape ...int) *State {
+	return &State{
+		M: mat.NewDense[T](mat.WithShape(shape...)),
+	}
+}
+
+// c = m*beta1 + grads*(1.0-beta1)
+// d = (sign(c) + lambda*weights) * lr
+// m = m*beta2 + grads*(1.0-beta2)
+func (o *Lion[T]) calculateParamUpdate(grads mat.Matrix, state *State, weights mat.Matrix) mat.Matrix {
+	c := state.M.ProdScalar(o.Beta1)
+	c.AddInPlace(grads.ProdScalar(1.0 - o.Beta1))
+	delta := c.Apply(func(_, _ int, v float64) float64 {
+		switch {
+		case v > 0:
+			return 1
+		case v < 0:
+			return -1
+		default:
+			return 0
+		}
+	})
+	if o.WeightDecay != 0 {
+		delta.AddInPlace(weights.ProdScalar(o.WeightDecay))
+	}
+	delta.ProdScalarInPlace(o.LR)
+
+	state.M.ProdScalarInPlace(o.Beta2)
+	state.M.AddInPlace(grads.ProdScalar(1.0 - o.Beta2))
+	return delta
+}
+
+func (o *Lion[T]) OptimizeParams(param *nn.Param) error {
+	if param.State == nil {
+		param.State = o.newState(param.Value().Shape()...)
+	}
+
+	state, ok := param.State.(*State)
+	if !ok {
+		return fmt.Errorf("unsupported state type: %T, expected %T", param.State, &State{})
+	}
+
+	param.SubInPlace(o.calculateParamUpdate(param.Grad().(mat.Matrix), state, param.Value().(mat.Matrix)))
+	param.ZeroGrad()
+
+	return nil
+}
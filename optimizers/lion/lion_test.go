@@ -0,0 +1,104 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lion
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+// The expected values follow lion_pytorch.py (google/automl), the
+// implementation released with the paper, evaluated in float64.
+func Test_Update(t *testing.T) {
+	t.Run("float32", testUpdate[float32])
+	t.Run("float64", testUpdate[float64])
+}
+
+func testUpdate[T float.DType](t *testing.T) {
+	updater := New[T](NewConfig(
+		0.001, // learning rate
+		0.9,   // beta1
+		0.99,  // beta2
+		0.1,   // weight decay
+	))
+
+	params := mat.NewDense[T](mat.WithBacking([]T{0.4, 0.4, 0.5, 1.0, 0.8}))
+	grads := mat.NewDense[T](mat.WithBacking([]T{0.9, 0.7, 0.4, 0.8, 0.1}))
+
+	supp := updater.newState(params.Shape()...)
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp, params))
+
+	assert.InDeltaSlice(t, []T{0.39896, 0.39896, 0.49895, 0.9989, 0.79892}, params.Data(), 1.0e-6)
+
+}
+
+func Test_Update2(t *testing.T) {
+	t.Run("float32", testUpdate2[float32])
+	t.Run("float64", testUpdate2[float64])
+}
+
+func testUpdate2[T float.DType](t *testing.T) {
+	updater := New[T](NewConfig(
+		0.001, // learning rate
+		0.9,   // beta1
+		0.99,  // beta2
+		0.1,   // weight decay
+	))
+
+	params := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		1.4, 1.3, 0,
+		-0.8, 0.16, 0.65,
+		0.7, -0.4, 0.2,
+	}))
+
+	grads := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.5, 0.3, -0.1,
+		-0.6, -0.4, -1.0,
+		0.5, -0.6, 0.1,
+	}))
+
+	supp := updater.newState(params.Shape()...)
+
+	// === First iteration
+
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp, params))
+
+	assert.InDeltaSlice(t, []T{
+		0.005, 0.003, -0.001,
+		-0.006, -0.004, -0.01,
+		0.005, -0.006, 0.001,
+	}, supp.M.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		1.39886, 1.29887, 0.001,
+		-0.79892, 0.160984, 0.650935,
+		0.69893, -0.39896, 0.19898,
+	}, params.Data(), 1.0e-6)
+
+	// === Second iteration
+
+	grads2 := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.7, 0.44, -0.66,
+		-0.56, 0.4, 1.4,
+		0.44, 1.44, 2.44,
+	}))
+
+	params.SubInPlace(updater.calculateParamUpdate(grads2, supp, params))
+
+	assert.InDeltaSlice(t, []T{
+		0.01195, 0.00737, -0.00759,
+		-0.01154, 4e-05, 0.0041,
+		0.00935, 0.00846, 0.02539,
+	}, supp.M.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		1.397720114, 1.297740113, 0.0019999,
+		-0.797840108, 0.1599679016, 0.6498699065,
+		0.697860107, -0.399920104, 0.197960102,
+	}, params.Data(), 1.0e-6)
+}
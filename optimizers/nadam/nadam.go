@@ -0,0 +1,140 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nadam
+
+import (
+	"encoding/gob"
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+// Config provides configuration settings for a NAdam optimizer.
+type Config struct {
+	StepSize      float64
+	Beta1         float64
+	Beta2         float64
+	Epsilon       float64
+	MomentumDecay float64 // psi, the decay of the momentum schedule
+}
+
+// NewConfig returns a new NAdam Config.
+// It panics if beta1 or beta2 are not in the range [0.0, 1.0).
+func NewConfig(stepSize, beta1, beta2, epsilon, momentumDecay float64) Config {
+	if !(beta1 >= 0.0 && beta1 < 1.0) {
+		panic("nadam: `beta1` must be in the range [0.0, 1.0)")
+	}
+	if !(beta2 >= 0.0 && beta2 < 1.0) {
+		panic("nadam: `beta2` must be in the range [0.0, 1.0)")
+	}
+	return Config{
+		StepSize:      stepSize,
+		Beta1:         beta1,
+		Beta2:         beta2,
+		Epsilon:       epsilon,
+		MomentumDecay: momentumDecay,
+	}
+}
+
+// NewDefaultConfig returns a new Config with generically reasonable default values.
+func NewDefaultConfig() Config {
+	return Config{
+		StepSize:      0.002,
+		Beta1:         0.9,
+		Beta2:         0.999,
+		Epsilon:       1.0e-8,
+		MomentumDecay: 0.004,
+	}
+}
+
+// NAdam is a variant of Adam incorporating Nesterov momentum, with the
+// momentum schedule mu_t = beta1 * (1 - 0.5 * 0.96^(t*psi)).
+// References:
+//
+//	Incorporating Nesterov Momentum into Adam
+//	https://openreview.net/forum?id=OM0jvwB8jIp57ZJjtNEZ
+type NAdam[T float.DType] struct {
+	Config
+}
+
+// New returns a new NAdam optimizer, initialized according to the given configuration.
+func New[T float.DType](c Config) *NAdam[T] {
+	return &NAdam[T]{Config: c}
+}
+
+// LearningRate returns the step size.
+func (o *NAdam[_]) LearningRate() float64 {
+	return o.StepSize
+}
+
+// SetLearningRate sets the step size.
+func (o *NAdam[_]) SetLearningRate(lr float64) {
+	o.StepSize = lr
+}
+
+type State struct {
+	M         mat.Matrix // first moment vector
+	V         mat.Matrix // second raw moment vector
+	TimeStep  int        // number of updates of the parameter
+	MuProduct float64    // product of the momentum coefficients up to TimeStep
+}
+
+func init() {
+	gob.Register(&State{})
+}
+
+func (o *NAdam[T]) newState(shape ...int) *State {
+	return &State{
+		M:         mat.NewDense[T](mat.WithShape(shape...)),
+		V:         mat.NewDense[T](mat.WithShape(shape...)),
+		MuProduct: 1,
+	}
+}
+
+// mu returns the momentum coefficient at time step t.
+func (o *NAdam[T]) mu(t int) float64 {
+	return o.Beta1 * (1.0 - 0.5*math.Pow(0.96, float64(t)*o.MomentumDecay))
+}
+
+// m = m*beta1 + grads*(1.0-beta1)
+// v = v*beta2 + (grads*grads)*(1.0-beta2)
+// d = (grads*(1-mu_t)/(1-prod(mu_1..t)) + m*mu_t+1/(1-prod(mu_1..t+1))) / (sqrt(v/(1-beta2^t)) + eps) * lr
+func (o *NAdam[T]) calculateParamUpdate(grads mat.Matrix, state *State) mat.Matrix {
+	state.TimeStep++
+	mu := o.mu(state.TimeStep)
+	muNext := o.mu(state.TimeStep + 1)
+	state.MuProduct *= mu
+
+	state.M.ProdScalarInPlace(o.Beta1)
+	state.M.AddInPlace(grads.ProdScalar(1.0 - o.Beta1))
+	state.V.ProdScalarInPlace(o.Beta2)
+	state.V.AddInPlace(grads.Prod(grads).ProdScalarInPlace(1.0 - o.Beta2))
+
+	biasCorrection2 := 1.0 - math.Pow(o.Beta2, float64(state.TimeStep))
+	denom := state.V.ProdScalar(1.0 / biasCorrection2).Sqrt().AddScalarInPlace(o.Epsilon)
+
+	delta := grads.ProdScalar((1.0 - mu) / (1.0 - state.MuProduct))
+	delta.AddInPlace(state.M.ProdScalar(muNext / (1.0 - state.MuProduct*muNext)))
+	return delta.DivInPlace(denom).ProdScalarInPlace(o.StepSize)
+}
+
+func (o *NAdam[T]) OptimizeParams(param *nn.Param) error {
+	if param.State == nil {
+		param.State = o.newState(param.Value().Shape()...)
+	}
+
+	state, ok := param.State.(*State)
+	if !ok {
+		return fmt.Errorf("unsupported state type: %T, expected %T", param.State, &State{})
+	}
+
+	param.SubInPlace(o.calculateParamUpdate(param.Grad().(mat.Matrix), state))
+	param.ZeroGrad()
+
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nadam
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+// The expected values follow the single-tensor update of torch.optim.NAdam
+// (PyTorch), evaluated in float64.
+func Test_Update(t *testing.T) {
+	t.Run("float32", testUpdate[float32])
+	t.Run("float64", testUpdate[float64])
+}
+
+func testUpdate[T float.DType](t *testing.T) {
+	updater := New[T](NewConfig(
+		0.002,  // step size
+		0.9,    // beta1
+		0.999,  // beta2
+		1.0e-8, // epsilon
+		0.004,  // momentum decay
+	))
+
+	params := mat.NewDense[T](mat.WithBacking([]T{0.4, 0.4, 0.5, 1.0, 0.8}))
+	grads := mat.NewDense[T](mat.WithBacking([]T{0.9, 0.7, 0.4, 0.8, 0.1}))
+
+	supp := updater.newState(params.Shape()...)
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp))
+
+	assert.InDeltaSlice(t, []T{0.397887096467, 0.397887096473, 0.497887096496, 0.99788709647, 0.797887096655}, params.Data(), 1.0e-6)
+	assert.InDelta(t, 0.450073473591, supp.MuProduct, 1.0e-9)
+}
+
+func Test_Update2(t *testing.T) {
+	t.Run("float32", testUpdate2[float32])
+	t.Run("float64", testUpdate2[float64])
+}
+
+func testUpdate2[T float.DType](t *testing.T) {
+	updater := New[T](NewConfig(
+		0.002,  // step size
+		0.9,    // beta1
+		0.999,  // beta2
+		1.0e-8, // epsilon
+		0.004,  // momentum decay
+	))
+
+	params := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		1.4, 1.3, 0,
+		-0.8, 0.16, 0.65,
+		0.7, -0.4, 0.2,
+	}))
+
+	grads := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.5, 0.3, -0.1,
+		-0.6, -0.4, -1.0,
+		0.5, -0.6, 0.1,
+	}))
+
+	supp := updater.newState(params.Shape()...)
+
+	// === First iteration
+
+	params.SubInPlace(updater.calculateParamUpdate(grads, supp))
+
+	assert.InDeltaSlice(t, []T{
+		0.05, 0.03, -0.01,
+		-0.06, -0.04, -0.1,
+		0.05, -0.06, 0.01,
+	}, supp.M.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		0.00025, 9e-05, 1e-05,
+		0.00036, 0.00016, 0.001,
+		0.00025, 0.00036, 1e-05,
+	}, supp.V.Data(), 1.0e-8)
+
+	assert.InDeltaSlice(t, []T{
+		1.39788709649, 1.29788709651, 0.00211290334542,
+		-0.797887096479, 0.162112903504, 0.652112903536,
+		0.697887096486, -0.397887096479, 0.197887096655,
+	}, params.Data(), 1.0e-6)
+
+	// === Second iteration
+
+	grads2 := mat.NewDense[T](mat.WithShape(3, 3), mat.WithBacking([]T{
+		0.7, 0.44, -0.66,
+		-0.56, 0.4, 1.4,
+		0.44, 1.44, 2.44,
+	}))
+
+	params.SubInPlace(updater.calculateParamUpdate(grads2, supp))
+
+	assert.InDeltaSlice(t, []T{
+		0.115, 0.071, -0.075,
+		-0.11, 0.004, 0.05,
+		0.089, 0.09, 0.253,
+	}, supp.M.Data(), 1.0e-6)
+
+	assert.InDeltaSlice(t, []T{
+		0.00073975, 0.00028351, 0.00044559,
+		0.00067324, 0.00031984, 0.002959,
+		0.00044335, 0.00243324, 0.00596359,
+	}, supp.V.Data(), 1.0e-8)
+
+	assert.InDeltaSlice(t, []T{
+		1.39611284372, 1.29608900345, 0.00419819077808,
+		-0.796368501489, 0.160723881971, 0.650485239319,
+		0.696411344956, -0.399767938384, 0.195793724146,
+	}, params.Data(), 1.0e-6)
+	assert.Equal(t, 2, supp.TimeStep)
+	assert.InDelta(t, 0.202599194746, supp.MuProduct, 1.0e-9)
+}
@@ -6,10 +6,12 @@ package optimizers
 
 import (
 	"context"
+	"fmt"
 	"runtime"
 	"sync"
 
 	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/optimizers/schedule"
 )
 
 // OptimizationStrategy is the interface implemented by AdaGrad, Adam, etc.
@@ -17,12 +19,58 @@ type OptimizationStrategy interface {
 	OptimizeParams(*nn.Param) error
 }
 
+// LearningRateStrategy is implemented by the optimization strategies whose
+// learning rate can be driven by a schedule.
+type LearningRateStrategy interface {
+	OptimizationStrategy
+	// LearningRate returns the current learning rate.
+	LearningRate() float64
+	// SetLearningRate sets the learning rate.
+	SetLearningRate(lr float64)
+}
+
+// StatefulStrategy is implemented by the optimization strategies keeping a
+// state besides the one of each parameter (nn.Param.State), such as the
+// statistics shared by the embeddings factored by Adafactor. The state is
+// saved and restored together with the one of the optimizer (see
+// Optimizer.State).
+type StatefulStrategy interface {
+	OptimizationStrategy
+	// MarshalState returns an encoding of the current state.
+	MarshalState() ([]byte, error)
+	// UnmarshalState restores a state encoded by MarshalState. On errors,
+	// the strategy is left unchanged.
+	UnmarshalState(data []byte) error
+}
+
+// Interval is the unit of time of a learning rate schedule.
+type Interval int
+
+const (
+	// PerStep schedules are advanced by each call to Optimize.
+	PerStep Interval = iota
+	// PerEpoch schedules are advanced by each call to EndEpoch.
+	PerEpoch
+)
+
 // Optimizer is an optimizer that can optimize a set of parameters.
 type Optimizer struct {
 	// parameters is a function that returns a channel of parameters to optimize.
 	parameters nn.ParamChannelFunc
 	// strategy is the optimization strategy to use.
 	strategy OptimizationStrategy
+	// groups are the optional parameter groups, with their own strategies.
+	groups []ParamGroup
+	// groupOf maps the parameters of the groups to the group index.
+	groupOf map[*nn.Param]int
+	// schedule is the optional learning rate schedule.
+	schedule schedule.Schedule
+	// interval is the unit of time of the schedule.
+	interval Interval
+	// steps is the number of successful calls to Optimize.
+	steps int
+	// epochs is the number of calls to EndEpoch.
+	epochs int
 }
 
 // New returns a new optimizer.
@@ -33,8 +81,60 @@ func New(parameters nn.ParamChannelFunc, strategy OptimizationStrategy) *Optimiz
 	}
 }
 
-// Optimize performs the optimization of the parameters.
+// WithSchedule sets the schedule driving the learning rate of the default
+// strategy, advanced at each step or epoch according to the interval, which
+// also applies to the schedules of the parameter groups. Before each
+// optimization, the learning rate is set to the value of the schedule at
+// the current step or epoch, counted from 0. A nil schedule only sets the
+// interval.
+// It panics if the strategy does not implement LearningRateStrategy.
+func (o *Optimizer) WithSchedule(s schedule.Schedule, interval Interval) *Optimizer {
+	if _, ok := o.strategy.(LearningRateStrategy); !ok && s != nil {
+		panic(fmt.Sprintf("optimizers: strategy %T does not support learning rate schedules", o.strategy))
+	}
+	o.schedule = s
+	o.interval = interval
+	return o
+}
+
+// Steps returns the number of successful optimization steps.
+func (o *Optimizer) Steps() int {
+	return o.steps
+}
+
+// Epochs returns the number of completed epochs.
+func (o *Optimizer) Epochs() int {
+	return o.epochs
+}
+
+// EndEpoch marks the end of an epoch, advancing the PerEpoch schedules.
+func (o *Optimizer) EndEpoch() {
+	o.epochs++
+}
+
+// applySchedule sets the learning rate of the strategies to the value of
+// their schedules at the current time.
+func (o *Optimizer) applySchedule() {
+	t := o.steps
+	if o.interval == PerEpoch {
+		t = o.epochs
+	}
+	if o.schedule != nil {
+		o.strategy.(LearningRateStrategy).SetLearningRate(o.schedule.LR(t))
+	}
+	for _, g := range o.groups {
+		if g.Schedule != nil {
+			g.Strategy.(LearningRateStrategy).SetLearningRate(g.Schedule.LR(t))
+		}
+	}
+}
+
+// Optimize performs the optimization of the parameters, each one with the
+// strategy of its group, if any, or with the default strategy.
+// Parameters without gradients, or frozen (see nn.Freeze), are skipped.
 func (o *Optimizer) Optimize() error {
+	o.applySchedule()
+
 	var wg sync.WaitGroup
 	guard := make(chan struct{}, runtime.NumCPU()*2)
 	errCh := make(chan error, 1)
@@ -55,10 +155,11 @@ func (o *Optimizer) Optimize() error {
 			go func() {
 				defer wg.Done()
 				defer func() { <-guard }()
-				if !param.HasGrad() {
+				strategy := o.strategyFor(param)
+				if strategy == nil || !param.HasGrad() || nn.IsFrozen(param) {
 					return
 				}
-				if err := o.strategy.OptimizeParams(param); err != nil {
+				if err := strategy.OptimizeParams(param); err != nil {
 					select {
 					case errCh <- err:
 					default:
@@ -68,11 +169,13 @@ func (o *Optimizer) Optimize() error {
 		}
 	}
 
+	wg.Wait() // Wait for all the parameters to be optimized
 	close(errCh)
 
 	if err, ok := <-errCh; ok {
 		return err
 	}
 
+	o.steps++
 	return nil
 }
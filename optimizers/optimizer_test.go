@@ -0,0 +1,129 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimizers
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/optimizers/schedule"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingStrategy struct {
+	mu     sync.Mutex
+	params []*nn.Param
+}
+
+func (s *recordingStrategy) OptimizeParams(p *nn.Param) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.params = append(s.params, p)
+	return nil
+}
+
+type frozenModel struct {
+	nn.Module
+	P *nn.Param
+}
+
+func TestOptimizer_SkipsFrozenParams(t *testing.T) {
+	newParam := func() *nn.Param {
+		p := nn.NewParam(mat.NewDense[float32](mat.WithBacking([]float32{1, 2})))
+		p.AccGrad(mat.NewDense[float32](mat.WithBacking([]float32{1, 1})))
+		return p
+	}
+	trainable, frozen, withoutGrad := newParam(), newParam(), newParam().WithGrad(false)
+	nn.Freeze(&frozenModel{P: frozen})
+	frozen.AccGrad(mat.NewDense[float32](mat.WithBacking([]float32{1, 1})))
+	noGrad := nn.NewParam(mat.NewDense[float32](mat.WithBacking([]float32{1, 2})))
+
+	s := &recordingStrategy{}
+	err := New(nn.StreamParams([]*nn.Param{trainable, frozen, withoutGrad, noGrad}), s).Optimize()
+	assert.NoError(t, err)
+	// a parameter which was never frozen is optimized if it has gradients,
+	// even if it does not require them
+	assert.ElementsMatch(t, []*nn.Param{trainable, withoutGrad}, s.params)
+}
+
+// slowStrategy counts the optimized parameters after a delay, optionally
+// failing.
+type slowStrategy struct {
+	count atomic.Int32
+	err   error
+}
+
+func (s *slowStrategy) OptimizeParams(*nn.Param) error {
+	time.Sleep(10 * time.Millisecond)
+	s.count.Add(1)
+	return s.err
+}
+
+func newSlowTestParams(n int) []*nn.Param {
+	params := make([]*nn.Param, n)
+	for i := range params {
+		params[i] = nn.NewParam(mat.NewDense[float32](mat.WithBacking([]float32{1, 2})))
+		params[i].AccGrad(mat.NewDense[float32](mat.WithBacking([]float32{1, 1})))
+	}
+	return params
+}
+
+func TestOptimizer_Optimize_WaitsForAllParams(t *testing.T) {
+	s := &slowStrategy{}
+	err := New(nn.StreamParams(newSlowTestParams(3)), s).Optimize()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), s.count.Load())
+}
+
+func TestOptimizer_Optimize_LastParamError(t *testing.T) {
+	// the error of the last parameter occurs once the iteration is over
+	expected := errors.New("test error")
+	s := &slowStrategy{err: expected}
+	err := New(nn.StreamParams(newSlowTestParams(1)), s).Optimize()
+	assert.ErrorIs(t, err, expected)
+}
+
+type lrStrategy struct {
+	recordingStrategy
+	lr []float64
+}
+
+func (s *lrStrategy) LearningRate() float64 {
+	return s.lr[len(s.lr)-1]
+}
+
+func (s *lrStrategy) SetLearningRate(lr float64) {
+	s.lr = append(s.lr, lr)
+}
+
+func TestOptimizer_WithSchedule(t *testing.T) {
+	params := nn.StreamParams([]*nn.Param{nn.NewParam(mat.NewDense[float32](mat.WithShape(1)))})
+
+	s := &lrStrategy{}
+	opt := New(params, s).WithSchedule(schedule.StepDecay(1, 2, 0.5), PerStep)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, opt.Optimize())
+	}
+	assert.Equal(t, 5, opt.Steps())
+	assert.Equal(t, []float64{1, 1, 0.5, 0.5, 0.25}, s.lr)
+
+	s = &lrStrategy{}
+	opt = New(params, s).WithSchedule(schedule.StepDecay(1, 1, 0.5), PerEpoch)
+	for epoch := 0; epoch < 3; epoch++ {
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, opt.Optimize())
+		}
+		opt.EndEpoch()
+	}
+	assert.Equal(t, 3, opt.Epochs())
+	assert.Equal(t, []float64{1, 1, 0.5, 0.5, 0.25, 0.25}, s.lr)
+
+	assert.Panics(t, func() { New(params, &recordingStrategy{}).WithSchedule(schedule.Constant(1), PerStep) })
+}
@@ -95,6 +95,16 @@ func (o *RAdam[_]) IncBatch() {
 	o.TimeStep++
 }
 
+// LearningRate returns the step size.
+func (o *RAdam[_]) LearningRate() float64 {
+	return o.StepSize
+}
+
+// SetLearningRate sets the step size.
+func (o *RAdam[_]) SetLearningRate(lr float64) {
+	o.StepSize = lr
+}
+
 func (o *RAdam[T]) calculateParamUpdate(grads mat.Matrix, state *State) mat.Matrix {
 	updateM(grads, state, o.Beta1)
 	updateV(grads, state, o.Beta2)
@@ -54,6 +54,16 @@ func New[T float.DType](c Config) *RMSProp[T] {
 	return &RMSProp[T]{Config: c}
 }
 
+// LearningRate returns the learning rate.
+func (o *RMSProp[_]) LearningRate() float64 {
+	return o.LR
+}
+
+// SetLearningRate sets the learning rate.
+func (o *RMSProp[_]) SetLearningRate(lr float64) {
+	o.LR = lr
+}
+
 type State struct {
 	V mat.Matrix // first moment vector
 }
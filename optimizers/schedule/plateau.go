@@ -0,0 +1,185 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Mode tells whether a metric improves decreasing or increasing.
+type Mode int
+
+const (
+	// Min is the mode of the metrics improving when decreasing (e.g. a loss).
+	Min Mode = iota
+	// Max is the mode of the metrics improving when increasing (e.g. accuracy).
+	Max
+)
+
+// ReduceOnPlateau wraps a schedule, scaling its learning rate by Factor
+// each time a validation metric, reported with Observe, has not improved
+// for more than Patience observations.
+//
+// It implements Stateful: its state, and the one of the wrapped schedule if
+// Stateful too, is saved with the state of the optimizer.
+type ReduceOnPlateau struct {
+	// Schedule is the wrapped schedule.
+	Schedule Schedule
+	// Mode tells whether the metric improves decreasing or increasing.
+	Mode Mode
+	// Factor is the multiplier of the learning rate at each reduction.
+	Factor float64
+	// Patience is the number of observations without improvement tolerated
+	// before reducing the learning rate.
+	Patience int
+	// Threshold is the minimum relative change of the metric which counts
+	// as an improvement.
+	Threshold float64
+	// Cooldown is the number of observations to ignore after a reduction.
+	Cooldown int
+	// MinScale is the lower bound of the overall scale of the learning rate.
+	MinScale float64
+
+	mu       sync.Mutex
+	best     float64
+	observed bool
+	bad      int
+	cooldown int
+	scale    float64
+}
+
+var _ Stateful = &ReduceOnPlateau{}
+
+// NewReduceOnPlateau returns a new ReduceOnPlateau, with a relative
+// threshold of 1e-4, no cooldown and no lower bound.
+func NewReduceOnPlateau(s Schedule, mode Mode, factor float64, patience int) *ReduceOnPlateau {
+	if factor <= 0 || factor >= 1 {
+		panic("schedule: the reduction factor must be in the range (0, 1)")
+	}
+	return &ReduceOnPlateau{
+		Schedule:  s,
+		Mode:      mode,
+		Factor:    factor,
+		Patience:  patience,
+		Threshold: 1e-4,
+	}
+}
+
+// LR returns the learning rate of the wrapped schedule at time t,
+// multiplied by the current scale.
+func (r *ReduceOnPlateau) LR(t int) float64 {
+	return r.Scale() * r.Schedule.LR(t)
+}
+
+// Scale returns the current multiplier of the learning rate, which starts
+// from 1.
+func (r *ReduceOnPlateau) Scale() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.scale == 0 {
+		return 1
+	}
+	return r.scale
+}
+
+// Observe reports a new value of the metric, typically computed on the
+// validation set at the end of an epoch. It returns whether the learning
+// rate has been reduced.
+func (r *ReduceOnPlateau) Observe(metric float64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.scale == 0 {
+		r.scale = 1
+	}
+	if !r.observed || r.improves(metric) {
+		r.best = metric
+		r.observed = true
+		r.bad = 0
+	} else {
+		r.bad++
+	}
+	if r.cooldown > 0 {
+		r.cooldown--
+		r.bad = 0
+	}
+	if r.bad <= r.Patience {
+		return false
+	}
+	r.bad = 0
+	r.cooldown = r.Cooldown
+	scale := math.Max(r.scale*r.Factor, r.MinScale)
+	reduced := scale < r.scale
+	r.scale = scale
+	return reduced
+}
+
+func (r *ReduceOnPlateau) improves(metric float64) bool {
+	if r.Mode == Max {
+		return metric > r.best+math.Abs(r.best)*r.Threshold
+	}
+	return metric < r.best-math.Abs(r.best)*r.Threshold
+}
+
+// plateauState is the state of a ReduceOnPlateau, encoded by MarshalState.
+type plateauState struct {
+	Best     float64
+	Observed bool
+	Bad      int
+	Cooldown int
+	Scale    float64
+	Schedule []byte // state of the wrapped schedule, if Stateful
+}
+
+// MarshalState returns an encoding of the state of the schedule, that is,
+// the best metric, the observations without improvement, the remaining
+// cooldown and the current scale.
+func (r *ReduceOnPlateau) MarshalState() ([]byte, error) {
+	r.mu.Lock()
+	s := plateauState{
+		Best:     r.best,
+		Observed: r.observed,
+		Bad:      r.bad,
+		Cooldown: r.cooldown,
+		Scale:    r.scale,
+	}
+	r.mu.Unlock()
+	if inner, ok := r.Schedule.(Stateful); ok {
+		data, err := inner.MarshalState()
+		if err != nil {
+			return nil, err
+		}
+		s.Schedule = data
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return nil, fmt.Errorf("schedule: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalState restores a state encoded by MarshalState.
+func (r *ReduceOnPlateau) UnmarshalState(data []byte) error {
+	var s plateauState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	}
+	if inner, ok := r.Schedule.(Stateful); ok && len(s.Schedule) > 0 {
+		if err := inner.UnmarshalState(s.Schedule); err != nil {
+			return err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.best = s.Best
+	r.observed = s.Observed
+	r.bad = s.Bad
+	r.cooldown = s.Cooldown
+	r.scale = s.Scale
+	return nil
+}
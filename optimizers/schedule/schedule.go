@@ -0,0 +1,325 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schedule provides learning rate schedules, to be set on an
+// optimizers.Optimizer with WithSchedule.
+//
+// A Schedule computes the learning rate at a given time, counted in steps or
+// in epochs, starting from zero. Schedules are composable: LinearWarmup and
+// ReduceOnPlateau wrap another schedule, and Sequential switches from a
+// schedule to the next one at given milestones.
+package schedule
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/nlpodyssey/spago/optimizers/decay"
+)
+
+// Schedule computes the learning rate at a given time.
+type Schedule interface {
+	// LR returns the learning rate at time t, starting from 0.
+	LR(t int) float64
+}
+
+// Stateful is implemented by the schedules whose learning rate depends on a
+// state changing during the training, such as ReduceOnPlateau, and by the
+// schedules wrapping other ones, such as LinearWarmup, which delegate to the
+// wrapped schedules. The state is saved and restored together with the one
+// of the optimizer (see optimizers.Optimizer.State).
+type Stateful interface {
+	Schedule
+	// MarshalState returns an encoding of the current state.
+	MarshalState() ([]byte, error)
+	// UnmarshalState restores a state encoded by MarshalState. On errors,
+	// the schedule is left unchanged.
+	UnmarshalState(data []byte) error
+}
+
+// Func is an adapter to allow the use of ordinary functions as schedules.
+type Func func(t int) float64
+
+// LR returns f(t).
+func (f Func) LR(t int) float64 {
+	return f(t)
+}
+
+// Constant returns a schedule with a fixed learning rate.
+func Constant(lr float64) Schedule {
+	return Func(func(int) float64 { return lr })
+}
+
+// LinearWarmup returns a schedule increasing the learning rate linearly for
+// the given number of steps, from s.LR(0)/steps up to s.LR(0), and then
+// following s, shifted in time by steps.
+// It implements Stateful, saving the state of s if Stateful.
+func LinearWarmup(steps int, s Schedule) Schedule {
+	if steps < 0 {
+		panic("schedule: the warmup steps must be >= 0")
+	}
+	return &linearWarmup{steps: steps, s: s}
+}
+
+type linearWarmup struct {
+	steps int
+	s     Schedule
+}
+
+var _ Stateful = &linearWarmup{}
+
+func (w *linearWarmup) LR(t int) float64 {
+	if t < w.steps {
+		return w.s.LR(0) * float64(t+1) / float64(w.steps)
+	}
+	return w.s.LR(t - w.steps)
+}
+
+func (w *linearWarmup) MarshalState() ([]byte, error) {
+	return marshalStates([]Schedule{w.s})
+}
+
+func (w *linearWarmup) UnmarshalState(data []byte) error {
+	return unmarshalStates([]Schedule{w.s}, data)
+}
+
+// Sequential returns a schedule following schedules[0] until the first
+// milestone, schedules[1] until the second one, and so on. Each schedule
+// starts again from time 0 at its milestone.
+// It implements Stateful, saving the states of the schedules which are
+// Stateful.
+// It panics if the milestones are not one less than the schedules, or not
+// in ascending order.
+func Sequential(schedules []Schedule, milestones []int) Schedule {
+	if len(schedules) == 0 || len(milestones) != len(schedules)-1 {
+		panic("schedule: the milestones must be one less than the schedules")
+	}
+	for i := 1; i < len(milestones); i++ {
+		if milestones[i] < milestones[i-1] {
+			panic("schedule: the milestones must be in ascending order")
+		}
+	}
+	return &sequential{schedules: schedules, milestones: milestones}
+}
+
+type sequential struct {
+	schedules  []Schedule
+	milestones []int
+}
+
+var _ Stateful = &sequential{}
+
+func (s *sequential) LR(t int) float64 {
+	start := 0
+	for i, m := range s.milestones {
+		if t < m {
+			return s.schedules[i].LR(t - start)
+		}
+		start = m
+	}
+	return s.schedules[len(s.schedules)-1].LR(t - start)
+}
+
+func (s *sequential) MarshalState() ([]byte, error) {
+	return marshalStates(s.schedules)
+}
+
+func (s *sequential) UnmarshalState(data []byte) error {
+	return unmarshalStates(s.schedules, data)
+}
+
+// marshalStates encodes the states of the schedules which are Stateful, for
+// the schedules wrapping other ones.
+func marshalStates(schedules []Schedule) ([]byte, error) {
+	states := make([][]byte, len(schedules))
+	for i, s := range schedules {
+		if st, ok := s.(Stateful); ok {
+			data, err := st.MarshalState()
+			if err != nil {
+				return nil, err
+			}
+			states[i] = data
+		}
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(states); err != nil {
+		return nil, fmt.Errorf("schedule: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalStates restores the states encoded by marshalStates. On errors,
+// the schedules already restored are brought back to their previous state.
+func unmarshalStates(schedules []Schedule, data []byte) error {
+	var states [][]byte
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&states); err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	}
+	if len(states) != len(schedules) {
+		return fmt.Errorf("schedule: state of %d schedules, expected %d", len(states), len(schedules))
+	}
+	var restored []Stateful
+	var previous [][]byte
+	for i, s := range schedules {
+		st, ok := s.(Stateful)
+		if !ok || len(states[i]) == 0 {
+			continue
+		}
+		prev, err := st.MarshalState()
+		if err == nil {
+			err = st.UnmarshalState(states[i])
+		}
+		if err != nil {
+			for j, r := range restored {
+				_ = r.UnmarshalState(previous[j])
+			}
+			return err
+		}
+		restored = append(restored, st)
+		previous = append(previous, prev)
+	}
+	return nil
+}
+
+// StepDecay returns a schedule multiplying the initial learning rate lr by
+// gamma every size steps:
+//
+//	lr_t = lr * gamma^floor(t/size)
+func StepDecay(lr float64, size int, gamma float64) Schedule {
+	if size <= 0 {
+		panic("schedule: the step size must be > 0")
+	}
+	return Func(func(t int) float64 {
+		return lr * math.Pow(gamma, float64(t/size))
+	})
+}
+
+// Polynomial returns a schedule decaying the learning rate from initLR to
+// finalLR in the given number of steps, then keeping finalLR:
+//
+//	lr_t = (initLR - finalLR) * (1 - t/steps)^power + finalLR
+func Polynomial(initLR, finalLR float64, steps int, power float64) Schedule {
+	if steps <= 0 {
+		panic("schedule: the decay steps must be > 0")
+	}
+	return Func(func(t int) float64 {
+		if t >= steps {
+			return finalLR
+		}
+		return (initLR-finalLR)*math.Pow(1-float64(t)/float64(steps), power) + finalLR
+	})
+}
+
+// Cosine returns a schedule annealing the learning rate from maxLR to minLR
+// in the given number of steps, along half a cosine wave, then keeping minLR.
+func Cosine(maxLR, minLR float64, steps int) Schedule {
+	if steps <= 0 {
+		panic("schedule: the annealing steps must be > 0")
+	}
+	return Func(func(t int) float64 {
+		if t >= steps {
+			return minLR
+		}
+		return cosineAnnealing(maxLR, minLR, float64(t)/float64(steps))
+	})
+}
+
+// CosineWithRestarts returns a schedule annealing the learning rate from
+// maxLR to minLR along half a cosine wave, and restarting from maxLR at the
+// end of each cycle (SGDR). The first cycle lasts period steps, and each
+// cycle is mult times longer than the previous one.
+//
+// Reference: "SGDR: Stochastic Gradient Descent with Warm Restarts"
+// (Loshchilov and Hutter, 2017).
+func CosineWithRestarts(maxLR, minLR float64, period int, mult float64) Schedule {
+	if period <= 0 {
+		panic("schedule: the period must be > 0")
+	}
+	if mult < 1 {
+		panic("schedule: the period multiplier must be >= 1")
+	}
+	return Func(func(t int) float64 {
+		length := period
+		for t >= length {
+			t -= length
+			length = int(math.Round(float64(length) * mult))
+		}
+		return cosineAnnealing(maxLR, minLR, float64(t)/float64(length))
+	})
+}
+
+// OneCycle returns the one-cycle schedule, which anneals the learning rate
+// from maxLR/divFactor up to maxLR during the first pctStart fraction of
+// the steps, then down to maxLR/(divFactor*finalDivFactor) at the last
+// step, along cosine waves. Common values are pctStart = 0.3,
+// divFactor = 25 and finalDivFactor = 1e4.
+//
+// Reference: "Super-Convergence: Very Fast Training of Neural Networks Using
+// Large Learning Rates" (Smith and Topin, 2018).
+func OneCycle(maxLR float64, steps int, pctStart, divFactor, finalDivFactor float64) Schedule {
+	if steps <= 1 {
+		panic("schedule: the total steps must be > 1")
+	}
+	if pctStart <= 0 || pctStart >= 1 {
+		panic("schedule: pctStart must be in the range (0, 1)")
+	}
+	initLR := maxLR / divFactor
+	minLR := initLR / finalDivFactor
+	peak := math.Max(1, math.Round(pctStart*float64(steps))-1)
+	last := float64(steps - 1)
+	return Func(func(t int) float64 {
+		x := float64(t)
+		switch {
+		case x <= peak:
+			return cosineAnnealing(initLR, maxLR, x/peak)
+		case x < last:
+			return cosineAnnealing(maxLR, minLR, (x-peak)/(last-peak))
+		default:
+			return minLR
+		}
+	})
+}
+
+// cosineAnnealing interpolates from a to b along half a cosine wave, with
+// the progress p in [0, 1].
+func cosineAnnealing(a, b, p float64) float64 {
+	return b + (a-b)*(1+math.Cos(math.Pi*p))/2
+}
+
+// FromDecay returns a schedule driven by a decay.Function, starting from the
+// learning rate lr. Since the decay functions are iterative, the learning
+// rate at time t is computed applying fn t+1 times (with t from 1); the last
+// value is cached, so that the schedule is efficient when t increases
+// steadily.
+func FromDecay(lr float64, fn decay.Function) Schedule {
+	return &decaySchedule{initLR: lr, fn: fn, t: -1}
+}
+
+type decaySchedule struct {
+	mu     sync.Mutex
+	initLR float64
+	fn     decay.Function
+	t      int     // time of the cached value
+	lr     float64 // cached value
+}
+
+func (s *decaySchedule) LR(t int) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t < s.t {
+		s.t, s.lr = -1, 0
+	}
+	for s.t < t {
+		lr := s.initLR
+		if s.t >= 0 {
+			lr = s.lr
+		}
+		s.t++
+		s.lr = s.fn.Decay(lr, s.t+1)
+	}
+	return s.lr
+}
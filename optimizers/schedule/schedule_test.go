@@ -0,0 +1,175 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/optimizers/decay/hyperbolic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lrs(s Schedule, n int) []float64 {
+	out := make([]float64, n)
+	for t := range out {
+		out[t] = s.LR(t)
+	}
+	return out
+}
+
+func TestConstant(t *testing.T) {
+	assert.Equal(t, []float64{0.1, 0.1, 0.1}, lrs(Constant(0.1), 3))
+}
+
+func TestLinearWarmup(t *testing.T) {
+	s := LinearWarmup(4, StepDecay(1, 2, 0.5))
+	assert.InDeltaSlice(t, []float64{0.25, 0.5, 0.75, 1, 1, 1, 0.5, 0.5, 0.25}, lrs(s, 9), 1e-12)
+	assert.Equal(t, []float64{1, 1}, lrs(LinearWarmup(0, Constant(1)), 2))
+}
+
+func TestSequential(t *testing.T) {
+	s := Sequential([]Schedule{Constant(1), StepDecay(0.5, 1, 0.5), Constant(0.01)}, []int{2, 4})
+	assert.Equal(t, []float64{1, 1, 0.5, 0.25, 0.01, 0.01}, lrs(s, 6))
+	assert.Panics(t, func() { Sequential([]Schedule{Constant(1)}, []int{2}) })
+	assert.Panics(t, func() { Sequential([]Schedule{Constant(1), Constant(1), Constant(1)}, []int{3, 2}) })
+}
+
+func TestStepDecay(t *testing.T) {
+	assert.InDeltaSlice(t, []float64{0.1, 0.1, 0.1, 0.01, 0.01, 0.01, 0.001}, lrs(StepDecay(0.1, 3, 0.1), 7), 1e-12)
+}
+
+func TestPolynomial(t *testing.T) {
+	assert.InDeltaSlice(t, []float64{1, 0.5625, 0.25, 0.0625, 0, 0}, lrs(Polynomial(1, 0, 4, 2), 6), 1e-12)
+	assert.InDeltaSlice(t, []float64{0.1, 0.075, 0.05, 0.025, 0}, lrs(Polynomial(0.1, 0, 4, 1), 5), 1e-12)
+}
+
+func TestCosine(t *testing.T) {
+	assert.InDeltaSlice(t, []float64{1, 0.853553, 0.5, 0.146447, 0, 0}, lrs(Cosine(1, 0, 4), 6), 1e-6)
+}
+
+func TestCosineWithRestarts(t *testing.T) {
+	s := CosineWithRestarts(1, 0, 2, 2)
+	// cycles of 2, 4 and 8 steps
+	assert.InDeltaSlice(t, []float64{
+		1, 0.5,
+		1, 0.853553, 0.5, 0.146447,
+		1, 0.961940,
+	}, lrs(s, 8), 1e-6)
+	assert.InDeltaSlice(t, []float64{1, 0.5, 1, 0.5}, lrs(CosineWithRestarts(1, 0, 2, 1), 4), 1e-12)
+	assert.Panics(t, func() { CosineWithRestarts(1, 0, 2, 0.5) })
+}
+
+func TestOneCycle(t *testing.T) {
+	s := OneCycle(1, 10, 0.3, 10, 100)
+	values := lrs(s, 12)
+	assert.InDelta(t, 0.1, values[0], 1e-12)
+	assert.InDelta(t, 0.55, values[1], 1e-12)
+	assert.InDelta(t, 1, values[2], 1e-12)
+	for i := 3; i < 10; i++ {
+		assert.Less(t, values[i], values[i-1])
+	}
+	assert.InDelta(t, 0.001, values[9], 1e-12)
+	assert.InDelta(t, 0.001, values[11], 1e-12)
+}
+
+func TestFromDecay(t *testing.T) {
+	fn := hyperbolic.New(1, 0.1, 1)
+	s := FromDecay(1, fn)
+	expected := []float64{1, 1.0 / 3, 0.25, 0.2}
+	assert.InDeltaSlice(t, expected, lrs(s, 4), 1e-12)
+	// going back in time recomputes the values
+	assert.InDelta(t, 1.0/3, s.LR(1), 1e-12)
+	assert.InDelta(t, 0.2, s.LR(3), 1e-12)
+}
+
+func TestReduceOnPlateau(t *testing.T) {
+	r := NewReduceOnPlateau(Constant(1), Min, 0.5, 1)
+	r.Cooldown = 1
+	r.MinScale = 0.2
+
+	reductions := []bool{}
+	for _, loss := range []float64{3, 2, 2.5, 2.1, 2.2, 2.3, 2.4, 1, 1.5, 1.2, 1.1, 1.3, 1.4} {
+		reductions = append(reductions, r.Observe(loss))
+	}
+	assert.Equal(t, []bool{
+		false, false, false, true, // 2 epochs without improvement
+		false, false, true, // cooldown, then 2 epochs without improvement
+		false, false, true, // the scale reaches its minimum
+		false, false, false, // the scale is already at its minimum
+	}, reductions)
+	assert.InDelta(t, 0.2, r.LR(0), 1e-12)
+
+	acc := NewReduceOnPlateau(Constant(0.1), Max, 0.1, 0)
+	assert.False(t, acc.Observe(0.5))
+	assert.False(t, acc.Observe(0.6))
+	assert.True(t, acc.Observe(0.6))
+	assert.InDelta(t, 0.01, acc.LR(5), 1e-12)
+	assert.Panics(t, func() { NewReduceOnPlateau(Constant(1), Min, 1, 0) })
+}
+
+func TestReduceOnPlateau_State(t *testing.T) {
+	newSchedule := func() *ReduceOnPlateau {
+		r := NewReduceOnPlateau(Constant(1), Min, 0.5, 1)
+		r.Cooldown = 1
+		return r
+	}
+	losses := []float64{3, 2, 2.5, 2.1, 2.2, 2.3, 2.4, 1, 1.5}
+
+	r := newSchedule()
+	for _, loss := range losses[:5] {
+		r.Observe(loss)
+	}
+	data, err := r.MarshalState()
+	require.NoError(t, err)
+
+	resumed := newSchedule()
+	require.NoError(t, resumed.UnmarshalState(data))
+	assert.Equal(t, r.Scale(), resumed.Scale())
+	for _, loss := range losses[5:] {
+		assert.Equal(t, r.Observe(loss), resumed.Observe(loss))
+		assert.Equal(t, r.LR(0), resumed.LR(0))
+	}
+
+	assert.Error(t, resumed.UnmarshalState([]byte("invalid")))
+	assert.Equal(t, r.Scale(), resumed.Scale())
+}
+
+func TestCombinators_State(t *testing.T) {
+	newPlateau := func() *ReduceOnPlateau { return NewReduceOnPlateau(Constant(1), Min, 0.5, 0) }
+	tests := []struct {
+		name  string
+		build func(r *ReduceOnPlateau) Schedule
+	}{
+		{"LinearWarmup", func(r *ReduceOnPlateau) Schedule { return LinearWarmup(2, r) }},
+		{"Sequential", func(r *ReduceOnPlateau) Schedule {
+			return Sequential([]Schedule{Constant(0.1), LinearWarmup(1, r)}, []int{2})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newPlateau()
+			s := tt.build(r)
+			for _, loss := range []float64{2, 1, 1.5, 1.7} {
+				r.Observe(loss)
+			}
+			require.Equal(t, 0.25, r.Scale())
+			data, err := s.(Stateful).MarshalState()
+			require.NoError(t, err)
+
+			r2 := newPlateau()
+			resumed := tt.build(r2)
+			require.NoError(t, resumed.(Stateful).UnmarshalState(data))
+			assert.Equal(t, 0.25, r2.Scale())
+			assert.Equal(t, lrs(s, 6), lrs(resumed, 6))
+
+			assert.Error(t, resumed.(Stateful).UnmarshalState([]byte("invalid")))
+			other, err := Sequential([]Schedule{Constant(1), Constant(1), Constant(1)}, []int{1, 2}).(Stateful).MarshalState()
+			require.NoError(t, err)
+			assert.Error(t, resumed.(Stateful).UnmarshalState(other), "the number of schedules differs")
+			assert.Equal(t, 0.25, r2.Scale())
+		})
+	}
+}
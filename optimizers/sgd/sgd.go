@@ -42,6 +42,16 @@ func New[T float.DType](c Config) *SGD[T] {
 	return &SGD[T]{Config: c, Alpha: c.LR}
 }
 
+// LearningRate returns the current learning rate.
+func (o *SGD[_]) LearningRate() float64 {
+	return o.Alpha
+}
+
+// SetLearningRate sets the current learning rate.
+func (o *SGD[_]) SetLearningRate(lr float64) {
+	o.Alpha = lr
+}
+
 type State struct {
 	V     mat.Matrix // velocity
 	Buf   mat.Matrix // buffer
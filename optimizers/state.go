@@ -0,0 +1,375 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimizers
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/optimizers/schedule"
+)
+
+// State is a snapshot of the state of an optimizer, allowing a training to
+// be resumed without restarting the estimates of the optimization strategy
+// (e.g. the moments of Adam) from zero.
+//
+// It is serialized with gob, so the type of the state of the parameters
+// must be registered with gob.Register, as done by all the strategies of
+// this module.
+type State struct {
+	// Strategy holds the scalar fields of the strategy (e.g. TimeStep and
+	// Alpha), keyed by field name. The embedded configuration is not part
+	// of the state, apart from the learning rate (see LearningRates).
+	Strategy map[string]any
+	// Groups holds the scalar fields of the strategy of each parameter
+	// group (see NewWithGroups), in order.
+	Groups []map[string]any
+	// Params holds a copy of the nn.Param.State of each parameter having
+	// one, keyed by its path within the model (see nn.NamedParameters).
+	Params map[string]any
+	// Steps and Epochs are the counters of the optimizer, which drive the
+	// learning rate schedule, if any.
+	Steps, Epochs int
+	// LearningRates holds the current learning rate of the strategy and of
+	// the strategy of each group, in order, as returned by
+	// LearningRateStrategy, or 0 for the strategies not implementing it.
+	LearningRates []float64
+	// Schedules holds the state of the schedule of the strategy and of the
+	// schedule of each group, in order, for the schedules implementing
+	// schedule.Stateful (e.g. schedule.ReduceOnPlateau), or nil.
+	Schedules [][]byte
+	// Strategies holds the state of the strategy and of the strategy of each
+	// group, in order, for the strategies implementing StatefulStrategy, or
+	// nil.
+	Strategies [][]byte
+}
+
+// State returns a snapshot of the state of the optimizer, whose
+// parameters belong to the model m.
+func (o *Optimizer) State(m nn.Model) (*State, error) {
+	s := &State{
+		Strategy: strategyState(o.strategy),
+		Params:   make(map[string]any),
+		Steps:    o.steps,
+		Epochs:   o.epochs,
+	}
+	for _, g := range o.groups {
+		s.Groups = append(s.Groups, strategyState(g.Strategy))
+	}
+	for _, strategy := range o.strategies() {
+		lr := 0.0
+		if ls, ok := strategy.(LearningRateStrategy); ok {
+			lr = ls.LearningRate()
+		}
+		s.LearningRates = append(s.LearningRates, lr)
+	}
+	for _, sched := range o.schedules() {
+		var data []byte
+		if st, ok := sched.(schedule.Stateful); ok {
+			var err error
+			if data, err = st.MarshalState(); err != nil {
+				return nil, fmt.Errorf("optimizers: %w", err)
+			}
+		}
+		s.Schedules = append(s.Schedules, data)
+	}
+	for _, strategy := range o.strategies() {
+		var data []byte
+		if st, ok := strategy.(StatefulStrategy); ok {
+			var err error
+			if data, err = st.MarshalState(); err != nil {
+				return nil, fmt.Errorf("optimizers: %w", err)
+			}
+		}
+		s.Strategies = append(s.Strategies, data)
+	}
+	for _, p := range nn.NamedParameters(m) {
+		if p.Param.State != nil {
+			s.Params[p.Name] = cloneParamState(p.Param.State)
+		}
+	}
+	return s, nil
+}
+
+// SetState restores a snapshot of the state of the optimizer, made by
+// State, on the parameters of the model m. The parameters missing from the
+// snapshot have their state reset. It returns an error if the snapshot
+// refers to parameters or fields which do not exist, or whose type does not
+// match; in that case, the optimizer and the model are left unchanged.
+func (o *Optimizer) SetState(m nn.Model, s *State) error {
+	if len(s.Groups) != len(o.groups) {
+		return fmt.Errorf("optimizers: unexpected number of parameter groups %d, expected %d", len(s.Groups), len(o.groups))
+	}
+	if err := checkStrategyState(o.strategy, s.Strategy); err != nil {
+		return fmt.Errorf("optimizers: %w", err)
+	}
+	for i, g := range o.groups {
+		if err := checkStrategyState(g.Strategy, s.Groups[i]); err != nil {
+			return fmt.Errorf("optimizers: group %d: %w", i, err)
+		}
+	}
+	if len(s.LearningRates) != len(o.groups)+1 || len(s.Schedules) != len(o.groups)+1 ||
+		len(s.Strategies) != len(o.groups)+1 {
+		return fmt.Errorf("optimizers: unexpected number of learning rates, schedules or strategies")
+	}
+	for i, sched := range o.schedules() {
+		if _, ok := sched.(schedule.Stateful); !ok && len(s.Schedules[i]) > 0 {
+			return fmt.Errorf("optimizers: state of schedule %d, which is not stateful", i)
+		}
+	}
+	for i, strategy := range o.strategies() {
+		if _, ok := strategy.(StatefulStrategy); !ok && len(s.Strategies[i]) > 0 {
+			return fmt.Errorf("optimizers: state of strategy %d, which is not stateful", i)
+		}
+	}
+	params := make(map[string]*nn.Param)
+	for _, p := range nn.NamedParameters(m) {
+		params[p.Name] = p.Param
+	}
+	for _, name := range sortedKeys(s.Params) {
+		state := s.Params[name]
+		p, ok := params[name]
+		if !ok {
+			return fmt.Errorf("optimizers: state of unknown parameter %q", name)
+		}
+		if err := checkParamState(p, state); err != nil {
+			return fmt.Errorf("optimizers: state of parameter %q: %w", name, err)
+		}
+	}
+
+	var schedules, strategies []stateful
+	for _, sched := range o.schedules() {
+		st, _ := sched.(stateful)
+		schedules = append(schedules, st)
+	}
+	for _, strategy := range o.strategies() {
+		st, _ := strategy.(stateful)
+		strategies = append(strategies, st)
+	}
+	restored, err := setStates(schedules, s.Schedules, "schedule")
+	if err != nil {
+		return fmt.Errorf("optimizers: %w", err)
+	}
+	if _, err := setStates(strategies, s.Strategies, "strategy"); err != nil {
+		restored()
+		return fmt.Errorf("optimizers: %w", err)
+	}
+	setStrategyState(o.strategy, s.Strategy)
+	for i, g := range o.groups {
+		setStrategyState(g.Strategy, s.Groups[i])
+	}
+	for i, strategy := range o.strategies() {
+		if ls, ok := strategy.(LearningRateStrategy); ok {
+			ls.SetLearningRate(s.LearningRates[i])
+		}
+	}
+	o.steps, o.epochs = s.Steps, s.Epochs
+	for name, p := range params {
+		if state, ok := s.Params[name]; ok {
+			p.State = cloneParamState(state)
+		} else {
+			p.State = nil
+		}
+	}
+	return nil
+}
+
+// SaveState writes the state of the optimizer, whose parameters belong to
+// the model m, to w.
+func (o *Optimizer) SaveState(w io.Writer, m nn.Model) error {
+	s, err := o.State(m)
+	if err != nil {
+		return err
+	}
+	return nn.Dump(s, w)
+}
+
+// LoadState reads the state of the optimizer from r, and restores it on the
+// parameters of the model m. See SetState.
+func (o *Optimizer) LoadState(r io.Reader, m nn.Model) error {
+	s, err := nn.Load[*State](r)
+	if err != nil {
+		return err
+	}
+	return o.SetState(m, s)
+}
+
+// strategies returns the strategy of the optimizer, followed by the ones of
+// the groups.
+func (o *Optimizer) strategies() []OptimizationStrategy {
+	out := []OptimizationStrategy{o.strategy}
+	for _, g := range o.groups {
+		out = append(out, g.Strategy)
+	}
+	return out
+}
+
+// schedules returns the schedule of the optimizer, followed by the ones of
+// the groups.
+func (o *Optimizer) schedules() []schedule.Schedule {
+	out := []schedule.Schedule{o.schedule}
+	for _, g := range o.groups {
+		out = append(out, g.Schedule)
+	}
+	return out
+}
+
+// stateful is the common interface of schedule.Stateful and
+// StatefulStrategy.
+type stateful interface {
+	MarshalState() ([]byte, error)
+	UnmarshalState(data []byte) error
+}
+
+// setStates restores the states of the targets, already checked against
+// them; nil targets and empty states are skipped. On errors, the targets
+// already restored are brought back to their previous state; otherwise,
+// the returned function does so.
+func setStates(targets []stateful, states [][]byte, kind string) (undo func(), err error) {
+	var restored []stateful
+	var previous [][]byte
+	undo = func() {
+		for j, r := range restored {
+			_ = r.UnmarshalState(previous[j])
+		}
+	}
+	for i, st := range targets {
+		if st == nil || len(states[i]) == 0 {
+			continue
+		}
+		prev, err := st.MarshalState()
+		if err == nil {
+			err = st.UnmarshalState(states[i])
+		}
+		if err != nil {
+			undo()
+			return nil, fmt.Errorf("%s %d: %w", kind, i, err)
+		}
+		restored = append(restored, st)
+		previous = append(previous, prev)
+	}
+	return undo, nil
+}
+
+// strategyState returns the scalar fields of the strategy.
+func strategyState(strategy OptimizationStrategy) map[string]any {
+	state := make(map[string]any)
+	forEachScalarField(strategy, func(name string, field reflect.Value) {
+		state[name] = field.Interface()
+	})
+	return state
+}
+
+// checkStrategyState returns an error if the state refers to fields of the
+// strategy which do not exist, or whose type does not match.
+func checkStrategyState(strategy OptimizationStrategy, state map[string]any) error {
+	fields := make(map[string]reflect.Value)
+	forEachScalarField(strategy, func(name string, field reflect.Value) {
+		fields[name] = field
+	})
+	for _, name := range sortedKeys(state) {
+		v := state[name]
+		field, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unknown strategy field %q", name)
+		}
+		if reflect.TypeOf(v) != field.Type() {
+			return fmt.Errorf("strategy field %q: unexpected type %T, expected %v", name, v, field.Type())
+		}
+	}
+	return nil
+}
+
+// setStrategyState sets the scalar fields of the strategy, already checked
+// with checkStrategyState.
+func setStrategyState(strategy OptimizationStrategy, state map[string]any) {
+	forEachScalarField(strategy, func(name string, field reflect.Value) {
+		if v, ok := state[name]; ok {
+			field.Set(reflect.ValueOf(v))
+		}
+	})
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// forEachScalarField calls fn for each exported, non-embedded field of
+// the strategy having a boolean or numeric type.
+func forEachScalarField(strategy OptimizationStrategy, fn func(name string, field reflect.Value)) {
+	v := reflect.ValueOf(strategy)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	v = v.Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			fn(f.Name, v.Field(i))
+		}
+	}
+}
+
+// cloneParamState returns a copy of the state of a parameter. A pointer to
+// a struct is copied, together with the matrices of its exported fields;
+// any other state is returned as it is.
+func cloneParamState(state any) any {
+	v := reflect.ValueOf(state)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return state
+	}
+	out := reflect.New(v.Elem().Type())
+	out.Elem().Set(v.Elem())
+	t := v.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		field := out.Elem().Field(i)
+		if m, ok := field.Interface().(mat.Matrix); ok && !field.IsNil() {
+			field.Set(reflect.ValueOf(m.Clone()))
+		}
+	}
+	return out.Interface()
+}
+
+// checkParamState returns an error if the matrices of the state of a
+// parameter do not have the same data type of the parameter.
+func checkParamState(p *nn.Param, state any) error {
+	v := reflect.ValueOf(state)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		m, ok := v.Field(i).Interface().(mat.Matrix)
+		if !ok || v.Field(i).IsNil() {
+			continue
+		}
+		if m.Data().BitSize() != p.Data().BitSize() {
+			return fmt.Errorf("field %s: unexpected data type", v.Type().Field(i).Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright 2024 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimizers_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/embedding"
+	"github.com/nlpodyssey/spago/nn/linear"
+	"github.com/nlpodyssey/spago/optimizers"
+	"github.com/nlpodyssey/spago/optimizers/adabelief"
+	"github.com/nlpodyssey/spago/optimizers/adadelta"
+	"github.com/nlpodyssey/spago/optimizers/adafactor"
+	"github.com/nlpodyssey/spago/optimizers/adagrad"
+	"github.com/nlpodyssey/spago/optimizers/adam"
+	"github.com/nlpodyssey/spago/optimizers/lamb"
+	"github.com/nlpodyssey/spago/optimizers/lion"
+	"github.com/nlpodyssey/spago/optimizers/nadam"
+	"github.com/nlpodyssey/spago/optimizers/radam"
+	"github.com/nlpodyssey/spago/optimizers/rmsprop"
+	"github.com/nlpodyssey/spago/optimizers/schedule"
+	"github.com/nlpodyssey/spago/optimizers/sgd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ optimizers.LearningRateStrategy = &adabelief.AdaBelief[float32]{}
+	_ optimizers.LearningRateStrategy = &adadelta.AdaDelta[float32]{}
+	_ optimizers.LearningRateStrategy = &adafactor.Adafactor[float32]{}
+	_ optimizers.LearningRateStrategy = &adagrad.AdaGrad[float32]{}
+	_ optimizers.LearningRateStrategy = &adam.Adam{}
+	_ optimizers.LearningRateStrategy = &lamb.Lamb[float32]{}
+	_ optimizers.LearningRateStrategy = &lion.Lion[float32]{}
+	_ optimizers.LearningRateStrategy = &nadam.NAdam[float32]{}
+	_ optimizers.LearningRateStrategy = &radam.RAdam[float32]{}
+	_ optimizers.LearningRateStrategy = &rmsprop.RMSProp[float32]{}
+	_ optimizers.LearningRateStrategy = &sgd.SGD[float32]{}
+)
+
+// stepper is a strategy together with its per-step bookkeeping.
+type stepper struct {
+	strategy optimizers.OptimizationStrategy
+	inc      func()
+}
+
+func TestOptimizer_SaveLoadState(t *testing.T) {
+	strategies := map[string]func() stepper{
+		"adafactor": func() stepper {
+			return stepper{adafactor.New[float32](adafactor.NewDefaultConfig()), func() {}}
+		},
+		"adam": func() stepper {
+			s := adam.New(adam.NewDefaultConfig())
+			return stepper{s, s.IncExample}
+		},
+		"nadam": func() stepper {
+			return stepper{nadam.New[float32](nadam.NewDefaultConfig()), func() {}}
+		},
+		"radam": func() stepper {
+			s := radam.New[float32](radam.NewDefaultConfig())
+			return stepper{s, s.IncBatch}
+		},
+		"sgd": func() stepper {
+			s := sgd.New[float32](sgd.NewConfig(0.1, 0.9, true))
+			return stepper{s, func() {}}
+		},
+	}
+	for name, newStepper := range strategies {
+		t.Run(name, func(t *testing.T) {
+			m := newStateTestModel()
+			s := newStepper()
+			opt := optimizers.New(nn.Parameters(m), s.strategy)
+			for i := 0; i < 3; i++ {
+				stateTestStep(t, m, opt, s, i)
+			}
+
+			var buf bytes.Buffer
+			require.NoError(t, opt.SaveState(&buf, m))
+			resumed := nn.Clone(m)
+
+			for i := 3; i < 6; i++ {
+				stateTestStep(t, m, opt, s, i)
+			}
+
+			s2 := newStepper()
+			opt2 := optimizers.New(nn.Parameters(resumed), s2.strategy)
+			require.NoError(t, opt2.LoadState(&buf, resumed))
+			for i := 3; i < 6; i++ {
+				stateTestStep(t, resumed, opt2, s2, i)
+			}
+
+			assert.Equal(t, nn.StateDict(m), nn.StateDict(resumed))
+			assert.Equal(t, mustState(t, opt, m), mustState(t, opt2, resumed))
+		})
+	}
+}
+
+func TestOptimizer_SetState_Errors(t *testing.T) {
+	m := newStateTestModel()
+	s := adam.New(adam.NewDefaultConfig())
+	opt := optimizers.New(nn.Parameters(m), s)
+	stateTestStep(t, m, opt, stepper{s, s.IncExample}, 0)
+	state := mustState(t, opt, m)
+
+	other := linear.New[float32](3, 2)
+	assert.EqualError(t, opt.SetState(other, state), `optimizers: state of unknown parameter "Layers.0.B"`)
+
+	state.Strategy["TimeStep"] = 1.5
+	assert.EqualError(t, opt.SetState(m, state),
+		`optimizers: strategy field "TimeStep": unexpected type float64, expected int`)
+
+	state = mustState(t, opt, m)
+	m64 := newStateTestModel()
+	nn.ConvertDType[float64](m64)
+	assert.Error(t, opt.SetState(m64, state))
+
+	state = mustState(t, opt, m)
+	state.Schedules[0] = []byte{1}
+	assert.EqualError(t, opt.SetState(m, state), "optimizers: state of schedule 0, which is not stateful")
+
+	// on errors the optimizer is left unchanged
+	assert.Equal(t, 2, s.TimeStep)
+}
+
+func TestOptimizer_SaveLoadState_LearningRate(t *testing.T) {
+	// these strategies keep the learning rate in their embedded Config
+	strategies := map[string]func() optimizers.LearningRateStrategy{
+		"adafactor": func() optimizers.LearningRateStrategy { return adafactor.New[float32](adafactor.NewDefaultConfig()) },
+		"adam":      func() optimizers.LearningRateStrategy { return adam.New(adam.NewDefaultConfig()) },
+		"lion":      func() optimizers.LearningRateStrategy { return lion.New[float32](lion.NewDefaultConfig()) },
+		"nadam":     func() optimizers.LearningRateStrategy { return nadam.New[float32](nadam.NewDefaultConfig()) },
+	}
+	for name, newStrategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			m := newStateTestModel()
+			s := newStrategy()
+			s.SetLearningRate(0.0123)
+			opt := optimizers.New(nn.Parameters(m), s)
+
+			var buf bytes.Buffer
+			require.NoError(t, opt.SaveState(&buf, m))
+			s2 := newStrategy()
+			require.NotEqual(t, 0.0123, s2.LearningRate())
+			require.NoError(t, optimizers.New(nn.Parameters(m), s2).LoadState(&buf, m))
+			assert.Equal(t, 0.0123, s2.LearningRate())
+		})
+	}
+}
+
+func TestOptimizer_SaveLoadState_Schedule(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(s schedule.Schedule) schedule.Schedule
+	}{
+		{"ReduceOnPlateau", func(s schedule.Schedule) schedule.Schedule { return s }},
+		{"LinearWarmup", func(s schedule.Schedule) schedule.Schedule { return schedule.LinearWarmup(2, s) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newOptimizer := func(m nn.Model) (*optimizers.Optimizer, *schedule.ReduceOnPlateau, *sgd.SGD[float32]) {
+				s := sgd.New[float32](sgd.NewConfig(0.1, 0, false))
+				plateau := schedule.NewReduceOnPlateau(schedule.Constant(0.1), schedule.Min, 0.5, 0)
+				opt := optimizers.New(nn.Parameters(m), s).WithSchedule(tt.wrap(plateau), optimizers.PerEpoch)
+				return opt, plateau, s
+			}
+
+			m := newStateTestModel()
+			opt, plateau, s := newOptimizer(m)
+			for i, loss := range []float64{2, 1, 1.5, 1.7} {
+				stateTestStep(t, m, opt, stepper{s, func() {}}, i)
+				opt.EndEpoch()
+				plateau.Observe(loss)
+			}
+			require.Equal(t, 0.25, plateau.Scale())
+
+			var buf bytes.Buffer
+			require.NoError(t, opt.SaveState(&buf, m))
+			resumed := nn.Clone(m)
+			opt2, plateau2, s2 := newOptimizer(resumed)
+			require.NoError(t, opt2.LoadState(&buf, resumed))
+			assert.Equal(t, 0.25, plateau2.Scale())
+
+			for i, loss := range []float64{1.1, 0.5, 0.6} {
+				assert.Equal(t, plateau.Observe(loss), plateau2.Observe(loss))
+				stateTestStep(t, m, opt, stepper{s, func() {}}, 4+i)
+				stateTestStep(t, resumed, opt2, stepper{s2, func() {}}, 4+i)
+			}
+			assert.Equal(t, plateau.Scale(), plateau2.Scale())
+			assert.Equal(t, nn.StateDict(m), nn.StateDict(resumed))
+		})
+	}
+}
+
+func TestOptimizer_SaveLoadState_Strategy(t *testing.T) {
+	type model struct {
+		nn.Module
+		Emb *embedding.Model
+	}
+	newOptimizer := func(m *model) *optimizers.Optimizer {
+		s := adafactor.New[float32](adafactor.NewDefaultConfig()).FactorEmbeddings(m.Emb)
+		return optimizers.New(nn.Parameters(m), s)
+	}
+	step := func(m *model, opt *optimizers.Optimizer, i int) {
+		e := m.Emb.MustEncode([]int{i % 3})[0]
+		e.AccGrad(mat.NewDense[float32](mat.WithBacking([]float32{float32(i%4)*0.3 - 0.5, 0.2})))
+		require.NoError(t, opt.Optimize())
+	}
+
+	m := &model{Emb: embedding.New[float32](3, 2)}
+	opt := newOptimizer(m)
+	for i := 0; i < 4; i++ {
+		step(m, opt, i)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, opt.SaveState(&buf, m))
+	resumed := nn.Clone(m)
+	opt2 := newOptimizer(resumed)
+	require.NoError(t, opt2.LoadState(&buf, resumed))
+
+	for i := 4; i < 8; i++ {
+		step(m, opt, i)
+		step(resumed, opt2, i)
+	}
+	assert.Equal(t, nn.StateDict(m), nn.StateDict(resumed))
+
+	// a state of the strategy cannot be restored on a strategy without state
+	state := mustState(t, opt, m)
+	sgdOpt := optimizers.New(nn.Parameters(m), sgd.New[float32](sgd.NewConfig(0.1, 0, false)))
+	state.Strategy = nil
+	assert.EqualError(t, sgdOpt.SetState(m, state), "optimizers: state of strategy 0, which is not stateful")
+}
+
+func mustState(t *testing.T, opt *optimizers.Optimizer, m nn.Model) *optimizers.State {
+	t.Helper()
+	s, err := opt.State(m)
+	require.NoError(t, err)
+	return s
+}
+
+type stateTestModel struct {
+	nn.Module
+	Layers []*linear.Model
+}
+
+func newStateTestModel() *stateTestModel {
+	m := &stateTestModel{Layers: []*linear.Model{linear.New[float32](3, 2), linear.New[float32](2, 1)}}
+	i := 0
+	for _, p := range nn.NamedParameters(m) {
+		data := make([]float32, p.Param.Size())
+		for j := range data {
+			data[j] = float32(i%5)*0.1 - 0.2
+			i++
+		}
+		p.Param.SetData(mat.NewDense[float32](mat.WithBacking(data)).Data())
+	}
+	return m
+}
+
+// stateTestStep sets deterministic gradients, depending on the step, and
+// optimizes the parameters.
+func stateTestStep(t *testing.T, m nn.Model, opt *optimizers.Optimizer, s stepper, step int) {
+	i := 0
+	for _, p := range nn.NamedParameters(m) {
+		grads := make([]float32, p.Param.Size())
+		for j := range grads {
+			grads[j] = float32((i+step)%7)*0.3 - 0.9
+			i++
+		}
+		p.Param.AccGrad(mat.NewDense[float32](mat.WithShape(p.Param.Shape()...), mat.WithBacking(grads)))
+	}
+	require.NoError(t, opt.Optimize())
+	s.inc()
+}